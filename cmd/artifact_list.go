@@ -31,6 +31,8 @@ func newArtifactListCmd() *cobra.Command {
 				ID           string `json:"id"`
 				AgentVersion int    `json:"agent_version"`
 				Score        *int   `json:"score,omitempty"`
+				Harness      *int   `json:"harness_score,omitempty"`
+				Composite    *int   `json:"composite_score,omitempty"`
 				CreatedAt    string `json:"created_at"`
 			}
 
@@ -46,6 +48,14 @@ func newArtifactListCmd() *cobra.Command {
 						score := artifact.Evaluation.Score
 						summary.Score = &score
 					}
+					if artifact.HarnessResult != nil {
+						harnessScore := artifact.HarnessResult.NormalizedScore()
+						summary.Harness = &harnessScore
+					}
+					if artifact.CompositeScore != nil {
+						composite := artifact.CompositeScore.Normalized
+						summary.Composite = &composite
+					}
 					summaries = append(summaries, summary)
 				}
 			}
@@ -55,20 +65,18 @@ func newArtifactListCmd() *cobra.Command {
 			}
 
 			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
-			if _, err := fmt.Fprintln(tw, "ID\tAgent Version\tScore\tCreated At"); err != nil {
+			if _, err := fmt.Fprintln(tw, "ID\tAgent Version\tScore\tHarness\tComposite\tCreated At"); err != nil {
 				return fmt.Errorf("write artifact table header: %w", err)
 			}
 			for _, summary := range summaries {
-				score := "-"
-				if summary.Score != nil {
-					score = strconv.Itoa(*summary.Score)
-				}
 				if _, err := fmt.Fprintf(
 					tw,
-					"%s\t%d\t%s\t%s\n",
+					"%s\t%d\t%s\t%s\t%s\t%s\n",
 					summary.ID,
 					summary.AgentVersion,
-					score,
+					optionalScore(summary.Score),
+					optionalScore(summary.Harness),
+					optionalScore(summary.Composite),
 					summary.CreatedAt,
 				); err != nil {
 					return fmt.Errorf("write artifact table row %q: %w", summary.ID, err)
@@ -89,3 +97,10 @@ func agentVersionForArtifact(lineage state.Lineage, agentID string) int {
 	}
 	return 0
 }
+
+func optionalScore(score *int) string {
+	if score == nil {
+		return "-"
+	}
+	return strconv.Itoa(*score)
+}
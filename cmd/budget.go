@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Perttulands/chiron/internal/cost"
+	"github.com/Perttulands/chiron/internal/provider"
+	"github.com/Perttulands/chiron/internal/tournament"
+	"github.com/Perttulands/chiron/internal/training"
+	"github.com/spf13/cobra"
+)
+
+// budgetFlags configures the spend limit of a command.
+type budgetFlags struct {
+	usd    float64
+	tokens int
+}
+
+func (b *budgetFlags) register(cmd *cobra.Command) {
+	cmd.Flags().Float64Var(&b.usd, "budget-usd", 0, "Stop before spend passes this many USD (0: unlimited)")
+	cmd.Flags().IntVar(&b.tokens, "budget-tokens", 0, "Stop before input plus output tokens pass this many (0: unlimited)")
+}
+
+func (b budgetFlags) validate() error {
+	if b.usd < 0 {
+		return fmt.Errorf("--budget-usd must be >= 0")
+	}
+	if b.tokens < 0 {
+		return fmt.Errorf("--budget-tokens must be >= 0")
+	}
+	return nil
+}
+
+// set reports whether either budget is limited.
+func (b budgetFlags) set() bool {
+	return b.usd > 0 || b.tokens > 0
+}
+
+// newCostTracker returns a tracker that enforces budget and appends every
+// call it records to the cost ledger.
+func newCostTracker(budget budgetFlags) *cost.Tracker {
+	return cost.NewWithTokens(budget.usd, budget.tokens).WithLedger(cost.NewLedger(""))
+}
+
+// newLedgerTracker returns a tracker that appends every call it records to
+// the cost ledger without enforcing a budget, for commands that take no
+// budget flags.
+func newLedgerTracker() *cost.Tracker {
+	return newCostTracker(budgetFlags{})
+}
+
+// warnLedger reports on w when t could not write the cost ledger. The
+// command's own work has succeeded by then, so it does not fail it.
+func warnLedger(w io.Writer, t *cost.Tracker) {
+	if err := t.LedgerErr(); err != nil {
+		_, _ = fmt.Fprintf(w, "warning: cost ledger: %v\n", err)
+	}
+}
+
+// attributeBout attributes the provider calls of a bout to its contestant.
+func attributeBout(ctx context.Context, c tournament.Contestant) context.Context {
+	return cost.WithAttribution(ctx, cost.Attribution{LineageID: c.LineageID, AgentID: c.Agent.ID})
+}
+
+// writeSpend prints a one-line spend summary against the budget.
+func writeSpend(w io.Writer, s cost.Summary) {
+	line := fmt.Sprintf("spent $%.4f, %d tokens in %d calls", s.TotalCostUSD, s.TotalTokensIn+s.TotalTokensOut, s.EventCount)
+	if s.BudgetUSD > 0 {
+		line += fmt.Sprintf("; $%.4f of $%.4f budget left", s.Remaining, s.BudgetUSD)
+	}
+	if s.BudgetTokens > 0 {
+		line += fmt.Sprintf("; %d of %d tokens left", s.RemainingTokens, s.BudgetTokens)
+	}
+	_, _ = fmt.Fprintln(w, line)
+}
+
+// identities describes the executor and grader the flags select for
+// contestants, as a cost projection and a run manifest see them.
+func (f *tournamentProviderFlags) identities(contestants []tournament.Contestant) (executor, grader training.ProviderIdentity) {
+	name := f.executingProvider(contestants)
+	judge := modelOrDefault(f.judgeProvider, name)
+	executor = training.ProviderIdentity{Role: training.RoleExecutor, Provider: name, Model: strings.TrimSpace(f.model), BaseURL: f.baseURL, Seeded: provider.SeedSupported(name)}
+	grader = training.ProviderIdentity{Role: training.RoleGrader, Provider: judge, Model: strings.TrimSpace(f.judgeModel), BaseURL: f.baseURL, Seeded: provider.SeedSupported(judge)}
+	return executor, grader
+}
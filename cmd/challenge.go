@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Perttulands/chiron/internal/challenge"
+	"github.com/spf13/cobra"
+)
+
+func newChallengeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "challenge",
+		Short: "Manage challenge-set files (YAML or JSON)",
+	}
+
+	cmd.AddCommand(newChallengeNewCmd())
+	cmd.AddCommand(newChallengeGenerateCmd())
+	cmd.AddCommand(newChallengeValidateCmd())
+	cmd.AddCommand(newChallengeListCmd())
+	cmd.AddCommand(newChallengeShowCmd())
+
+	return cmd
+}
+
+func challengeSetNameFromPath(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func describeChallengeSet(set challenge.ChallengeSet) string {
+	return fmt.Sprintf("%s (%s, %d challenges)", set.Name, set.ID, len(set.Challenges))
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Perttulands/chiron/internal/challenge"
+	"github.com/Perttulands/chiron/internal/cost"
+	"github.com/Perttulands/chiron/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+func newChallengeGenerateCmd() *cobra.Command {
+	var count int
+	var challengeType string
+	var difficulty string
+	var domain string
+	var tags []string
+	var name string
+	var providerName string
+	var model string
+	var baseURL string
+	var apiKey string
+
+	cmd := &cobra.Command{
+		Use:   "generate <file>",
+		Short: "Generate challenges with an LLM and append them to a challenge-set file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := strings.TrimSpace(args[0])
+
+			set := &challenge.ChallengeSet{
+				ID:        newPrefixedID("cset"),
+				Name:      strings.TrimSpace(name),
+				CreatedAt: time.Now().UTC().Format(time.RFC3339),
+			}
+			if set.Name == "" {
+				set.Name = challengeSetNameFromPath(path)
+			}
+			if fileExists(path) {
+				existing, err := challenge.ReadSet(path)
+				if err != nil {
+					return err
+				}
+				set = existing
+			}
+
+			adapter, err := provider.NewFactory(provider.Config{
+				Provider: providerName,
+				Model:    model,
+				BaseURL:  baseURL,
+				APIKey:   apiKey,
+			})
+			if err != nil {
+				return fmt.Errorf("initialize provider: %w", err)
+			}
+			tracker := newLedgerTracker()
+			adapter = cost.Track(adapter, tracker, cost.OpChallenge)
+
+			generated, genErr := challenge.GenerateBatch(cmd.Context(), count, challenge.GenerateRequest{
+				Type:       challengeType,
+				Difficulty: difficulty,
+				Domain:     domain,
+				Tags:       tags,
+				Warn: func(msg string) {
+					fmt.Fprintf(os.Stderr, "warning: %s\n", msg)
+				},
+			}, adapter, newPrefixedID)
+			if len(generated) == 0 && genErr != nil {
+				return genErr
+			}
+
+			set.Challenges = append(set.Challenges, generated...)
+			if err := challenge.SaveSet(path, *set); err != nil {
+				return err
+			}
+			if genErr != nil {
+				fmt.Fprintf(os.Stderr, "warning: saved %d of %d challenges: %v\n", len(generated), count, genErr)
+			}
+			if err := set.Validate(); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: %s needs review:\n%v\n", path, err)
+			}
+
+			ids := make([]string, 0, len(generated))
+			for _, ch := range generated {
+				ids = append(ids, ch.ID)
+			}
+
+			warnLedger(os.Stderr, tracker)
+
+			if isJSONOutput(cmd) {
+				return writeJSON(cmd, map[string]any{
+					"path":             path,
+					"challenge_set_id": set.ID,
+					"generated":        ids,
+				})
+			}
+			if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Added %d challenges to %s: %s\n", len(generated), path, describeChallengeSet(*set)); err != nil {
+				return fmt.Errorf("write output: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&count, "count", 1, "Number of challenges to generate")
+	cmd.Flags().StringVar(&challengeType, "type", challenge.TypeFeature, "Challenge type (feature, bugfix, refactor, review)")
+	cmd.Flags().StringVar(&difficulty, "difficulty", challenge.DifficultyMedium, "Difficulty (easy, medium, hard)")
+	cmd.Flags().StringVar(&domain, "domain", "", "Domain, e.g. \"web API\" or \"CLI tool\"")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "Tag to attach to generated challenges (repeatable)")
+	cmd.Flags().StringVar(&name, "name", "", "Challenge set name when creating a new file")
+	cmd.Flags().StringVar(&providerName, "provider", "anthropic", "Provider name (anthropic, openai-compatible, claude-cli, ollama-native, or pi-cli)")
+	cmd.Flags().StringVar(&model, "model", "", "Override provider model")
+	cmd.Flags().StringVar(&baseURL, "base-url", "", "Override provider base URL")
+	cmd.Flags().StringVar(&apiKey, "api-key", "", "Override provider API key")
+
+	return cmd
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/Perttulands/chiron/internal/challenge"
+	"github.com/spf13/cobra"
+)
+
+func newChallengeListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list [dir]",
+		Short: "List challenge-set files under a directory (default: current directory)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := "."
+			if len(args) == 1 {
+				dir = args[0]
+			}
+
+			paths, err := challenge.FindSetFiles(dir)
+			if err != nil {
+				return err
+			}
+
+			type setSummary struct {
+				Path       string `json:"path"`
+				ID         string `json:"id"`
+				Name       string `json:"name"`
+				Challenges int    `json:"challenges"`
+				Error      string `json:"error,omitempty"`
+			}
+
+			summaries := make([]setSummary, 0, len(paths))
+			for _, path := range paths {
+				summary := setSummary{Path: path}
+				set, err := challenge.LoadSet(path)
+				if err != nil {
+					summary.Error = err.Error()
+				} else {
+					summary.ID = set.ID
+					summary.Name = set.Name
+					summary.Challenges = len(set.Challenges)
+				}
+				summaries = append(summaries, summary)
+			}
+
+			if isJSONOutput(cmd) {
+				return writeJSON(cmd, map[string]any{"challenge_sets": summaries})
+			}
+
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			if _, err := fmt.Fprintln(tw, "PATH\tID\tNAME\tCHALLENGES"); err != nil {
+				return fmt.Errorf("write challenge set header: %w", err)
+			}
+			for _, s := range summaries {
+				name := s.Name
+				if s.Error != "" {
+					name = "(unreadable: " + s.Error + ")"
+				}
+				if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%d\n", s.Path, s.ID, name, s.Challenges); err != nil {
+					return fmt.Errorf("write challenge set row %q: %w", s.Path, err)
+				}
+			}
+			return tw.Flush()
+		},
+	}
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Perttulands/chiron/internal/challenge"
+	"github.com/Perttulands/chiron/internal/harness"
+	"github.com/spf13/cobra"
+)
+
+func newChallengeNewCmd() *cobra.Command {
+	var name string
+	var challengeType string
+	var difficulty string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "new <file>",
+		Short: "Create a challenge-set file with one example challenge to edit",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := strings.TrimSpace(args[0])
+			if fileExists(path) && !force {
+				return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+			}
+
+			setName := strings.TrimSpace(name)
+			if setName == "" {
+				setName = challengeSetNameFromPath(path)
+			}
+
+			now := time.Now().UTC().Format(time.RFC3339)
+			set := challenge.ChallengeSet{
+				ID:        newPrefixedID("cset"),
+				Name:      setName,
+				CreatedAt: now,
+				Challenges: []challenge.Challenge{
+					{
+						ID:          newPrefixedID("ch"),
+						Name:        "Example challenge",
+						Type:        challengeType,
+						Difficulty:  difficulty,
+						Description: "Describe what the agent must do and how success is judged.",
+						Input:       "The exact prompt the agent receives.",
+						TestSuite: harness.TestSuite{
+							ID:   newPrefixedID("ts"),
+							Name: "Tests for Example challenge",
+							TestCases: []harness.TestCase{
+								{
+									ID:       newPrefixedID("tc"),
+									Name:     "mentions the key term",
+									Type:     harness.TypeIContains,
+									Expected: "replace me",
+									Weight:   1.0,
+								},
+							},
+						},
+						CreatedAt: now,
+					},
+				},
+			}
+
+			if err := set.Validate(); err != nil {
+				return fmt.Errorf("invalid challenge scaffold: %w", err)
+			}
+			if err := challenge.SaveSet(path, set); err != nil {
+				return err
+			}
+
+			if isJSONOutput(cmd) {
+				return writeJSON(cmd, map[string]any{"path": path, "challenge_set_id": set.ID})
+			}
+			if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Created %s: %s\n", path, describeChallengeSet(set)); err != nil {
+				return fmt.Errorf("write output: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Challenge set name (default: file name)")
+	cmd.Flags().StringVar(&challengeType, "type", challenge.TypeFeature, "Example challenge type (feature, bugfix, refactor, review)")
+	cmd.Flags().StringVar(&difficulty, "difficulty", challenge.DifficultyMedium, "Example challenge difficulty (easy, medium, hard)")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing file")
+
+	return cmd
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Perttulands/chiron/internal/challenge"
+	"github.com/spf13/cobra"
+)
+
+func newChallengeShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <file> [challenge-id]",
+		Short: "Show a challenge set, or one challenge with its test cases",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			set, err := challenge.LoadSet(args[0])
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 2 {
+				ch, ok := set.Find(strings.TrimSpace(args[1]))
+				if !ok {
+					return fmt.Errorf("challenge %q not found in %s", args[1], args[0])
+				}
+				if isJSONOutput(cmd) {
+					return writeJSON(cmd, ch)
+				}
+				return printChallenge(cmd, ch)
+			}
+
+			if isJSONOutput(cmd) {
+				return writeJSON(cmd, set)
+			}
+
+			out := cmd.OutOrStdout()
+			if _, err := fmt.Fprintf(out, "%s\n\n", describeChallengeSet(*set)); err != nil {
+				return fmt.Errorf("write output: %w", err)
+			}
+			tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			if _, err := fmt.Fprintln(tw, "ID\tTYPE\tDIFFICULTY\tTESTS\tNAME"); err != nil {
+				return fmt.Errorf("write challenge header: %w", err)
+			}
+			for _, ch := range set.Challenges {
+				if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", ch.ID, ch.Type, ch.Difficulty, len(ch.TestSuite.TestCases), ch.Name); err != nil {
+					return fmt.Errorf("write challenge row %q: %w", ch.ID, err)
+				}
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+func printChallenge(cmd *cobra.Command, ch challenge.Challenge) error {
+	out := cmd.OutOrStdout()
+	lines := []string{
+		fmt.Sprintf("ID:          %s", ch.ID),
+		fmt.Sprintf("Name:        %s", ch.Name),
+		fmt.Sprintf("Type:        %s", ch.Type),
+		fmt.Sprintf("Difficulty:  %s", ch.Difficulty),
+		fmt.Sprintf("Description: %s", ch.Description),
+		fmt.Sprintf("Input:\n%s", ch.Input),
+	}
+	if strings.TrimSpace(ch.Context) != "" {
+		lines = append(lines, fmt.Sprintf("Context:\n%s", ch.Context))
+	}
+	lines = append(lines, "", fmt.Sprintf("Test suite %s (%d cases):", ch.TestSuite.ID, len(ch.TestSuite.TestCases)))
+	if _, err := fmt.Fprintln(out, strings.Join(lines, "\n")); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "ID\tTYPE\tWEIGHT\tNAME\tEXPECTED"); err != nil {
+		return fmt.Errorf("write test case header: %w", err)
+	}
+	for _, tc := range ch.TestSuite.TestCases {
+		expected := tc.Expected
+		if len(tc.Values) > 0 {
+			expected = strings.Join(tc.Values, ", ")
+		}
+		if tc.Exec != nil {
+			expected = tc.Exec.Command
+		}
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%.2f\t%s\t%s\n", tc.ID, tc.Type, tc.Weight, tc.Name, truncateSandboxField(expected, 60)); err != nil {
+			return fmt.Errorf("write test case row %q: %w", tc.ID, err)
+		}
+	}
+	return tw.Flush()
+}
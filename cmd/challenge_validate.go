@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Perttulands/chiron/internal/challenge"
+	"github.com/spf13/cobra"
+)
+
+type challengeValidation struct {
+	Path       string `json:"path"`
+	Valid      bool   `json:"valid"`
+	Challenges int    `json:"challenges"`
+	Error      string `json:"error,omitempty"`
+}
+
+func newChallengeValidateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate <file>...",
+		Short: "Validate challenge-set files (schema, challenge fields, test suites)",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			results := make([]challengeValidation, 0, len(args))
+			invalid := 0
+			for _, path := range args {
+				result := challengeValidation{Path: path}
+				set, err := challenge.LoadSet(path)
+				if err == nil {
+					result.Challenges = len(set.Challenges)
+					err = set.Validate()
+				}
+				if err != nil {
+					result.Error = err.Error()
+					invalid++
+				} else {
+					result.Valid = true
+				}
+				results = append(results, result)
+			}
+
+			if isJSONOutput(cmd) {
+				if err := writeJSON(cmd, map[string]any{"results": results}); err != nil {
+					return err
+				}
+			} else {
+				for _, r := range results {
+					var err error
+					if r.Valid {
+						_, err = fmt.Fprintf(cmd.OutOrStdout(), "ok    %s (%d challenges)\n", r.Path, r.Challenges)
+					} else {
+						_, err = fmt.Fprintf(cmd.OutOrStdout(), "FAIL  %s\n%s\n", r.Path, r.Error)
+					}
+					if err != nil {
+						return fmt.Errorf("write output: %w", err)
+					}
+				}
+			}
+
+			if invalid > 0 {
+				return fmt.Errorf("%d of %d challenge-set files are invalid", invalid, len(args))
+			}
+			return nil
+		},
+	}
+}
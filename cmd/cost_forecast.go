@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Perttulands/chiron/internal/challenge"
+	"github.com/Perttulands/chiron/internal/checkpoint"
+	"github.com/Perttulands/chiron/internal/cost"
+	"github.com/Perttulands/chiron/internal/experiment"
+	"github.com/Perttulands/chiron/internal/state"
+	"github.com/Perttulands/chiron/internal/training"
+	"github.com/spf13/cobra"
+)
+
+func newCostForecastCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "forecast",
+		Short: "Estimate the spend of a planned experiment matrix or training loop",
+		Long: `Estimate what a run will cost before starting it, with the same projection
+budgets use. Calls are priced with the built-in price tables; models without
+a known price are counted as free and reported as unpriced.`,
+	}
+
+	cmd.AddCommand(newCostForecastExperimentCmd())
+	cmd.AddCommand(newCostForecastLoopCmd())
+
+	return cmd
+}
+
+func newCostForecastExperimentCmd() *cobra.Command {
+	var model string
+	var condition string
+	var replicas int
+
+	cmd := &cobra.Command{
+		Use:   "experiment <config.yaml>",
+		Short: "Forecast the pending cells of an experiment matrix",
+		Long: `Forecast the cells experiment run would execute. Completed cells are
+skipped. Each pending cell is priced at the average tokens of the model's
+completed cells; a model with none is estimated from its prompt sizes
+(basis "prompt"), which undercounts runs that take many turns.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath := args[0]
+			cfg, err := experiment.LoadConfig(configPath)
+			if err != nil {
+				return fmt.Errorf("loading config: %w", err)
+			}
+			baseDir := filepath.Dir(configPath)
+			if abs, err := filepath.Abs(baseDir); err == nil {
+				baseDir = abs
+			}
+
+			forecasts, err := experiment.Forecast(cfg, baseDir, experiment.RunOptions{
+				ModelFilter:     model,
+				ConditionFilter: condition,
+				ReplicaOverride: replicas,
+			})
+			if err != nil {
+				return fmt.Errorf("forecast experiment %q: %w", cfg.Name, err)
+			}
+			var total cost.Projection
+			for _, f := range forecasts {
+				total = total.Add(f.Cost)
+			}
+
+			if isJSONOutput(cmd) {
+				return writeJSON(cmd, map[string]any{
+					"experiment": cfg.Name,
+					"models":     forecasts,
+					"total":      total,
+				})
+			}
+
+			out := cmd.OutOrStdout()
+			tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			if _, err := fmt.Fprintln(tw, "MODEL\tPROVIDER\tPENDING\tDONE\tBASIS\tTOKENS\tCOST_USD"); err != nil {
+				return fmt.Errorf("write forecast header: %w", err)
+			}
+			for _, f := range forecasts {
+				if _, err := fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\t%d\t%.4f\n", f.Model, f.Provider, f.Pending, f.Completed, f.Basis, f.Cost.Tokens(), f.Cost.CostUSD); err != nil {
+					return fmt.Errorf("write forecast row %q: %w", f.Model, err)
+				}
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			return writeProjection(out, "total", total)
+		},
+	}
+
+	cmd.Flags().StringVar(&model, "model", "", "Forecast only this model")
+	cmd.Flags().StringVar(&condition, "condition", "", "Forecast only this condition")
+	cmd.Flags().IntVar(&replicas, "replicas", 0, "Override replica count")
+
+	return cmd
+}
+
+func newCostForecastLoopCmd() *cobra.Command {
+	var flags tournamentProviderFlags
+	var challengesPath string
+	var generations int
+	var selectCount int
+	var repetitions int
+	var mutatorProvider string
+	var mutatorModel string
+	var resumeID string
+	var curriculum training.CurriculumConfig
+
+	cmd := &cobra.Command{
+		Use:   "loop <session-id>",
+		Short: "Forecast a loop run over a session's lineages",
+		Long: `Forecast chiron loop run with the same flags. Every generation is projected
+like the first: each contestant on each challenge and repetition, a grading
+call per llm_rubric test case and a mutation per replaced contestant. With
+--resume the remaining generations of a checkpointed loop are projected,
+using its last generation's recorded spend when that is higher. With
+--curriculum only the first tier's challenges are projected, so harder
+tiers may cost more.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sessionID := strings.TrimSpace(args[0])
+			if strings.TrimSpace(challengesPath) == "" {
+				return fmt.Errorf("--challenges is required")
+			}
+			set, err := challenge.LoadSet(challengesPath)
+			if err != nil {
+				return err
+			}
+
+			var loop *training.Loop
+			if id := strings.TrimSpace(resumeID); id != "" {
+				cp, err := checkpoint.Load(id)
+				if err != nil {
+					return err
+				}
+				if cp.Recovered != "" {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "warning: latest checkpoint unusable (%s); forecasting from %s, generation %d\n", cp.Recovered, cp.Path, cp.Generation)
+				}
+				loop = &cp.Loop
+			} else {
+				st, err := state.Load("")
+				if err != nil {
+					return fmt.Errorf("load state: %w", err)
+				}
+				session, ok := st.Sessions[sessionID]
+				if !ok {
+					return fmt.Errorf("session %q not found", sessionID)
+				}
+				contestants, err := sessionContestants(session)
+				if err != nil {
+					return err
+				}
+				cfg := training.DefaultConfig(newPrefixedID)
+				cfg.MaxGenerations = generations
+				cfg.SelectionCount = selectCount
+				cfg.Schedule.Repetitions = repetitions
+				cfg.Curriculum = curriculum
+				loop, err = training.NewLoop(cfg, contestants)
+				if err != nil {
+					return err
+				}
+			}
+			run := &loopRun{loop: loop, flags: &flags, mutatorProvider: mutatorProvider, mutatorModel: mutatorModel}
+			loop.Config.Providers = run.identities()
+
+			remaining := max(loop.Config.MaxGenerations-loop.CurrentGeneration(), 0)
+			perGeneration := loop.ProjectGeneration(set.Challenges)
+			var total cost.Projection
+			for range remaining {
+				total = total.Add(perGeneration)
+			}
+
+			if isJSONOutput(cmd) {
+				return writeJSON(cmd, map[string]any{
+					"session_id":     sessionID,
+					"generations":    remaining,
+					"per_generation": perGeneration,
+					"total":          total,
+					"spent_usd":      loop.SpentUSD,
+					"spent_tokens":   loop.SpentTokens,
+				})
+			}
+
+			out := cmd.OutOrStdout()
+			if _, err := fmt.Fprintf(out, "generations=%d contestants=%d challenges=%d\n", remaining, len(loop.Contestants), len(set.Challenges)); err != nil {
+				return fmt.Errorf("write output: %w", err)
+			}
+			if err := writeProjection(out, "per generation", perGeneration); err != nil {
+				return err
+			}
+			return writeProjection(out, "total", total)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.provider, "provider", "", "Provider for bouts (default: the provider that generated the first contestant)")
+	cmd.Flags().StringVar(&flags.model, "model", "", "Model override for bouts (default: each agent's model)")
+	cmd.Flags().StringVar(&flags.judgeProvider, "judge-provider", "", "Provider for llm_rubric test cases (default: the bout provider)")
+	cmd.Flags().StringVar(&flags.judgeModel, "judge-model", "", "Model for llm_rubric test cases (default: the first contestant's model)")
+	cmd.Flags().StringVar(&mutatorProvider, "mutator-provider", "", "Provider for mutations (default: the bout provider)")
+	cmd.Flags().StringVar(&mutatorModel, "mutator-model", "", "Model for mutations (default: the parent's model)")
+	cmd.Flags().StringVar(&challengesPath, "challenges", "", "Challenge set file the contestants play")
+	cmd.Flags().IntVar(&generations, "generations", 10, "Generations to run")
+	cmd.Flags().IntVar(&selectCount, "select", 2, "Contestants selection keeps each generation")
+	cmd.Flags().IntVar(&repetitions, "repetitions", 0, "Bouts per contestant and challenge")
+	cmd.Flags().StringVar(&resumeID, "resume", "", "Forecast the rest of the checkpointed loop with this id")
+	cmd.Flags().BoolVar(&curriculum.Enabled, "curriculum", false, "Forecast a curriculum loop, which plays one difficulty tier at a time")
+
+	return cmd
+}
+
+// writeProjection prints one labelled projection line.
+func writeProjection(w io.Writer, label string, p cost.Projection) error {
+	line := fmt.Sprintf("%s: %d calls, %d tokens, $%.4f", label, p.Calls, p.Tokens(), p.CostUSD)
+	if p.Unpriced > 0 {
+		line += fmt.Sprintf(" (%d calls unpriced)", p.Unpriced)
+	}
+	if _, err := fmt.Fprintln(w, line); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Perttulands/chiron/internal/cost"
+	"github.com/spf13/cobra"
+)
+
+func newCostReportCmd() *cobra.Command {
+	var since string
+	var by string
+	var format string
+	var ledgerPath string
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Summarise recorded spend by session, model, operation, lineage or day",
+		Long: `Summarise the cost ledger, the record of every provider call chiron has
+made in this directory (.chiron/cost_ledger.jsonl).
+
+Calls are grouped by --by and listed most expensive first; calls without a
+value for the grouping, such as experiment cells for --by session, are
+listed under "-".`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, err := parseSince(since, time.Now().UTC())
+			if err != nil {
+				return err
+			}
+			if isJSONOutput(cmd) {
+				format = "json"
+			}
+			if format != "table" && format != "json" && format != "csv" {
+				return fmt.Errorf("unknown format %q; choose from: table, json, csv", format)
+			}
+
+			events, err := cost.ReadLedger(ledgerPath)
+			if err != nil {
+				return err
+			}
+			rows, err := cost.Report(events, from, by)
+			if err != nil {
+				return err
+			}
+			total := cost.ReportRow{Key: "total"}
+			for _, r := range rows {
+				total.Calls += r.Calls
+				total.TokensIn += r.TokensIn
+				total.TokensOut += r.TokensOut
+				total.CostUSD += r.CostUSD
+				total.DurationMS += r.DurationMS
+			}
+
+			out := cmd.OutOrStdout()
+			switch format {
+			case "json":
+				payload := map[string]any{"by": by, "rows": rows, "total": total}
+				if !from.IsZero() {
+					payload["since"] = from.Format(time.RFC3339)
+				}
+				return writeJSON(cmd, payload)
+			case "csv":
+				w := csv.NewWriter(out)
+				if err := w.Write([]string{by, "calls", "tokens_in", "tokens_out", "cost_usd", "duration_ms"}); err != nil {
+					return fmt.Errorf("write csv header: %w", err)
+				}
+				for _, r := range rows {
+					if err := w.Write([]string{
+						r.Key,
+						strconv.Itoa(r.Calls),
+						strconv.Itoa(r.TokensIn),
+						strconv.Itoa(r.TokensOut),
+						strconv.FormatFloat(r.CostUSD, 'f', 6, 64),
+						strconv.Itoa(r.DurationMS),
+					}); err != nil {
+						return fmt.Errorf("write csv row %q: %w", r.Key, err)
+					}
+				}
+				w.Flush()
+				return w.Error()
+			}
+
+			if len(rows) == 0 {
+				_, err := fmt.Fprintln(out, "no recorded spend")
+				return err
+			}
+			tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			if _, err := fmt.Fprintf(tw, "%s\tCALLS\tTOKENS_IN\tTOKENS_OUT\tCOST_USD\n", strings.ToUpper(by)); err != nil {
+				return fmt.Errorf("write report header: %w", err)
+			}
+			for _, r := range append(rows, total) {
+				if _, err := fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.4f\n", r.Key, r.Calls, r.TokensIn, r.TokensOut, r.CostUSD); err != nil {
+					return fmt.Errorf("write report row %q: %w", r.Key, err)
+				}
+			}
+			return tw.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "Only calls since this age or time, e.g. 7d, 12h or 2026-01-31 (default: all)")
+	cmd.Flags().StringVar(&by, "by", cost.BySession, "Group by: "+strings.Join(cost.ValidGroupings, ", "))
+	cmd.Flags().StringVar(&format, "format", "table", "Output format: table, json, csv")
+	cmd.Flags().StringVar(&ledgerPath, "ledger", cost.DefaultLedgerPath, "Cost ledger file")
+
+	return cmd
+}
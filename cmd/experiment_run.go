@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/Perttulands/chiron/internal/cost"
 	"github.com/Perttulands/chiron/internal/experiment"
 	"github.com/Perttulands/chiron/internal/sandbox"
 	"github.com/spf13/cobra"
@@ -16,6 +18,7 @@ func newExperimentRunCmd() *cobra.Command {
 		model     string
 		condition string
 		replicas  int
+		budget    budgetFlags
 	)
 
 	cmd := &cobra.Command{
@@ -24,6 +27,9 @@ func newExperimentRunCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			configPath := args[0]
+			if err := budget.validate(); err != nil {
+				return err
+			}
 
 			cfg, err := experiment.LoadConfig(configPath)
 			if err != nil {
@@ -44,6 +50,7 @@ func newExperimentRunCmd() *cobra.Command {
 				ConditionFilter: condition,
 				ReplicaOverride: replicas,
 				DryRun:          dryRun,
+				Budget:          newCostTracker(budget),
 			}
 
 			cells := experiment.MatrixCells(cfg, opts)
@@ -53,11 +60,19 @@ func newExperimentRunCmd() *cobra.Command {
 			runner := experiment.NewRunner(executor, baseDir)
 
 			results, err := runner.Run(cmd.Context(), cfg, opts)
-			if err != nil {
+			if errors.Is(err, cost.ErrBudgetExceeded) {
+				// Completed cells are skipped on the next run, so a
+				// budget stop can be continued by rerunning.
+				fmt.Fprintf(os.Stderr, "\n%v\nRerun to continue with the remaining cells.\n", err)
+			} else if err != nil {
 				return err
 			}
 
 			fmt.Fprintf(os.Stderr, "\nCompleted: %d/%d cells\n", len(results), len(cells))
+			if budget.set() {
+				writeSpend(os.Stderr, opts.Budget.Summarize())
+			}
+			warnLedger(os.Stderr, opts.Budget)
 			return nil
 		},
 	}
@@ -66,6 +81,7 @@ func newExperimentRunCmd() *cobra.Command {
 	cmd.Flags().StringVar(&model, "model", "", "Run only this model")
 	cmd.Flags().StringVar(&condition, "condition", "", "Run only this condition")
 	cmd.Flags().IntVar(&replicas, "replicas", 0, "Override replica count")
+	budget.register(cmd)
 
 	return cmd
 }
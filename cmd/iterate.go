@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Perttulands/chiron/internal/cost"
 	"github.com/Perttulands/chiron/internal/engine"
 	"github.com/Perttulands/chiron/internal/provider"
 	"github.com/Perttulands/chiron/internal/state"
@@ -76,12 +77,18 @@ func newIterateCmd() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("initialize provider: %w", err)
 			}
+			tracker := newLedgerTracker()
+			adapter = cost.Track(adapter, tracker, cost.OpGenerate)
 
-			newDefinition, generationMeta, err := engine.GenerateAgentDefinitionWithMetadata(cmd.Context(), evolutionPrompt, nil, adapter)
+			newDefinition, generationMeta, err := engine.GenerateAgentDefinitionWithMetadata(cost.WithAttribution(cmd.Context(), cost.Attribution{SessionID: sessionID, LineageID: lineage.ID}), evolutionPrompt, nil, adapter)
 			if err != nil {
 				return fmt.Errorf("generate agent: %w", err)
 			}
 
+			generationMeta.ParentIDs = []string{prevAgent.ID}
+			generationMeta.Operator = engine.OperatorEvolve
+			generationMeta.MutationPrompt = evolutionPrompt
+
 			newAgent := state.Agent{
 				ID:                 newPrefixedID("agt"),
 				LineageID:          lineage.ID,
@@ -99,6 +106,7 @@ func newIterateCmd() *cobra.Command {
 			if err := state.Save("", st); err != nil {
 				return fmt.Errorf("save state: %w", err)
 			}
+			warnLedger(cmd.ErrOrStderr(), tracker)
 
 			if isJSONOutput(cmd) {
 				return writeJSON(cmd, map[string]any{
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Perttulands/chiron/internal/rating"
+	"github.com/Perttulands/chiron/internal/state"
+	"github.com/spf13/cobra"
+)
+
+type leaderboardEntry struct {
+	Rank         int     `json:"rank"`
+	AgentID      string  `json:"agent_id"`
+	SessionID    string  `json:"session_id,omitempty"`
+	Lineage      string  `json:"lineage,omitempty"`
+	Version      int     `json:"version"`
+	Elo          float64 `json:"elo"`
+	Mu           float64 `json:"mu"`
+	Sigma        float64 `json:"sigma"`
+	Conservative float64 `json:"conservative"`
+	Matches      int     `json:"matches"`
+	Wins         int     `json:"wins"`
+	Losses       int     `json:"losses"`
+	Draws        int     `json:"draws"`
+	Prompt       string  `json:"prompt,omitempty"`
+}
+
+func newLeaderboardCmd() *cobra.Command {
+	var system string
+	var sessionID string
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "leaderboard",
+		Short: "Show persistent agent ratings across tournaments and sessions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			st, err := state.Load("")
+			if err != nil {
+				return fmt.Errorf("load state: %w", err)
+			}
+
+			ratings, err := rating.Table(st.Ratings).Leaderboard(strings.TrimSpace(system))
+			if err != nil {
+				return err
+			}
+
+			agents := indexAgents(st)
+			entries := []leaderboardEntry{}
+			for _, r := range ratings {
+				located := agents[r.AgentID]
+				if sessionID != "" && located.sessionID != sessionID {
+					continue
+				}
+				entries = append(entries, leaderboardEntry{
+					Rank:         len(entries) + 1,
+					AgentID:      r.AgentID,
+					SessionID:    located.sessionID,
+					Lineage:      located.lineage,
+					Version:      r.Version,
+					Elo:          r.Elo,
+					Mu:           r.Mu,
+					Sigma:        r.Sigma,
+					Conservative: rating.Conservative(r),
+					Matches:      r.Matches,
+					Wins:         r.Wins,
+					Losses:       r.Losses,
+					Draws:        r.Draws,
+					Prompt:       located.prompt,
+				})
+				if limit > 0 && len(entries) == limit {
+					break
+				}
+			}
+
+			if isJSONOutput(cmd) {
+				return writeJSON(cmd, map[string]any{"system": system, "leaderboard": entries})
+			}
+
+			if len(entries) == 0 {
+				_, err := fmt.Fprintln(cmd.OutOrStdout(), "No ratings yet. Ratings are recorded when agents play tournaments.")
+				return err
+			}
+
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			if _, err := fmt.Fprintln(tw, "RANK\tAGENT\tSESSION\tLINEAGE\tVERSION\tELO\tTRUESKILL\tW/L/D\tPROMPT"); err != nil {
+				return fmt.Errorf("write leaderboard header: %w", err)
+			}
+			for _, e := range entries {
+				if _, err := fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\t%.0f\t%.1f±%.1f\t%d/%d/%d\t%s\n",
+					e.Rank, e.AgentID, truncateSandboxField(e.SessionID, 16), truncateSandboxField(e.Lineage, 16), e.Version,
+					e.Elo, e.Mu, e.Sigma, e.Wins, e.Losses, e.Draws,
+					truncateSandboxField(e.Prompt, 40)); err != nil {
+					return fmt.Errorf("write leaderboard row %q: %w", e.AgentID, err)
+				}
+			}
+			return tw.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&system, "system", rating.SystemElo, "Sort by rating system: elo or trueskill (mu - 3*sigma)")
+	cmd.Flags().StringVar(&sessionID, "session", "", "Only show agents from this session")
+	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum rows to show (0 for all)")
+
+	return cmd
+}
+
+type agentLocation struct {
+	sessionID string
+	lineage   string
+	prompt    string
+}
+
+// indexAgents maps every agent id in state to where it lives.
+func indexAgents(st state.State) map[string]agentLocation {
+	index := map[string]agentLocation{}
+	for sessionID, session := range st.Sessions {
+		for _, lineage := range session.Lineages {
+			for _, agent := range lineage.Agents {
+				index[agent.ID] = agentLocation{
+					sessionID: sessionID,
+					lineage:   lineage.Name,
+					prompt:    strings.Join(strings.Fields(agent.Definition.SystemPrompt), " "),
+				}
+			}
+		}
+	}
+	return index
+}
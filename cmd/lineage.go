@@ -11,11 +11,12 @@ import (
 func newLineageCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "lineage",
-		Short: "Manage lineage lock state",
+		Short: "Manage lineage lock state and inspect lineage history",
 	}
 
 	cmd.AddCommand(newLineageLockCmd())
 	cmd.AddCommand(newLineageUnlockCmd())
+	cmd.AddCommand(newLineageGraphCmd())
 	return cmd
 }
 
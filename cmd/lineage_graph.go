@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Perttulands/chiron/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// Graph output formats for lineage graph.
+const (
+	graphFormatDOT     = "dot"
+	graphFormatMermaid = "mermaid"
+)
+
+type lineageGraphNode struct {
+	AgentID     string   `json:"agent_id"`
+	Lineage     string   `json:"lineage"`
+	Version     int      `json:"version"`
+	Operator    string   `json:"operator,omitempty"`
+	Reasoning   string   `json:"reasoning,omitempty"`
+	AvgScore    *float64 `json:"avg_score,omitempty"`      // mean composite score of the agent's artifacts
+	Scored      int      `json:"scored_artifacts"`         // artifacts with a composite score
+	AvgEval     *float64 `json:"avg_evaluation,omitempty"` // mean reviewer score (1-10)
+	Evaluations int      `json:"evaluations"`
+}
+
+type lineageGraphEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Operator string `json:"operator,omitempty"`
+	Partner  bool   `json:"partner,omitempty"`  // crossover partner rather than primary parent
+	Inferred bool   `json:"inferred,omitempty"` // no recorded provenance; linked to the previous version
+}
+
+type lineageGraph struct {
+	SessionID string             `json:"session_id"`
+	Nodes     []lineageGraphNode `json:"nodes"`
+	Edges     []lineageGraphEdge `json:"edges"`
+}
+
+func newLineageGraphCmd() *cobra.Command {
+	var format string
+	var lineageName string
+
+	cmd := &cobra.Command{
+		Use:   "graph <session-id>",
+		Short: "Render a session's agent family tree as DOT or Mermaid",
+		Long: `Render every agent version of a session as a family tree.
+
+Nodes show the lineage, version and mean scores of the agent's artifacts;
+edges run from parent to child and are labelled with the mutation operator.
+Crossover partners are drawn dashed. Agents created before provenance was
+recorded are linked to the previous version of their lineage with a dotted
+edge.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format = strings.ToLower(strings.TrimSpace(format))
+			if format != graphFormatDOT && format != graphFormatMermaid {
+				return fmt.Errorf("unknown format %q; choose from: %s, %s", format, graphFormatDOT, graphFormatMermaid)
+			}
+
+			st, err := state.Load("")
+			if err != nil {
+				return fmt.Errorf("load state: %w", err)
+			}
+			sessionID := strings.TrimSpace(args[0])
+			session, ok := st.Sessions[sessionID]
+			if !ok {
+				return fmt.Errorf("session %q not found", sessionID)
+			}
+
+			lineages := make([]state.Lineage, 0, len(session.Lineages))
+			if name := strings.TrimSpace(lineageName); name != "" {
+				_, lineage, ok := findLineageByName(session, name)
+				if !ok {
+					return fmt.Errorf("lineage %q not found", name)
+				}
+				lineages = append(lineages, lineage)
+			} else {
+				for _, lineage := range session.Lineages {
+					lineages = append(lineages, lineage)
+				}
+			}
+			sort.Slice(lineages, func(i, j int) bool { return lineages[i].Name < lineages[j].Name })
+
+			graph := buildLineageGraph(sessionID, lineages)
+			if isJSONOutput(cmd) {
+				return writeJSON(cmd, graph)
+			}
+			if format == graphFormatMermaid {
+				return writeMermaidGraph(cmd.OutOrStdout(), graph)
+			}
+			return writeDOTGraph(cmd.OutOrStdout(), graph)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", graphFormatDOT, "Output format: dot or mermaid")
+	cmd.Flags().StringVar(&lineageName, "lineage", "", "Only graph this lineage")
+
+	return cmd
+}
+
+func buildLineageGraph(sessionID string, lineages []state.Lineage) lineageGraph {
+	graph := lineageGraph{SessionID: sessionID, Nodes: []lineageGraphNode{}, Edges: []lineageGraphEdge{}}
+	known := map[string]bool{}
+	for _, lineage := range lineages {
+		for _, agent := range lineage.Agents {
+			known[agent.ID] = true
+		}
+	}
+
+	for _, lineage := range lineages {
+		agents := append([]state.Agent(nil), lineage.Agents...)
+		sort.SliceStable(agents, func(i, j int) bool { return agents[i].Version < agents[j].Version })
+
+		for i, agent := range agents {
+			meta := agent.GenerationMetadata
+			node := lineageGraphNode{
+				AgentID:   agent.ID,
+				Lineage:   lineage.Name,
+				Version:   agent.Version,
+				Operator:  meta.Operator,
+				Reasoning: meta.Reasoning,
+			}
+			var scoreSum, evalSum float64
+			for _, artifact := range lineage.Artifacts {
+				if artifact.AgentID != agent.ID {
+					continue
+				}
+				if artifact.CompositeScore != nil {
+					scoreSum += artifact.CompositeScore.FinalScore
+					node.Scored++
+				}
+				if artifact.Evaluation != nil {
+					evalSum += float64(artifact.Evaluation.Score)
+					node.Evaluations++
+				}
+			}
+			if node.Scored > 0 {
+				avg := scoreSum / float64(node.Scored)
+				node.AvgScore = &avg
+			}
+			if node.Evaluations > 0 {
+				avg := evalSum / float64(node.Evaluations)
+				node.AvgEval = &avg
+			}
+			graph.Nodes = append(graph.Nodes, node)
+
+			if len(meta.ParentIDs) == 0 {
+				if i > 0 {
+					graph.Edges = append(graph.Edges, lineageGraphEdge{From: agents[i-1].ID, To: agent.ID, Inferred: true})
+				}
+				continue
+			}
+			for j, parentID := range meta.ParentIDs {
+				if !known[parentID] {
+					continue
+				}
+				graph.Edges = append(graph.Edges, lineageGraphEdge{
+					From:     parentID,
+					To:       agent.ID,
+					Operator: meta.Operator,
+					Partner:  j > 0,
+				})
+			}
+		}
+	}
+	return graph
+}
+
+// graphNodeLines returns the label lines of a node, shared by both formats.
+func graphNodeLines(node lineageGraphNode) []string {
+	lines := []string{fmt.Sprintf("%s v%d", node.Lineage, node.Version), node.AgentID}
+	if node.AvgScore != nil {
+		lines = append(lines, fmt.Sprintf("score %.2f (n=%d)", *node.AvgScore, node.Scored))
+	}
+	if node.AvgEval != nil {
+		lines = append(lines, fmt.Sprintf("eval %.1f (n=%d)", *node.AvgEval, node.Evaluations))
+	}
+	return lines
+}
+
+func writeDOTGraph(w io.Writer, graph lineageGraph) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", graph.SessionID)
+	b.WriteString("  rankdir=TB;\n  node [shape=box];\n")
+
+	cluster := ""
+	for _, node := range graph.Nodes {
+		if node.Lineage != cluster {
+			if cluster != "" {
+				b.WriteString("  }\n")
+			}
+			cluster = node.Lineage
+			fmt.Fprintf(&b, "  subgraph %q {\n    label=%q;\n", "cluster_"+cluster, cluster)
+		}
+		fmt.Fprintf(&b, "    %q [label=%q];\n", node.AgentID, strings.Join(graphNodeLines(node), "\n"))
+	}
+	if cluster != "" {
+		b.WriteString("  }\n")
+	}
+
+	for _, edge := range graph.Edges {
+		attrs := []string{}
+		if edge.Operator != "" {
+			attrs = append(attrs, fmt.Sprintf("label=%q", edge.Operator))
+		}
+		switch {
+		case edge.Partner:
+			attrs = append(attrs, "style=dashed")
+		case edge.Inferred:
+			attrs = append(attrs, "style=dotted")
+		}
+		fmt.Fprintf(&b, "  %q -> %q", edge.From, edge.To)
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(attrs, ", "))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func writeMermaidGraph(w io.Writer, graph lineageGraph) error {
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+
+	cluster := ""
+	for _, node := range graph.Nodes {
+		if node.Lineage != cluster {
+			if cluster != "" {
+				b.WriteString("  end\n")
+			}
+			cluster = node.Lineage
+			fmt.Fprintf(&b, "  subgraph %s[\"%s\"]\n", mermaidID("lineage_"+cluster), mermaidText(cluster))
+		}
+		lines := graphNodeLines(node)
+		for i := range lines {
+			lines[i] = mermaidText(lines[i])
+		}
+		fmt.Fprintf(&b, "    %s[\"%s\"]\n", mermaidID(node.AgentID), strings.Join(lines, "<br/>"))
+	}
+	if cluster != "" {
+		b.WriteString("  end\n")
+	}
+
+	for _, edge := range graph.Edges {
+		arrow := "-->"
+		if edge.Partner || edge.Inferred {
+			arrow = "-.->"
+		}
+		if edge.Operator != "" {
+			fmt.Fprintf(&b, "  %s %s|%s| %s\n", mermaidID(edge.From), arrow, mermaidText(edge.Operator), mermaidID(edge.To))
+		} else {
+			fmt.Fprintf(&b, "  %s %s %s\n", mermaidID(edge.From), arrow, mermaidID(edge.To))
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+// mermaidID maps an id to the characters Mermaid accepts in node ids.
+func mermaidID(id string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, id)
+}
+
+// mermaidText escapes text for a quoted Mermaid label.
+func mermaidText(text string) string {
+	return strings.NewReplacer(`"`, "#quot;", "|", "#124;").Replace(text)
+}
@@ -0,0 +1,16 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+func newLoopCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "loop",
+		Short: "Inspect and reproduce training loop runs",
+	}
+
+	cmd.AddCommand(newLoopRunCmd())
+	cmd.AddCommand(newLoopReplayCmd())
+	cmd.AddCommand(newLoopReviewCmd())
+
+	return cmd
+}
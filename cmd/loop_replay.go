@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Perttulands/chiron/internal/challenge"
+	"github.com/Perttulands/chiron/internal/provider"
+	"github.com/Perttulands/chiron/internal/state"
+	"github.com/Perttulands/chiron/internal/tournament"
+	"github.com/Perttulands/chiron/internal/training"
+	"github.com/spf13/cobra"
+)
+
+func newLoopReplayCmd() *cobra.Command {
+	var flags tournamentProviderFlags
+
+	cmd := &cobra.Command{
+		Use:   "replay <manifest>",
+		Short: "Re-run a training loop from its manifest and check whether it reproduces",
+		Long: `Re-run every recorded generation of a training loop with the manifest's
+configuration, seed, challenges and contestants, then compare each bout's
+output hash, the contestants selection kept and the children bred from them.
+Generations the loop held for review are replayed with the manual scores
+recorded in the manifest. Breeding reads the loop's session from the local
+state but never saves it.
+
+Providers default to the ones recorded in the manifest. Runs only reproduce
+when every provider accepted the seed and the backend honours it; composite
+scores include latency and are reported as a delta.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifest, err := training.LoadManifest(args[0])
+			if err != nil {
+				return err
+			}
+			if len(manifest.Generations) == 0 {
+				return fmt.Errorf("manifest %q has no generations to replay", args[0])
+			}
+
+			errOut := cmd.ErrOrStderr()
+			var mutatorProvider, mutatorModel string
+			for _, p := range manifest.Providers {
+				switch p.Role {
+				case training.RoleExecutor:
+					flags.provider = modelOrDefault(flags.provider, p.Provider)
+					flags.baseURL = modelOrDefault(flags.baseURL, p.BaseURL)
+				case training.RoleGrader:
+					flags.judgeProvider = modelOrDefault(flags.judgeProvider, p.Provider)
+					flags.judgeModel = modelOrDefault(flags.judgeModel, p.Model)
+				case training.RoleMutator:
+					mutatorProvider, mutatorModel = p.Provider, p.Model
+				}
+				if !p.Seeded {
+					_, _ = fmt.Fprintf(errOut, "warning: %s provider %s was not seeded; its outputs may not reproduce\n", p.Role, p.Provider)
+				}
+			}
+			if current := training.CurrentBinary(); current.Revision != manifest.Binary.Revision || current.Version != manifest.Binary.Version {
+				_, _ = fmt.Fprintf(errOut, "warning: manifest was written by chiron %s; this is %s\n", manifest.Binary, current)
+			}
+			flags.seed = manifest.Seed
+			flags.tracker = newLedgerTracker()
+
+			first := manifest.Generations[0].Contestants
+			grader, err := flags.grader(&tournament.Tournament{Contestants: first, Challenges: manifest.Challenges})
+			if err != nil {
+				return fmt.Errorf("replay loop %q: %w", manifest.LoopID, err)
+			}
+
+			cfg := manifest.Config
+			cfg.Seed = manifest.Seed
+			cfg.IDFunc = newPrefixedID
+			cfg.Grader = grader
+			cfg.ManifestDir = ""
+			cfg.Schedule.Progress = tournamentProgress(errOut)
+			cfg.Schedule.BoutContext = attributeBout
+			loop, err := training.NewLoop(cfg, first)
+			if err != nil {
+				return fmt.Errorf("replay loop %q: %w", manifest.LoopID, err)
+			}
+
+			run := &loopRun{
+				sessionID:       manifest.SessionID,
+				loop:            loop,
+				flags:           &flags,
+				tracker:         flags.tracker,
+				mutatorProvider: mutatorProvider,
+				mutatorModel:    mutatorModel,
+				adapters:        map[string]provider.Provider{},
+				progress:        errOut,
+			}
+			challenges := map[string]challenge.Challenge{}
+			for _, ch := range manifest.Challenges {
+				challenges[ch.ID] = ch
+			}
+
+			results := make([]training.ReplayResult, 0, len(manifest.Generations))
+			for i, recorded := range manifest.Generations {
+				if loop.IsComplete() {
+					break
+				}
+				set := make([]challenge.Challenge, 0, len(recorded.ChallengeIDs))
+				for _, id := range recorded.ChallengeIDs {
+					ch, ok := challenges[id]
+					if !ok {
+						return fmt.Errorf("manifest generation %d references unknown challenge %q", recorded.Number, id)
+					}
+					set = append(set, ch)
+				}
+				loop.SetContestants(recorded.Contestants)
+				loop.Config.Schedule.Provider = flags.executingProvider(recorded.Contestants)
+				gen, err := loop.RunGeneration(cmd.Context(), set, flags.executor(recorded.Contestants))
+				if errors.Is(err, training.ErrReviewPending) {
+					if _, err := loop.ApplyRecordedReview(recorded); err != nil {
+						return err
+					}
+					gen, err = loop.RunGeneration(cmd.Context(), set, flags.executor(recorded.Contestants))
+				}
+				if err != nil {
+					return fmt.Errorf("replay generation %d: %w", recorded.Number, err)
+				}
+				result := training.CompareGeneration(recorded, *gen)
+				if i+1 < len(manifest.Generations) && !loop.IsComplete() {
+					st, err := replayState(manifest.SessionID)
+					if err != nil {
+						return err
+					}
+					children, err := run.breed(cmd.Context(), &st, *gen)
+					if err != nil {
+						return fmt.Errorf("replay mutation of generation %d: %w", recorded.Number, err)
+					}
+					result.CompareChildren(recorded.Contestants, manifest.Generations[i+1].Contestants, children)
+				}
+				results = append(results, result)
+			}
+			warnLedger(errOut, flags.tracker)
+
+			reproduced := 0
+			for _, r := range results {
+				if r.Reproduced {
+					reproduced++
+				}
+			}
+
+			if isJSONOutput(cmd) {
+				if err := writeJSON(cmd, map[string]any{
+					"loop_id":     manifest.LoopID,
+					"seed":        manifest.Seed,
+					"generations": results,
+					"reproduced":  reproduced == len(manifest.Generations),
+				}); err != nil {
+					return err
+				}
+			} else {
+				out := cmd.OutOrStdout()
+				if _, err := fmt.Fprintf(out, "loop_id=%s seed=%d\n", manifest.LoopID, manifest.Seed); err != nil {
+					return fmt.Errorf("write output: %w", err)
+				}
+				tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+				if _, err := fmt.Fprintln(tw, "GEN\tOUTPUTS\tMISSING\tSCORE_DELTA\tWINNERS\tCHILDREN\tRESULT"); err != nil {
+					return fmt.Errorf("write replay header: %w", err)
+				}
+				for _, r := range results {
+					winners := "same"
+					if !r.WinnersMatch {
+						winners = strings.Join(r.Winners, ",") + " -> " + strings.Join(r.ReplayedWinners, ",")
+					}
+					result := "reproduced"
+					if !r.Reproduced {
+						result = "diverged"
+					}
+					if _, err := fmt.Fprintf(tw, "%d\t%d/%d\t%d\t%.3f\t%s\t%d/%d\t%s\n",
+						r.Number, r.MatchingOutputs, r.Bouts, r.MissingBouts, r.MaxScoreDelta, winners, r.MatchingChildren, r.Children, result); err != nil {
+						return fmt.Errorf("write replay row %d: %w", r.Number, err)
+					}
+				}
+				if err := tw.Flush(); err != nil {
+					return err
+				}
+			}
+
+			if reproduced != len(manifest.Generations) {
+				return fmt.Errorf("loop %q reproduced %d of %d generations", manifest.LoopID, reproduced, len(manifest.Generations))
+			}
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+
+	return cmd
+}
+
+// replayState loads the local state for breeding a replayed generation.
+// Lineages the replay breeds into are only held in memory, so a loop whose
+// session is not in this workspace breeds into an empty one.
+func replayState(sessionID string) (state.State, error) {
+	st, err := state.Load("")
+	if err != nil {
+		return state.State{}, fmt.Errorf("load state: %w", err)
+	}
+	if st.Sessions == nil {
+		st.Sessions = map[string]state.Session{}
+	}
+	session := st.Sessions[sessionID]
+	if session.Lineages == nil {
+		session.Lineages = map[string]state.Lineage{}
+	}
+	st.Sessions[sessionID] = session
+	return st, nil
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Perttulands/chiron/internal/checkpoint"
+	"github.com/Perttulands/chiron/internal/state"
+	"github.com/Perttulands/chiron/internal/training"
+	"github.com/spf13/cobra"
+)
+
+// reviewExcerptLen is how much of each output a review shows in a table.
+const reviewExcerptLen = 80
+
+func newLoopReviewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "review <loop-id>",
+		Short: "Show the outputs a paused loop is waiting to have scored",
+		Long: `Show the review a training loop paused for: the top contestants of the
+generation and the artifacts of their bouts, with any manual scores given
+so far. Score each artifact with chiron evaluate, then continue the loop
+with chiron loop run --resume; the manual scores are folded into the
+bouts' composite scores before selection.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			loopID := strings.TrimSpace(args[0])
+			cp, err := checkpoint.Load(loopID)
+			if err != nil {
+				return err
+			}
+			loop := &cp.Loop
+			if loop.Review == nil {
+				return fmt.Errorf("loop %q has no pending review (status %s)", loop.ID, loop.Status)
+			}
+			st, err := state.Load("")
+			if err != nil {
+				return fmt.Errorf("load state: %w", err)
+			}
+			return writeReview(cmd, loop, st.Sessions[loop.SessionID])
+		},
+	}
+
+	return cmd
+}
+
+// reviewBout is one bout presented for review.
+type reviewBout struct {
+	ArtifactID  string  `json:"artifact_id"`
+	ChallengeID string  `json:"challenge_id"`
+	Score       float64 `json:"score"`
+	ManualScore *int    `json:"manual_score,omitempty"`
+	Output      string  `json:"output"`
+}
+
+// reviewContestant is one top contestant presented for review.
+type reviewContestant struct {
+	Rank         int          `json:"rank"`
+	ContestantID string       `json:"contestant_id"`
+	LineageID    string       `json:"lineage_id"`
+	AgentID      string       `json:"agent_id"`
+	AvgScore     float64      `json:"avg_score"`
+	Bouts        []reviewBout `json:"bouts"`
+}
+
+// reviewScores returns the manual scores given so far to the artifacts of
+// review's tournament, keyed by artifact id.
+func reviewScores(session state.Session, review *training.PendingReview) map[string]int {
+	wanted := map[string]bool{}
+	for _, round := range review.Tournament.Rounds {
+		for _, bout := range round.Bouts {
+			if bout.ArtifactID != "" {
+				wanted[bout.ArtifactID] = true
+			}
+		}
+	}
+	scores := map[string]int{}
+	for _, lineage := range session.Lineages {
+		for _, artifact := range lineage.Artifacts {
+			if wanted[artifact.ID] && artifact.Evaluation != nil {
+				scores[artifact.ID] = artifact.Evaluation.Score
+			}
+		}
+	}
+	return scores
+}
+
+// reviewContestants lists review's top contestants with their stored bouts.
+func reviewContestants(session state.Session, review *training.PendingReview) []reviewContestant {
+	scores := reviewScores(session, review)
+	trn := review.Tournament
+	byID := map[string]reviewContestant{}
+	for _, s := range trn.Standings {
+		byID[s.ContestantID] = reviewContestant{Rank: s.Rank, ContestantID: s.ContestantID, LineageID: s.LineageID, AvgScore: s.AvgScore, Bouts: []reviewBout{}}
+	}
+	for _, c := range trn.Contestants {
+		if rc, ok := byID[c.ID]; ok {
+			rc.AgentID = c.Agent.ID
+			byID[c.ID] = rc
+		}
+	}
+	for _, round := range trn.Rounds {
+		for _, bout := range round.Bouts {
+			rc, ok := byID[bout.ContestantID]
+			if !ok || bout.ArtifactID == "" {
+				continue
+			}
+			rb := reviewBout{ArtifactID: bout.ArtifactID, ChallengeID: bout.ChallengeID, Score: bout.CompositeScore.FinalScore, Output: bout.Output}
+			if score, ok := scores[bout.ArtifactID]; ok {
+				rb.ManualScore = &score
+			}
+			rc.Bouts = append(rc.Bouts, rb)
+			byID[bout.ContestantID] = rc
+		}
+	}
+
+	out := make([]reviewContestant, 0, len(review.ContestantIDs))
+	for _, id := range review.ContestantIDs {
+		out = append(out, byID[id])
+	}
+	return out
+}
+
+// writeReview presents a loop's pending review.
+func writeReview(cmd *cobra.Command, loop *training.Loop, session state.Session) error {
+	review := loop.Review
+	contestants := reviewContestants(session, review)
+	if isJSONOutput(cmd) {
+		return writeJSON(cmd, map[string]any{
+			"loop_id":     loop.ID,
+			"session_id":  loop.SessionID,
+			"generation":  review.Generation,
+			"trigger":     review.Trigger,
+			"contestants": contestants,
+		})
+	}
+
+	out := cmd.OutOrStdout()
+	if _, err := fmt.Fprintf(out, "generation %d awaits review (%s); score outputs with: chiron evaluate <artifact-id> --score <1-10>\n", review.Generation, review.Trigger); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "RANK\tAGENT\tAVG\tARTIFACT\tCHALLENGE\tSCORE\tMANUAL\tOUTPUT"); err != nil {
+		return fmt.Errorf("write review header: %w", err)
+	}
+	for _, c := range contestants {
+		for _, b := range c.Bouts {
+			manual := "-"
+			if b.ManualScore != nil {
+				manual = fmt.Sprintf("%d", *b.ManualScore)
+			}
+			if _, err := fmt.Fprintf(tw, "%d\t%s\t%.2f\t%s\t%s\t%.2f\t%s\t%s\n",
+				c.Rank, c.AgentID, c.AvgScore, b.ArtifactID, b.ChallengeID, b.Score, manual, reviewExcerpt(b.Output)); err != nil {
+				return fmt.Errorf("write review row %q: %w", b.ArtifactID, err)
+			}
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(out, "full outputs: chiron artifact inspect <artifact-id>; then continue with: chiron loop run %s --challenges <set> --resume %s\n", loop.SessionID, loop.ID); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func reviewExcerpt(output string) string {
+	output = strings.Join(strings.Fields(output), " ")
+	if output == "" {
+		return "-"
+	}
+	if len(output) > reviewExcerptLen {
+		return output[:reviewExcerptLen-3] + "..."
+	}
+	return output
+}
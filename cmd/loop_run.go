@@ -0,0 +1,696 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Perttulands/chiron/internal/challenge"
+	"github.com/Perttulands/chiron/internal/checkpoint"
+	"github.com/Perttulands/chiron/internal/cost"
+	"github.com/Perttulands/chiron/internal/mutation"
+	"github.com/Perttulands/chiron/internal/provider"
+	"github.com/Perttulands/chiron/internal/rating"
+	"github.com/Perttulands/chiron/internal/selection"
+	"github.com/Perttulands/chiron/internal/state"
+	"github.com/Perttulands/chiron/internal/tournament"
+	"github.com/Perttulands/chiron/internal/training"
+	"github.com/spf13/cobra"
+)
+
+// Checkpoint reasons written by loop run.
+const (
+	checkpointGeneration = "generation_complete"
+	checkpointRound      = "round_complete"
+	checkpointBudget     = "budget_exhausted"
+	checkpointReview     = "review"
+	checkpointError      = "error"
+)
+
+func newLoopRunCmd() *cobra.Command {
+	var flags tournamentProviderFlags
+	var budget budgetFlags
+	var challengesPath string
+	var generations int
+	var selectCount int
+	var strategy string
+	var operatorSchedule string
+	var operators []string
+	var targetScore float64
+	var concurrency int
+	var repetitions int
+	var seed int64
+	var mutatorProvider string
+	var mutatorModel string
+	var resumeID string
+	var keepCheckpoints int
+	var review training.ReviewConfig
+	var curriculum training.CurriculumConfig
+
+	cmd := &cobra.Command{
+		Use:   "run <session-id>",
+		Short: "Evolve a session's lineages with a training loop",
+		Long: `Run a training loop over the latest agent of every lineage in a session.
+
+Each generation plays a tournament on the challenge set, keeps the
+contestants selection picks and replaces the others with mutated children
+of the winners, using the configured operator schedule. Children become new
+agent versions in their parent's lineage and every bout is stored as an
+artifact. A checkpoint is written after every round and every generation;
+the last --keep-checkpoints generation checkpoints are kept under
+.chiron/checkpoints/<loop-id>/. With --resume a loop stopped mid-generation
+replays the rounds it finished and plays the rest, and a corrupt latest
+checkpoint falls back to the newest generation checkpoint that verifies.
+
+With --budget-usd or --budget-tokens every execution, grading and mutation
+call is counted. Before each generation the loop projects its cost and
+stops if it would pass the budget; a generation that has started always
+finishes. A loop stopped by its budget is paused with a checkpoint and
+continues with --resume and a larger budget.
+
+With --review-every or --review-on-best the loop pauses for human review
+after a generation's tournament, before selection: the outputs of the top
+--review-top contestants are stored as artifacts and listed. Score them
+with chiron evaluate, then continue with --resume; the manual scores are
+folded into the bouts' composite scores and selection runs on the result.
+chiron loop review shows a pending review again.
+
+With --curriculum each generation plays one difficulty tier of the set,
+starting with the easiest. Once the population averages --promote-score on
+its tier the next tier unlocks, and --resurface-failed replays challenges
+of easier tiers that no contestant passed until one does. Every generation
+reports its mean score per difficulty.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sessionID := strings.TrimSpace(args[0])
+			if err := budget.validate(); err != nil {
+				return err
+			}
+			if strings.TrimSpace(challengesPath) == "" {
+				return fmt.Errorf("--challenges is required")
+			}
+			set, err := challenge.LoadSet(challengesPath)
+			if err != nil {
+				return err
+			}
+
+			st, err := state.Load("")
+			if err != nil {
+				return fmt.Errorf("load state: %w", err)
+			}
+			session, ok := st.Sessions[sessionID]
+			if !ok {
+				return fmt.Errorf("session %q not found", sessionID)
+			}
+
+			var loop *training.Loop
+			if id := strings.TrimSpace(resumeID); id != "" {
+				cp, err := checkpoint.Load(id)
+				if err != nil {
+					return err
+				}
+				if cp.Recovered != "" {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "warning: latest checkpoint unusable (%s); resuming from %s, generation %d\n", cp.Recovered, cp.Path, cp.Generation)
+				}
+				loop = &cp.Loop
+				if loop.SessionID != sessionID {
+					return fmt.Errorf("loop %q belongs to session %q, not %q", loop.ID, loop.SessionID, sessionID)
+				}
+				if loop.IsComplete() {
+					return fmt.Errorf("loop %q is %s (%s)", loop.ID, loop.Status, loop.StopReason)
+				}
+				loop.Config.IDFunc = newPrefixedID
+				loop.Config.ManifestDir = training.DefaultManifestDir
+				if cmd.Flags().Changed("budget-usd") {
+					loop.Config.BudgetUSD = budget.usd
+				}
+				if cmd.Flags().Changed("budget-tokens") {
+					loop.Config.BudgetTokens = budget.tokens
+				}
+				if cmd.Flags().Changed("review-every") {
+					loop.Config.Review.Every = review.Every
+				}
+				if cmd.Flags().Changed("review-on-best") {
+					loop.Config.Review.OnNewBest = review.OnNewBest
+				}
+				if cmd.Flags().Changed("review-top") {
+					loop.Config.Review.Top = review.Top
+				}
+			} else {
+				contestants, err := sessionContestants(session)
+				if err != nil {
+					return err
+				}
+				cfg := training.DefaultConfig(newPrefixedID)
+				cfg.MaxGenerations = generations
+				cfg.SelectionCount = selectCount
+				cfg.SelectionStrategy = strategy
+				cfg.OperatorSchedule = operatorSchedule
+				cfg.Operators = operators
+				cfg.TargetScore = targetScore
+				cfg.Seed = seed
+				cfg.Schedule.Concurrency = concurrency
+				cfg.Schedule.Repetitions = repetitions
+				cfg.BudgetUSD = budget.usd
+				cfg.BudgetTokens = budget.tokens
+				cfg.Review = review
+				cfg.Curriculum = curriculum
+				loop, err = training.NewLoop(cfg, contestants)
+				if err != nil {
+					return err
+				}
+				loop.SessionID = sessionID
+				loop.Ratings = rating.Table{}
+				for id, r := range st.Ratings {
+					loop.Ratings[id] = r
+				}
+			}
+
+			// A loop's budget covers every invocation, so only what is left
+			// of it applies to this one.
+			errOut := cmd.ErrOrStderr()
+			// Rounds of an interrupted generation were paid for but are not
+			// yet in the loop's totals.
+			spentUSD, spentTokens := loop.SpentUSD, loop.SpentTokens
+			if loop.Partial != nil {
+				spentUSD += loop.Partial.CostUSD
+				spentTokens += loop.Partial.Tokens
+			}
+			if loop.Review != nil {
+				spentUSD += loop.Review.CostUSD
+				spentTokens += loop.Review.Tokens
+			}
+			remainingUSD, remainingTokens := loop.Config.BudgetUSD, loop.Config.BudgetTokens
+			if remainingUSD > 0 {
+				remainingUSD -= spentUSD
+			}
+			if remainingTokens > 0 {
+				remainingTokens -= spentTokens
+			}
+			tracker := newCostTracker(budgetFlags{usd: max(remainingUSD, 0), tokens: max(remainingTokens, 0)})
+			flags.tracker = tracker
+			flags.seed = loop.Config.Seed
+
+			run := &loopRun{
+				sessionID:       sessionID,
+				loop:            loop,
+				flags:           &flags,
+				tracker:         tracker,
+				mutatorProvider: mutatorProvider,
+				mutatorModel:    mutatorModel,
+				adapters:        map[string]provider.Provider{},
+				keep:            keepCheckpoints,
+				progress:        errOut,
+			}
+			loop.Config.Providers = run.identities()
+			grader, err := flags.grader(&tournament.Tournament{Contestants: loop.Contestants, Challenges: set.Challenges})
+			if err != nil {
+				return fmt.Errorf("loop %q: %w", loop.ID, err)
+			}
+			loop.Config.Grader = grader
+			loop.Config.Schedule.Progress = tournamentProgress(errOut)
+			loop.Config.Schedule.BoutContext = attributeBout
+			loop.Config.OnRound = run.roundDone
+			ctx := cost.WithAttribution(cmd.Context(), cost.Attribution{SessionID: sessionID})
+
+			if loop.Review != nil {
+				applied, err := loop.ApplyReview(reviewScores(session, loop.Review))
+				if err != nil {
+					return err
+				}
+				if applied == 0 {
+					_, _ = fmt.Fprintf(errOut, "warning: no artifacts of generation %d were evaluated; selecting on automatic scores\n", loop.Review.Generation)
+				} else {
+					_, _ = fmt.Fprintf(errOut, "generation %d: %d manual scores applied\n", loop.Review.Generation, applied)
+				}
+			}
+
+			budgetSpent := loop.Config.BudgetUSD > 0 && remainingUSD <= 0 || loop.Config.BudgetTokens > 0 && remainingTokens <= 0
+			for !loop.IsComplete() && !budgetSpent {
+				// A reviewed generation has already played its tournament.
+				if loop.Review == nil && (loop.Config.BudgetUSD > 0 || loop.Config.BudgetTokens > 0) {
+					projection := loop.ProjectGeneration(set.Challenges)
+					if projection.Unpriced > 0 && loop.Config.BudgetUSD > 0 {
+						_, _ = fmt.Fprintf(errOut, "warning: %d projected calls use models without a known price and are counted as free\n", projection.Unpriced)
+					}
+					if err := tracker.CheckProjection(projection); err != nil {
+						_, _ = fmt.Fprintf(errOut, "generation %d not started: %v\n", loop.CurrentGeneration()+1, err)
+						budgetSpent = true
+						break
+					}
+				}
+
+				err := run.generation(ctx, set.Challenges, errOut)
+				if errors.Is(err, training.ErrReviewPending) {
+					break
+				}
+				if err != nil {
+					if cpErr := run.save(checkpointError); cpErr != nil {
+						return errors.Join(err, cpErr)
+					}
+					return err
+				}
+				budgetSpent = tracker.Exhausted()
+			}
+
+			reason := checkpointGeneration
+			switch {
+			case loop.StopReason == training.StopReview:
+				reason = checkpointReview
+			case budgetSpent && !loop.IsComplete():
+				loop.StopForBudget()
+				reason = checkpointBudget
+			}
+			if err := run.save(reason); err != nil {
+				return err
+			}
+			warnLedger(errOut, tracker)
+			if loop.StopReason == training.StopReview {
+				st, err := state.Load("")
+				if err != nil {
+					return fmt.Errorf("load state: %w", err)
+				}
+				return writeReview(cmd, loop, st.Sessions[sessionID])
+			}
+			return writeLoopRun(cmd, loop, tracker.Summarize())
+		},
+	}
+
+	flags.register(cmd)
+	budget.register(cmd)
+	cmd.Flags().StringVar(&challengesPath, "challenges", "", "Challenge set file the contestants play")
+	cmd.Flags().IntVar(&generations, "generations", 10, "Generations to run")
+	cmd.Flags().IntVar(&selectCount, "select", 2, "Contestants selection keeps each generation")
+	cmd.Flags().StringVar(&strategy, "strategy", selection.StrategyTruncation, "Selection strategy: "+strings.Join(selection.ValidStrategies, ", "))
+	cmd.Flags().StringVar(&operatorSchedule, "operator-schedule", mutation.ScheduleRandom, "Mutation operator schedule: "+strings.Join(mutation.ValidSchedules, ", "))
+	cmd.Flags().StringSliceVar(&operators, "operators", nil, "Mutation operators to schedule (default: all)")
+	cmd.Flags().Float64Var(&targetScore, "target-score", 9.0, "Stop once a contestant averages this score")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 0, "Bouts in flight")
+	cmd.Flags().IntVar(&repetitions, "repetitions", 0, "Bouts per contestant and challenge")
+	cmd.Flags().Int64Var(&seed, "seed", 0, "Loop seed (default: random)")
+	cmd.Flags().StringVar(&mutatorProvider, "mutator-provider", "", "Provider for mutations (default: the bout provider)")
+	cmd.Flags().StringVar(&mutatorModel, "mutator-model", "", "Model for mutations (default: the parent's model)")
+	cmd.Flags().StringVar(&resumeID, "resume", "", "Continue the checkpointed loop with this id")
+	cmd.Flags().IntVar(&review.Every, "review-every", 0, "Pause for review after every N generations (0: never)")
+	cmd.Flags().BoolVar(&review.OnNewBest, "review-on-best", false, "Pause for review when a contestant beats the loop's best score")
+	cmd.Flags().IntVar(&review.Top, "review-top", training.DefaultReviewTop, "Contestants whose outputs a review presents")
+	cmd.Flags().BoolVar(&curriculum.Enabled, "curriculum", false, "Train on one challenge difficulty at a time, easiest first")
+	cmd.Flags().Float64Var(&curriculum.PromoteScore, "promote-score", training.DefaultPromoteScore, "Population average on a tier that unlocks the next")
+	cmd.Flags().Float64Var(&curriculum.FailScore, "fail-score", training.DefaultFailScore, "A challenge fails when no contestant averages this on it")
+	cmd.Flags().BoolVar(&curriculum.ResurfaceFailed, "resurface-failed", true, "Replay failed challenges of easier tiers until they pass")
+	cmd.Flags().IntVar(&keepCheckpoints, "keep-checkpoints", checkpoint.DefaultKeep, "Generation checkpoints to keep (-1 keeps all)")
+
+	return cmd
+}
+
+// loopRun holds what a training loop run needs between generations.
+type loopRun struct {
+	sessionID       string
+	loop            *training.Loop
+	flags           *tournamentProviderFlags
+	tracker         *cost.Tracker
+	mutatorProvider string
+	mutatorModel    string
+	adapters        map[string]provider.Provider // mutation providers by model
+	keep            int                          // generation checkpoints to keep
+	progress        io.Writer
+
+	// Spend before the generation in progress started, and what earlier
+	// runs of it spent, so round checkpoints carry the generation's spend.
+	genStart      cost.Summary
+	carriedUSD    float64
+	carriedTokens int
+}
+
+// sessionContestants returns the latest agent of every lineage, by name.
+func sessionContestants(session state.Session) ([]tournament.Contestant, error) {
+	lineages := make([]state.Lineage, 0, len(session.Lineages))
+	for _, lineage := range session.Lineages {
+		if len(lineage.Agents) > 0 {
+			lineages = append(lineages, lineage)
+		}
+	}
+	sort.Slice(lineages, func(i, j int) bool { return lineages[i].Name < lineages[j].Name })
+
+	contestants := make([]tournament.Contestant, 0, len(lineages))
+	for _, lineage := range lineages {
+		agent, _ := latestAgent(lineage)
+		contestants = append(contestants, tournament.Contestant{ID: newPrefixedID("cst"), LineageID: lineage.ID, Agent: agent})
+	}
+	if len(contestants) < 2 {
+		return nil, fmt.Errorf("session %q needs at least 2 lineages with agents, has %d", session.ID, len(contestants))
+	}
+	return contestants, nil
+}
+
+// identities describes the providers of the run for the manifest and the
+// cost projection.
+func (r *loopRun) identities() []training.ProviderIdentity {
+	executor, grader := r.flags.identities(r.loop.Contestants)
+	mutator := modelOrDefault(r.mutatorProvider, executor.Provider)
+	return []training.ProviderIdentity{
+		executor,
+		grader,
+		{Role: training.RoleMutator, Provider: mutator, Model: strings.TrimSpace(r.mutatorModel), BaseURL: r.flags.baseURL, Seeded: provider.SeedSupported(mutator)},
+	}
+}
+
+// generation plays one generation, records it in the session, breeds the
+// next contestants and checkpoints the loop.
+func (r *loopRun) generation(ctx context.Context, challenges []challenge.Challenge, progress io.Writer) error {
+	loop := r.loop
+	r.genStart = r.tracker.Summarize()
+	r.carriedUSD, r.carriedTokens = 0, 0
+	if p := loop.Partial; p != nil && p.Number == loop.CurrentGeneration()+1 {
+		r.carriedUSD, r.carriedTokens = p.CostUSD, p.Tokens
+	}
+	if rv := loop.Review; rv != nil && rv.Generation == loop.CurrentGeneration()+1 {
+		r.carriedUSD, r.carriedTokens = rv.CostUSD, rv.Tokens
+	}
+
+	loop.Config.Schedule.Provider = r.flags.executingProvider(loop.Contestants)
+	gen, err := loop.RunGeneration(ctx, challenges, r.flags.executor(loop.Contestants))
+	if errors.Is(err, training.ErrReviewPending) {
+		if err := r.holdForReview(); err != nil {
+			return err
+		}
+		return training.ErrReviewPending
+	}
+	if err != nil {
+		return err
+	}
+
+	// Reload so changes made while the tournament ran are kept.
+	st, err := state.Load("")
+	if err != nil {
+		return fmt.Errorf("load state: %w", err)
+	}
+	if _, err := loop.RecordGeneration(&st, r.sessionID, *gen); err != nil {
+		return fmt.Errorf("record generation %d: %w", gen.Number, err)
+	}
+
+	if !loop.IsComplete() {
+		next, err := r.breed(ctx, &st, *gen)
+		if err != nil {
+			return fmt.Errorf("mutate generation %d: %w", gen.Number, err)
+		}
+		loop.SetContestants(next)
+	}
+
+	costUSD, tokens := r.generationSpend()
+	loop.RecordSpend(costUSD, tokens)
+
+	if st.Ratings == nil {
+		st.Ratings = map[string]state.Rating{}
+	}
+	for id, rt := range loop.Ratings {
+		st.Ratings[id] = rt
+	}
+	if err := state.Save("", st); err != nil {
+		return fmt.Errorf("save state: %w", err)
+	}
+	if err := r.save(checkpointGeneration); err != nil {
+		return err
+	}
+
+	last := loop.Generations[len(loop.Generations)-1]
+	_, _ = fmt.Fprintf(progress, "generation %d: best %.2f avg %.2f, kept %d, cost $%.4f (%d tokens)\n",
+		last.Number, last.BestScore, last.AvgScore, len(last.Winners), last.CostUSD, last.Tokens)
+	if last.PromotedTo != "" {
+		_, _ = fmt.Fprintf(progress, "curriculum: promoted from %s to %s\n", last.Tier, last.PromotedTo)
+	}
+	return nil
+}
+
+// holdForReview stores the bouts of the contestants a review presents as
+// artifacts, so they can be evaluated, and carries the tournament's spend
+// in the review.
+func (r *loopRun) holdForReview() error {
+	review := r.loop.Review
+	review.CostUSD, review.Tokens = r.generationSpend()
+
+	st, err := state.Load("")
+	if err != nil {
+		return fmt.Errorf("load state: %w", err)
+	}
+	if _, err := tournament.RecordBouts(&st, &review.Tournament, r.sessionID, review.ContestantIDs); err != nil {
+		return fmt.Errorf("store generation %d for review: %w", review.Generation, err)
+	}
+	if err := state.Save("", st); err != nil {
+		return fmt.Errorf("save state: %w", err)
+	}
+	return nil
+}
+
+// generationSpend returns what the generation in progress has cost,
+// including rounds played before it was resumed.
+func (r *loopRun) generationSpend() (float64, int) {
+	now := r.tracker.Summarize()
+	return r.carriedUSD + now.TotalCostUSD - r.genStart.TotalCostUSD,
+		r.carriedTokens + now.TotalTokensIn + now.TotalTokensOut - r.genStart.TotalTokensIn - r.genStart.TotalTokensOut
+}
+
+// roundDone checkpoints the loop after every finished round, so a crash
+// loses at most the round in flight. A failed save is reported and the
+// tournament goes on; the next round tries again.
+func (r *loopRun) roundDone(loop *training.Loop) {
+	loop.Partial.CostUSD, loop.Partial.Tokens = r.generationSpend()
+	if err := r.save(checkpointRound); err != nil {
+		_, _ = fmt.Fprintf(r.progress, "warning: %v\n", err)
+	}
+}
+
+// save checkpoints the loop, keeping r.keep generation checkpoints.
+func (r *loopRun) save(reason string) error {
+	return checkpoint.Write("", r.loop, reason, checkpoint.Options{Keep: r.keep})
+}
+
+// breed keeps the generation's winners and fills every eliminated slot with
+// a child of a winner, made by the operator the loop's schedule chooses.
+// Children are added to their parent's lineage in st.
+func (r *loopRun) breed(ctx context.Context, st *state.State, gen training.Generation) ([]tournament.Contestant, error) {
+	session := st.Sessions[r.sessionID]
+	byID := map[string]tournament.Contestant{}
+	for _, c := range r.loop.Contestants {
+		byID[c.ID] = c
+	}
+
+	next := make([]tournament.Contestant, 0, len(r.loop.Contestants))
+	parents := []tournament.Contestant{}
+	for _, w := range gen.Winners {
+		c := byID[w.ContestantID]
+		next = append(next, c)
+		if !session.Lineages[c.LineageID].Locked {
+			parents = append(parents, c)
+		}
+	}
+	if len(gen.Eliminated) == 0 {
+		return next, nil
+	}
+	if len(parents) == 0 {
+		return nil, fmt.Errorf("every winning lineage is locked")
+	}
+
+	rng := r.loop.MutationRand(gen.Number)
+	var candidates []state.FewShotExample
+	for i := range gen.Eliminated {
+		parent := parents[i%len(parents)]
+		var partner *tournament.Contestant
+		if len(parents) > 1 {
+			partner = &parents[(i+1)%len(parents)]
+		}
+
+		name := r.loop.ChooseOperator(rng)
+		if strings.HasPrefix(name, "example_") && candidates == nil {
+			candidates = mutation.ExampleCandidates(session, mutation.ExampleOptions{})
+		}
+		op, ok := r.operator(name, parent, partner, session, gen, candidates, rng)
+		if !ok {
+			op = mutation.RandomOperator(rng)
+		}
+
+		adapter, err := r.mutator(parent.Agent)
+		if err != nil {
+			return nil, err
+		}
+		mutateCtx := cost.WithAttribution(ctx, cost.Attribution{LineageID: parent.LineageID, AgentID: parent.Agent.ID})
+		m, err := op.Mutate(mutateCtx, parent.Agent.Definition, adapter)
+		if err != nil && op.Name() != mutation.OpRephrase {
+			// Operators can fail on the parent they were given, e.g. an
+			// example swap with no unused candidates; fall back once.
+			op = mutation.RephraseOp{}
+			m, err = op.Mutate(mutateCtx, parent.Agent.Definition, adapter)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s on %s: %w", op.Name(), parent.Agent.ID, err)
+		}
+
+		child := m.Child(newPrefixedID("agt"), parent.Agent)
+		lineage := session.Lineages[parent.LineageID]
+		lineage.Agents = append(lineage.Agents, child)
+		session.Lineages[parent.LineageID] = lineage
+		next = append(next, tournament.Contestant{ID: newPrefixedID("cst"), LineageID: parent.LineageID, Agent: child})
+	}
+	st.Sessions[r.sessionID] = session
+	return next, nil
+}
+
+// operator configures the named operator for parent. It reports false when
+// the operator has nothing to work with, such as a crossover without a
+// partner or a failure-driven mutation of a parent that did not fail.
+func (r *loopRun) operator(name string, parent tournament.Contestant, partner *tournament.Contestant, session state.Session, gen training.Generation, candidates []state.FewShotExample, rng *rand.Rand) (mutation.Operator, bool) {
+	switch name {
+	case mutation.OpCrossover:
+		if partner == nil {
+			return nil, false
+		}
+		return mutation.CrossoverOp{Partner: partner.Agent.Definition, PartnerID: partner.Agent.ID}, true
+	case mutation.OpSectionCrossover:
+		if partner == nil {
+			return nil, false
+		}
+		return mutation.SectionCrossoverOp{Partner: partner.Agent.Definition, PartnerID: partner.Agent.ID, Rand: rng}, true
+	case mutation.OpTargeted:
+		directives := session.Lineages[parent.LineageID].Directives
+		texts := []string{}
+		for _, d := range append(append([]state.Directive{}, directives.Sticky...), directives.Oneshot...) {
+			texts = append(texts, d.Text)
+		}
+		if len(texts) == 0 {
+			return nil, false
+		}
+		return mutation.TargetedOp{Directive: strings.Join(texts, "\n")}, true
+	case mutation.OpFailureDriven:
+		failures := mutation.CollectFailures(parent.ID, gen.Tournament.Rounds, mutation.FailureOptions{})
+		if len(failures) == 0 {
+			return nil, false
+		}
+		return mutation.FailureDrivenOp{Failures: failures}, true
+	case mutation.OpSection:
+		return mutation.SectionOp{Rand: rng}, true
+	case mutation.OpExampleAdd:
+		return mutation.ExampleAddOp{Candidates: candidates, Rand: rng}, true
+	case mutation.OpExampleRemove:
+		return mutation.ExampleRemoveOp{Rand: rng}, true
+	case mutation.OpExampleSwap:
+		return mutation.ExampleSwapOp{Candidates: candidates, Rand: rng}, true
+	default:
+		op, err := mutation.NewOperator(name)
+		return op, err == nil
+	}
+}
+
+// mutator returns the tracked provider that mutates agent.
+func (r *loopRun) mutator(agent state.Agent) (provider.Provider, error) {
+	model := modelOrDefault(r.mutatorModel, agent.Definition.Model)
+	if adapter, ok := r.adapters[model]; ok {
+		return adapter, nil
+	}
+	name := modelOrDefault(r.mutatorProvider, modelOrDefault(r.flags.provider, agent.GenerationMetadata.Provider))
+	adapter, err := provider.NewFactory(provider.Config{
+		Provider: name,
+		Model:    model,
+		BaseURL:  r.flags.baseURL,
+		APIKey:   r.flags.apiKey,
+		Seed:     r.flags.seed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("configure mutation provider: %w", err)
+	}
+	adapter = cost.Track(adapter, r.tracker, cost.OpMutate)
+	r.adapters[model] = adapter
+	return adapter, nil
+}
+
+func writeLoopRun(cmd *cobra.Command, loop *training.Loop, spend cost.Summary) error {
+	if isJSONOutput(cmd) {
+		return writeJSON(cmd, map[string]any{
+			"loop_id":      loop.ID,
+			"session_id":   loop.SessionID,
+			"status":       loop.Status,
+			"stop_reason":  loop.StopReason,
+			"generations":  len(loop.Generations),
+			"best_score":   loop.BestScore,
+			"spent_usd":    loop.SpentUSD,
+			"spent_tokens": loop.SpentTokens,
+			"spend":        spend,
+			"checkpoint":   checkpoint.DefaultPath(loop.ID),
+		})
+	}
+
+	out := cmd.OutOrStdout()
+	if _, err := fmt.Fprintf(out, "loop_id=%s status=%s stop_reason=%s best=%.2f spent=$%.4f tokens=%d\n",
+		loop.ID, loop.Status, orNone(loop.StopReason), loop.BestScore, loop.SpentUSD, loop.SpentTokens); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	// One score column per difficulty any generation played.
+	difficulties := []string{}
+	for _, d := range challenge.ValidDifficulties {
+		for _, gen := range loop.Generations {
+			if _, ok := gen.Difficulty[d]; ok {
+				difficulties = append(difficulties, d)
+				break
+			}
+		}
+	}
+	curriculum := loop.Config.Curriculum.Enabled
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	header := "GEN\tBEST\tAVG\tKEPT\tCOST_USD\tTOKENS"
+	if curriculum {
+		header += "\tTIER"
+	}
+	for _, d := range difficulties {
+		header += "\t" + strings.ToUpper(d)
+	}
+	if _, err := fmt.Fprintln(tw, header); err != nil {
+		return fmt.Errorf("write generation header: %w", err)
+	}
+	for _, gen := range loop.Generations {
+		row := fmt.Sprintf("%d\t%.2f\t%.2f\t%d\t%.4f\t%d", gen.Number, gen.BestScore, gen.AvgScore, len(gen.Winners), gen.CostUSD, gen.Tokens)
+		if curriculum {
+			tier := gen.Tier
+			if gen.PromotedTo != "" {
+				tier += " -> " + gen.PromotedTo
+			}
+			row += "\t" + tier
+		}
+		for _, d := range difficulties {
+			if score, ok := gen.Difficulty[d]; ok {
+				row += fmt.Sprintf("\t%.2f", score)
+			} else {
+				row += "\t-"
+			}
+		}
+		if _, err := fmt.Fprintln(tw, row); err != nil {
+			return fmt.Errorf("write generation row %d: %w", gen.Number, err)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	writeSpend(out, spend)
+	if loop.StopReason == training.StopBudget {
+		flag := "--budget-usd"
+		if loop.Config.BudgetUSD == 0 {
+			flag = "--budget-tokens"
+		}
+		_, err := fmt.Fprintf(out, "budget reached; continue with: chiron loop run %s --challenges <set> --resume %s %s <more>\n", loop.SessionID, loop.ID, flag)
+		if err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+	}
+	return nil
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Perttulands/chiron/internal/cost"
 	"github.com/Perttulands/chiron/internal/engine"
 	"github.com/Perttulands/chiron/internal/provider"
 	"github.com/Perttulands/chiron/internal/state"
@@ -76,6 +77,8 @@ func newPromoteCmd() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("initialize provider: %w", err)
 			}
+			tracker := newLedgerTracker()
+			adapter = cost.Track(adapter, tracker, cost.OpGenerate)
 
 			now := time.Now().UTC().Format(time.RFC3339)
 			lineages := make(map[string]state.Lineage, len(variants))
@@ -90,7 +93,7 @@ func newPromoteCmd() *cobra.Command {
 					variant.strategy,
 				)
 
-				agentDef, generationMeta, err := engine.GenerateAgentDefinitionWithMetadata(cmd.Context(), promotionPrompt, nil, adapter)
+				agentDef, generationMeta, err := engine.GenerateAgentDefinitionWithMetadata(cost.WithAttribution(cmd.Context(), cost.Attribution{SessionID: sessionID, LineageID: lineageID, AgentID: agentID}), promotionPrompt, nil, adapter)
 				if err != nil {
 					return fmt.Errorf("generate agent for lineage %s: %w", variant.name, err)
 				}
@@ -122,6 +125,7 @@ func newPromoteCmd() *cobra.Command {
 			if err := state.Save("", st); err != nil {
 				return fmt.Errorf("save state: %w", err)
 			}
+			warnLedger(cmd.ErrOrStderr(), tracker)
 
 			if isJSONOutput(cmd) {
 				return writeJSON(cmd, map[string]any{
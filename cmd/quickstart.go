@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Perttulands/chiron/internal/cost"
 	"github.com/Perttulands/chiron/internal/engine"
 	"github.com/Perttulands/chiron/internal/provider"
 	"github.com/Perttulands/chiron/internal/state"
@@ -26,6 +27,8 @@ func newQuickstartInitCmd() *cobra.Command {
 	var model string
 	var baseURL string
 	var apiKey string
+	var suitePath string
+	var challengesPath string
 
 	cmd := &cobra.Command{
 		Use:   "init",
@@ -36,6 +39,11 @@ func newQuickstartInitCmd() *cobra.Command {
 				return fmt.Errorf("load state: %w", err)
 			}
 
+			sessionHarness, err := initialSessionHarness(suitePath, challengesPath)
+			if err != nil {
+				return fmt.Errorf("load session harness: %w", err)
+			}
+
 			now := time.Now().UTC().Format(time.RFC3339)
 			sessionID := newPrefixedID("ses")
 			lineageID := newPrefixedID("lin")
@@ -50,8 +58,10 @@ func newQuickstartInitCmd() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("initialize provider: %w", err)
 			}
+			tracker := newLedgerTracker()
+			adapter = cost.Track(adapter, tracker, cost.OpGenerate)
 
-			agentDef, generationMeta, err := engine.GenerateAgentDefinitionWithMetadata(cmd.Context(), need, nil, adapter)
+			agentDef, generationMeta, err := engine.GenerateAgentDefinitionWithMetadata(cost.WithAttribution(cmd.Context(), cost.Attribution{SessionID: sessionID, LineageID: lineageID, AgentID: agentID}), need, nil, adapter)
 			if err != nil {
 				return fmt.Errorf("generate agent: %w", err)
 			}
@@ -82,11 +92,13 @@ func newQuickstartInitCmd() *cobra.Command {
 				CreatedAt: now,
 				Status:    "active",
 				Lineages:  map[string]state.Lineage{lineageID: mainLineage},
+				Harness:   sessionHarness,
 			}
 
 			if err := state.Save("", st); err != nil {
 				return fmt.Errorf("save state: %w", err)
 			}
+			warnLedger(cmd.ErrOrStderr(), tracker)
 
 			if isJSONOutput(cmd) {
 				return writeJSON(cmd, map[string]any{
@@ -110,6 +122,8 @@ func newQuickstartInitCmd() *cobra.Command {
 	cmd.Flags().StringVar(&model, "model", "", "Override provider model")
 	cmd.Flags().StringVar(&baseURL, "base-url", "", "Override provider base URL")
 	cmd.Flags().StringVar(&apiKey, "api-key", "", "Override provider API key")
+	cmd.Flags().StringVar(&suitePath, "suite", "", "Test suite that scores every run in the session")
+	cmd.Flags().StringVar(&challengesPath, "challenges", "", "Challenge set whose challenges become the session's dataset rows")
 	_ = cmd.MarkFlagRequired("need")
 
 	return cmd
@@ -1,6 +1,9 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"github.com/Perttulands/chiron/internal/harness"
+	"github.com/spf13/cobra"
+)
 
 func Execute() error {
 	return newRootCmd().Execute()
@@ -10,8 +13,14 @@ func newRootCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "chiron",
 		Short: "Chiron — train AI agents through iterative evaluation",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			if unsandboxed, _ := cmd.Flags().GetBool("unsandboxed-exec"); unsandboxed {
+				cmd.SetContext(harness.WithUnsandboxedExec(cmd.Context()))
+			}
+		},
 	}
 	cmd.PersistentFlags().Bool("json", false, "Output JSON")
+	cmd.PersistentFlags().Bool("unsandboxed-exec", false, "Run code_exec test commands on the host instead of in bwrap (trusted challenges only)")
 
 	cmd.AddCommand(newSessionCmd())
 	cmd.AddCommand(newQuickstartCmd())
@@ -26,6 +35,11 @@ func newRootCmd() *cobra.Command {
 	cmd.AddCommand(newExportCmd())
 	cmd.AddCommand(newDoctorCmd())
 	cmd.AddCommand(newExperimentCmd())
+	cmd.AddCommand(newChallengeCmd())
+	cmd.AddCommand(newLeaderboardCmd())
+	cmd.AddCommand(newTournamentCmd())
+	cmd.AddCommand(newLoopCmd())
+	cmd.AddCommand(newCostCmd())
 
 	return cmd
 }
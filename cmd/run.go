@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/Perttulands/chiron/internal/cost"
 	"github.com/Perttulands/chiron/internal/engine"
+	"github.com/Perttulands/chiron/internal/harness"
 	"github.com/Perttulands/chiron/internal/provider"
 	"github.com/Perttulands/chiron/internal/state"
 	"github.com/spf13/cobra"
@@ -19,10 +21,13 @@ func newRunCmd() *cobra.Command {
 	var model string
 	var baseURL string
 	var apiKey string
-	var harness string
+	var harnessScript string
 	var harnessModel string
 	var condition string
 	var runNumber int
+	var rowID string
+	var judgeProvider string
+	var judgeModel string
 
 	cmd := &cobra.Command{
 		Use:   "run <session-id>",
@@ -60,6 +65,23 @@ func newRunCmd() *cobra.Command {
 				return fmt.Errorf("lineage %q has no agents", selectedLineage)
 			}
 
+			suite, row, err := session.SuiteFor(input, rowID)
+			if err != nil {
+				return fmt.Errorf("run session=%q lineage=%q: select test suite: %w", sessionID, selectedLineage, err)
+			}
+			if row != nil && strings.TrimSpace(rowID) != "" {
+				if input != "" && input != row.Input {
+					return fmt.Errorf("--input does not match the input of dataset row %q", row.ID)
+				}
+				input = row.Input
+			}
+			if input == "" {
+				return fmt.Errorf("--input or --row is required")
+			}
+
+			tracker := newLedgerTracker()
+			ctx := cost.WithAttribution(cmd.Context(), cost.Attribution{SessionID: sessionID, LineageID: lineage.ID, AgentID: agent.ID})
+
 			request := engine.ExecuteRequest{
 				Mode:       mode,
 				Input:      input,
@@ -68,7 +90,7 @@ func newRunCmd() *cobra.Command {
 			}
 
 			if strings.TrimSpace(mode) == engine.ExecutionModeSealed {
-				request.HarnessScript = harness
+				request.HarnessScript = harnessScript
 				request.HarnessModel = harnessModel
 				request.Condition = condition
 				request.RunNumber = runNumber
@@ -86,10 +108,10 @@ func newRunCmd() *cobra.Command {
 				if err != nil {
 					return fmt.Errorf("run session=%q lineage=%q: configure provider: %w", sessionID, selectedLineage, err)
 				}
-				request.Provider = adapter
+				request.Provider = cost.Track(adapter, tracker, cost.OpExecute)
 			}
 
-			result, err := engine.Execute(cmd.Context(), request)
+			result, err := engine.Execute(ctx, request)
 			if err != nil {
 				return fmt.Errorf("run session=%q lineage=%q: execute agent: %w", sessionID, selectedLineage, err)
 			}
@@ -100,14 +122,54 @@ func newRunCmd() *cobra.Command {
 				Output:            result.Output,
 				ExecutionMetadata: result.Metadata,
 			}
+			if row != nil {
+				artifact.DatasetRowID = row.ID
+			}
+
+			if suite != nil && len(suite.TestCases) > 0 {
+				var grader harness.Grader
+				if suite.NeedsGrader() {
+					judgeName := strings.TrimSpace(judgeProvider)
+					if judgeName == "" {
+						judgeName = strings.TrimSpace(providerName)
+					}
+					if judgeName == "" {
+						judgeName = strings.TrimSpace(agent.GenerationMetadata.Provider)
+					}
+					judge, err := provider.NewFactory(provider.Config{
+						Provider: judgeName,
+						Model:    modelOrDefault(judgeModel, agent.Definition.Model),
+						BaseURL:  baseURL,
+						APIKey:   apiKey,
+					})
+					if err != nil {
+						return fmt.Errorf("run session=%q lineage=%q: configure judge provider: %w", sessionID, selectedLineage, err)
+					}
+					grader = harness.NewLLMGrader(cost.Track(judge, tracker, cost.OpJudge))
+				}
+				suiteResult, err := harness.RunSuite(ctx, *suite, result.Output, grader)
+				if err != nil {
+					// Keep the paid-for output; it can be graded manually.
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "warning: output stored unscored: %v\n", err)
+				} else {
+					artifact.HarnessResult = &suiteResult
+					artifact.RefreshCompositeScore()
+				}
+			}
 
 			artifactID, err := state.AddArtifact(sessionID, lineage.ID, artifact)
 			if err != nil {
 				return fmt.Errorf("run session=%q lineage=%q lineage_id=%q: persist artifact: %w", sessionID, selectedLineage, lineage.ID, err)
 			}
+			warnLedger(cmd.ErrOrStderr(), tracker)
 
 			if isJSONOutput(cmd) {
-				if err := writeJSON(cmd, map[string]any{"artifact_id": artifactID}); err != nil {
+				payload := map[string]any{"artifact_id": artifactID}
+				if artifact.HarnessResult != nil {
+					payload["harness_result"] = artifact.HarnessResult
+					payload["composite_score"] = artifact.CompositeScore
+				}
+				if err := writeJSON(cmd, payload); err != nil {
 					return fmt.Errorf("run session=%q lineage=%q: write json output: %w", sessionID, selectedLineage, err)
 				}
 				return nil
@@ -117,6 +179,13 @@ func newRunCmd() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("run session=%q lineage=%q: write output: %w", sessionID, selectedLineage, err)
 			}
+			if res := artifact.HarnessResult; res != nil {
+				_, err = fmt.Fprintf(cmd.OutOrStdout(), "harness=%d/%d passed score=%d/10 composite=%d/10\n",
+					res.Passed, res.Passed+res.Failed, res.NormalizedScore(), artifact.CompositeScore.Normalized)
+				if err != nil {
+					return fmt.Errorf("run session=%q lineage=%q: write output: %w", sessionID, selectedLineage, err)
+				}
+			}
 			return nil
 		},
 	}
@@ -125,7 +194,7 @@ func newRunCmd() *cobra.Command {
 	cmd.Flags().StringVar(&lineageName, "lineage", "", "Lineage name (main, A, B, C, D)")
 	cmd.Flags().StringVar(&mode, "mode", engine.ExecutionModeAPI, "Execution mode: api, cli, or sealed")
 	cmd.Flags().StringVar(&executor, "executor", "", "CLI executor for mode=cli: claude or codex")
-	cmd.Flags().StringVar(&harness, "harness", "", "Path to harness script for mode=sealed")
+	cmd.Flags().StringVar(&harnessScript, "harness", "", "Path to harness script for mode=sealed")
 	cmd.Flags().StringVar(&harnessModel, "harness-model", "", "Model to pass to sealed harness (e.g. qwen3.5:9b)")
 	cmd.Flags().StringVar(&condition, "condition", "chiron", "Condition name for sealed harness")
 	cmd.Flags().IntVar(&runNumber, "run-number", 0, "Run number for sealed harness (auto-generated if 0)")
@@ -133,7 +202,9 @@ func newRunCmd() *cobra.Command {
 	cmd.Flags().StringVar(&model, "model", "", "Model override for mode=api")
 	cmd.Flags().StringVar(&baseURL, "base-url", "", "Base URL override for mode=api")
 	cmd.Flags().StringVar(&apiKey, "api-key", "", "API key override for mode=api")
-	_ = cmd.MarkFlagRequired("input")
+	cmd.Flags().StringVar(&rowID, "row", "", "Dataset row to run; its input is used and its test suite grades the output")
+	cmd.Flags().StringVar(&judgeProvider, "judge-provider", "", "Provider for llm_rubric test cases (default: the run provider)")
+	cmd.Flags().StringVar(&judgeModel, "judge-model", "", "Model for llm_rubric test cases (default: the agent model)")
 
 	return cmd
 }
@@ -11,6 +11,7 @@ func newSessionCmd() *cobra.Command {
 	cmd.AddCommand(newSessionCreateCmd())
 	cmd.AddCommand(newSessionListCmd())
 	cmd.AddCommand(newSessionInspectCmd())
+	cmd.AddCommand(newSessionHarnessCmd())
 
 	return cmd
 }
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Perttulands/chiron/internal/challenge"
+	"github.com/Perttulands/chiron/internal/harness"
+	"github.com/Perttulands/chiron/internal/state"
+	"github.com/spf13/cobra"
+)
+
+func newSessionHarnessCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "harness",
+		Short: "Attach test suites that score a session's runs",
+	}
+
+	cmd.AddCommand(newSessionHarnessSetCmd())
+	cmd.AddCommand(newSessionHarnessClearCmd())
+	return cmd
+}
+
+// initialSessionHarness builds the harness for `quickstart init` and
+// `training init` from their --suite/--challenges flags; nil when neither is set.
+func initialSessionHarness(suitePath, challengesPath string) (*state.SessionHarness, error) {
+	if suitePath == "" && challengesPath == "" {
+		return nil, nil
+	}
+	h := &state.SessionHarness{}
+	if suitePath != "" {
+		suite, err := loadValidSuite(suitePath)
+		if err != nil {
+			return nil, err
+		}
+		h.Suite = suite
+	}
+	if challengesPath != "" {
+		rows, err := datasetRowsFromChallenges(challengesPath)
+		if err != nil {
+			return nil, err
+		}
+		h.Rows = rows
+	}
+	return h, nil
+}
+
+func loadValidSuite(path string) (*harness.TestSuite, error) {
+	suite, err := harness.LoadSuite(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := suite.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid test suite %q: %w", path, err)
+	}
+	return suite, nil
+}
+
+// datasetRowsFromChallenges turns each challenge with test cases into a
+// dataset row keyed by the challenge id.
+func datasetRowsFromChallenges(path string) ([]state.DatasetRow, error) {
+	set, err := challenge.LoadSet(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := set.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid challenge set %q: %w", path, err)
+	}
+
+	rows := make([]state.DatasetRow, 0, len(set.Challenges))
+	for _, c := range set.Challenges {
+		if len(c.TestSuite.TestCases) == 0 {
+			continue
+		}
+		rows = append(rows, state.DatasetRow{ID: c.ID, Input: c.Input, Suite: c.TestSuite})
+	}
+	return rows, nil
+}
+
+// upsertDatasetRow replaces the row with the same id or input, or appends it.
+func upsertDatasetRow(rows []state.DatasetRow, row state.DatasetRow) []state.DatasetRow {
+	for i := range rows {
+		if rows[i].ID == row.ID || rows[i].Input == row.Input {
+			rows[i] = row
+			return rows
+		}
+	}
+	return append(rows, row)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Perttulands/chiron/internal/state"
+	"github.com/spf13/cobra"
+)
+
+func newSessionHarnessClearCmd() *cobra.Command {
+	var rowID string
+
+	cmd := &cobra.Command{
+		Use:   "clear <session-id>",
+		Short: "Detach the session's test suites, or one dataset row with --row",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sessionID := strings.TrimSpace(args[0])
+
+			st, err := state.Load("")
+			if err != nil {
+				return fmt.Errorf("load state: %w", err)
+			}
+			session, ok := st.Sessions[sessionID]
+			if !ok {
+				return fmt.Errorf("session %q not found", sessionID)
+			}
+
+			if id := strings.TrimSpace(rowID); id != "" {
+				if session.Harness == nil {
+					return fmt.Errorf("dataset row %q not found", id)
+				}
+				rows := make([]state.DatasetRow, 0, len(session.Harness.Rows))
+				for _, row := range session.Harness.Rows {
+					if row.ID != id {
+						rows = append(rows, row)
+					}
+				}
+				if len(rows) == len(session.Harness.Rows) {
+					return fmt.Errorf("dataset row %q not found", id)
+				}
+				h := *session.Harness
+				h.Rows = rows
+				session.Harness = &h
+			} else {
+				session.Harness = nil
+			}
+
+			st.Sessions[sessionID] = session
+			if err := state.Save("", st); err != nil {
+				return fmt.Errorf("save state: %w", err)
+			}
+
+			if isJSONOutput(cmd) {
+				return writeJSON(cmd, map[string]any{
+					"session_id": sessionID,
+					"row_id":     rowID,
+					"cleared":    true,
+				})
+			}
+
+			if _, err = fmt.Fprintf(cmd.OutOrStdout(), "session_id=%s harness cleared\n", sessionID); err != nil {
+				return fmt.Errorf("write output: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&rowID, "row", "", "Remove only this dataset row")
+
+	return cmd
+}
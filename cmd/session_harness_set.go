@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Perttulands/chiron/internal/state"
+	"github.com/spf13/cobra"
+)
+
+func newSessionHarnessSetCmd() *cobra.Command {
+	var suitePath string
+	var challengesPath string
+	var input string
+	var rowID string
+
+	cmd := &cobra.Command{
+		Use:   "set <session-id>",
+		Short: "Attach a session-wide suite, a per-input suite, or a challenge set as dataset rows",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sessionID := strings.TrimSpace(args[0])
+			if (suitePath == "") == (challengesPath == "") {
+				return fmt.Errorf("exactly one of --suite or --challenges is required")
+			}
+			if input == "" && rowID != "" {
+				return fmt.Errorf("--row-id requires --input")
+			}
+			if challengesPath != "" && input != "" {
+				return fmt.Errorf("--input applies to --suite only")
+			}
+
+			st, err := state.Load("")
+			if err != nil {
+				return fmt.Errorf("load state: %w", err)
+			}
+			session, ok := st.Sessions[sessionID]
+			if !ok {
+				return fmt.Errorf("session %q not found", sessionID)
+			}
+
+			h := state.SessionHarness{}
+			if session.Harness != nil {
+				h = *session.Harness
+			}
+
+			var added []string
+			if challengesPath != "" {
+				rows, err := datasetRowsFromChallenges(challengesPath)
+				if err != nil {
+					return err
+				}
+				if len(rows) == 0 {
+					return fmt.Errorf("challenge set %q has no challenges with test cases", challengesPath)
+				}
+				for _, row := range rows {
+					h.Rows = upsertDatasetRow(h.Rows, row)
+					added = append(added, row.ID)
+				}
+			} else {
+				suite, err := loadValidSuite(suitePath)
+				if err != nil {
+					return err
+				}
+				if input == "" {
+					h.Suite = suite
+				} else {
+					id := strings.TrimSpace(rowID)
+					if id == "" {
+						id = newPrefixedID("row")
+					}
+					h.Rows = upsertDatasetRow(h.Rows, state.DatasetRow{ID: id, Input: input, Suite: *suite})
+					added = append(added, id)
+				}
+			}
+
+			session.Harness = &h
+			st.Sessions[sessionID] = session
+			if err := state.Save("", st); err != nil {
+				return fmt.Errorf("save state: %w", err)
+			}
+
+			if isJSONOutput(cmd) {
+				return writeJSON(cmd, map[string]any{
+					"session_id":    sessionID,
+					"session_suite": h.Suite != nil,
+					"rows":          len(h.Rows),
+					"row_ids":       added,
+				})
+			}
+
+			if len(added) == 0 {
+				_, err = fmt.Fprintf(cmd.OutOrStdout(), "session_id=%s session suite set (%d test cases)\n", sessionID, len(h.Suite.TestCases))
+			} else {
+				_, err = fmt.Fprintf(cmd.OutOrStdout(), "session_id=%s rows=%s (%d total)\n", sessionID, strings.Join(added, ","), len(h.Rows))
+			}
+			if err != nil {
+				return fmt.Errorf("write output: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&suitePath, "suite", "", "Test suite file (.yaml, .yml or .json)")
+	cmd.Flags().StringVar(&challengesPath, "challenges", "", "Challenge-set file; each challenge becomes a dataset row")
+	cmd.Flags().StringVar(&input, "input", "", "Pin --suite to this exact input as a dataset row")
+	cmd.Flags().StringVar(&rowID, "row-id", "", "Dataset row id for --input (generated if empty)")
+
+	return cmd
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/Perttulands/chiron/internal/cost"
+	"github.com/Perttulands/chiron/internal/engine"
+	"github.com/Perttulands/chiron/internal/harness"
+	"github.com/Perttulands/chiron/internal/provider"
+	"github.com/Perttulands/chiron/internal/state"
+	"github.com/Perttulands/chiron/internal/tournament"
+	"github.com/spf13/cobra"
+)
+
+func newTournamentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tournament",
+		Short: "Review and replay stored tournaments",
+	}
+
+	cmd.AddCommand(newTournamentListCmd())
+	cmd.AddCommand(newTournamentShowCmd())
+	cmd.AddCommand(newTournamentRerunCmd())
+
+	return cmd
+}
+
+// tournamentProviderFlags configures the provider that plays and judges bouts.
+type tournamentProviderFlags struct {
+	provider      string
+	model         string
+	baseURL       string
+	apiKey        string
+	judgeProvider string
+	judgeModel    string
+	seed          int64         // sampling seed for providers that accept one
+	tracker       *cost.Tracker // records bout and grading calls, if set
+}
+
+func (f *tournamentProviderFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.provider, "provider", "", "Provider for bouts (default: the provider that generated the first contestant)")
+	cmd.Flags().StringVar(&f.model, "model", "", "Model override for bouts (default: each agent's model)")
+	cmd.Flags().StringVar(&f.baseURL, "base-url", "", "Base URL override")
+	cmd.Flags().StringVar(&f.apiKey, "api-key", "", "API key override")
+	cmd.Flags().StringVar(&f.judgeProvider, "judge-provider", "", "Provider for llm_rubric test cases (default: the bout provider)")
+	cmd.Flags().StringVar(&f.judgeModel, "judge-model", "", "Model for llm_rubric test cases (default: the first contestant's model)")
+}
+
+// executingProvider names the provider bouts of contestants run on: the
+// --provider flag, or the provider that generated the first contestant.
+func (f *tournamentProviderFlags) executingProvider(contestants []tournament.Contestant) string {
+	name := strings.TrimSpace(f.provider)
+	if name == "" && len(contestants) > 0 {
+		name = strings.TrimSpace(contestants[0].Agent.GenerationMetadata.Provider)
+	}
+	return name
+}
+
+// executor returns a tournament executor that runs each agent definition
+// through the configured provider in api mode, one adapter per model.
+func (f *tournamentProviderFlags) executor(contestants []tournament.Contestant) tournament.Executor {
+	providerName := f.executingProvider(contestants)
+
+	var mu sync.Mutex
+	adapters := map[string]provider.Provider{}
+	return func(ctx context.Context, def state.AgentDefinition, input string) (string, int, error) {
+		model := modelOrDefault(f.model, def.Model)
+		mu.Lock()
+		adapter, ok := adapters[model]
+		if !ok {
+			var err error
+			adapter, err = provider.NewFactory(provider.Config{
+				Provider: providerName,
+				Model:    model,
+				BaseURL:  f.baseURL,
+				APIKey:   f.apiKey,
+				Seed:     f.seed,
+			})
+			if err != nil {
+				mu.Unlock()
+				return "", 0, fmt.Errorf("configure provider: %w: %w", tournament.ErrProvider, err)
+			}
+			adapter = cost.Track(adapter, f.tracker, cost.OpExecute)
+			adapters[model] = adapter
+		}
+		mu.Unlock()
+
+		result, err := engine.Execute(ctx, engine.ExecuteRequest{
+			Mode:       engine.ExecutionModeAPI,
+			Input:      input,
+			Definition: def,
+			Provider:   adapter,
+		})
+		if err != nil {
+			return "", 0, err
+		}
+		tournament.ReportUsage(ctx, tournament.Usage{
+			TokensIn:  result.Metadata.TokensInput,
+			TokensOut: result.Metadata.TokensOutput,
+			CostUSD:   result.Metadata.CostUSD,
+		})
+		return result.Output, result.Metadata.DurationMS, nil
+	}
+}
+
+// grader returns an LLM grader when any challenge needs one, otherwise nil.
+func (f *tournamentProviderFlags) grader(t *tournament.Tournament) (harness.Grader, error) {
+	needed := false
+	for _, ch := range t.Challenges {
+		needed = needed || ch.TestSuite.NeedsGrader()
+	}
+	if !needed || len(t.Contestants) == 0 {
+		return nil, nil
+	}
+
+	first := t.Contestants[0].Agent
+	judgeName := strings.TrimSpace(f.judgeProvider)
+	if judgeName == "" {
+		judgeName = strings.TrimSpace(f.provider)
+	}
+	if judgeName == "" {
+		judgeName = strings.TrimSpace(first.GenerationMetadata.Provider)
+	}
+	judge, err := provider.NewFactory(provider.Config{
+		Provider: judgeName,
+		Model:    modelOrDefault(f.judgeModel, first.Definition.Model),
+		BaseURL:  f.baseURL,
+		APIKey:   f.apiKey,
+		Seed:     f.seed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("configure judge provider: %w", err)
+	}
+	return harness.NewLLMGrader(cost.Track(judge, f.tracker, cost.OpJudge)), nil
+}
+
+// tournamentProgress renders a one-line progress counter to w.
+func tournamentProgress(w io.Writer) tournament.ProgressFunc {
+	return func(p tournament.Progress) {
+		status := fmt.Sprintf("%.1f", p.Bout.CompositeScore.FinalScore)
+		if p.Bout.ErrorKind != "" {
+			status = p.Bout.ErrorKind + " error"
+		}
+		_, _ = fmt.Fprintf(w, "\r\033[K[%d/%d] %s on %s: %s", p.Completed, p.Total, p.Bout.ContestantID, p.Bout.ChallengeID, status)
+		if p.Completed == p.Total {
+			_, _ = fmt.Fprintln(w)
+		}
+	}
+}
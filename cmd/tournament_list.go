@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Perttulands/chiron/internal/state"
+	"github.com/spf13/cobra"
+)
+
+type tournamentListEntry struct {
+	ID          string `json:"id"`
+	SessionID   string `json:"session_id"`
+	Name        string `json:"name"`
+	Format      string `json:"format"`
+	Status      string `json:"status"`
+	Generation  int    `json:"generation,omitempty"`
+	RerunOf     string `json:"rerun_of,omitempty"`
+	Lineages    int    `json:"lineages"`
+	Artifacts   int    `json:"artifacts"`
+	WinnerAgent string `json:"winner_agent_id,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+func newTournamentListCmd() *cobra.Command {
+	var sessionID string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List stored tournaments, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			st, err := state.Load("")
+			if err != nil {
+				return fmt.Errorf("load state: %w", err)
+			}
+			sessionID = strings.TrimSpace(sessionID)
+			if sessionID != "" {
+				if _, ok := st.Sessions[sessionID]; !ok {
+					return fmt.Errorf("session %q not found", sessionID)
+				}
+			}
+
+			entries := []tournamentListEntry{}
+			for id, session := range st.Sessions {
+				if sessionID != "" && id != sessionID {
+					continue
+				}
+				for _, record := range session.Tournaments {
+					format := record.Format
+					if format == "" {
+						format = "round_robin"
+					}
+					entries = append(entries, tournamentListEntry{
+						ID:          record.ID,
+						SessionID:   id,
+						Name:        record.Name,
+						Format:      format,
+						Status:      record.Status,
+						Generation:  record.Generation,
+						RerunOf:     record.RerunOf,
+						Lineages:    len(record.LineageIDs),
+						Artifacts:   len(record.ArtifactIDs),
+						WinnerAgent: record.WinnerAgentID,
+						CreatedAt:   record.CreatedAt,
+					})
+				}
+			}
+			sort.Slice(entries, func(i, j int) bool {
+				if entries[i].CreatedAt != entries[j].CreatedAt {
+					return entries[i].CreatedAt > entries[j].CreatedAt
+				}
+				return entries[i].ID < entries[j].ID
+			})
+
+			if isJSONOutput(cmd) {
+				return writeJSON(cmd, map[string]any{"tournaments": entries})
+			}
+
+			if len(entries) == 0 {
+				_, err := fmt.Fprintln(cmd.OutOrStdout(), "No tournaments recorded yet.")
+				return err
+			}
+
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			if _, err := fmt.Fprintln(tw, "ID\tSESSION\tNAME\tFORMAT\tSTATUS\tGEN\tARTIFACTS\tWINNER\tCREATED"); err != nil {
+				return fmt.Errorf("write tournament header: %w", err)
+			}
+			for _, e := range entries {
+				generation := "-"
+				if e.Generation > 0 {
+					generation = fmt.Sprintf("%d", e.Generation)
+				}
+				if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+					e.ID, e.SessionID, truncateSandboxField(e.Name, 28), e.Format, e.Status, generation,
+					e.Artifacts, truncateSandboxField(e.WinnerAgent, 16), e.CreatedAt); err != nil {
+					return fmt.Errorf("write tournament row %q: %w", e.ID, err)
+				}
+			}
+			return tw.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&sessionID, "session", "", "Only list tournaments from this session")
+
+	return cmd
+}
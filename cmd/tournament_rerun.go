@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/Perttulands/chiron/internal/cost"
+	"github.com/Perttulands/chiron/internal/rating"
+	"github.com/Perttulands/chiron/internal/state"
+	"github.com/Perttulands/chiron/internal/tournament"
+	"github.com/Perttulands/chiron/internal/training"
+	"github.com/spf13/cobra"
+)
+
+func newTournamentRerunCmd() *cobra.Command {
+	var flags tournamentProviderFlags
+	var concurrency int
+	var repetitions int
+	var budget budgetFlags
+
+	cmd := &cobra.Command{
+		Use:   "rerun <tournament-id>",
+		Short: "Replay a stored tournament with the same contestants, challenges and format",
+		Long: `Replay a stored tournament with the same contestants, challenges and format.
+
+With --budget-usd or --budget-tokens the rerun's spend is projected first
+and the rerun is refused when the projection passes the budget.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := budget.validate(); err != nil {
+				return err
+			}
+			st, err := state.Load("")
+			if err != nil {
+				return fmt.Errorf("load state: %w", err)
+			}
+			record, sessionID, err := state.FindTournamentRecord(st, args[0])
+			if err != nil {
+				return err
+			}
+			original, err := tournament.FromRecord(record)
+			if err != nil {
+				return err
+			}
+
+			schedule := original.Schedule
+			if concurrency > 0 {
+				schedule.Concurrency = concurrency
+			}
+			if repetitions > 0 {
+				schedule.Repetitions = repetitions
+			}
+			schedule.Progress = tournamentProgress(cmd.ErrOrStderr())
+
+			schedule.BoutContext = attributeBout
+			schedule.Provider = flags.executingProvider(original.Contestants)
+			flags.tracker = newCostTracker(budget)
+			if budget.set() {
+				executor, judge := flags.identities(original.Contestants)
+				projection := training.ProjectTournament(original.Contestants, original.Challenges, schedule.Repetitions, executor, judge)
+				if err := flags.tracker.CheckProjection(projection); err != nil {
+					return fmt.Errorf("rerun tournament %q: %w", record.ID, err)
+				}
+			}
+
+			grader, err := flags.grader(original)
+			if err != nil {
+				return fmt.Errorf("rerun tournament %q: %w", record.ID, err)
+			}
+			trn, err := tournament.New(tournament.Config{
+				Name:        original.Name + " (rerun)",
+				Weights:     original.Weights,
+				IDFunc:      newPrefixedID,
+				Grader:      grader,
+				Schedule:    schedule,
+				Ranking:     original.Ranking,
+				Format:      original.Format,
+				SwissRounds: original.SwissRounds,
+			}, original.Contestants, original.Challenges)
+			if err != nil {
+				return fmt.Errorf("rerun tournament %q: %w", record.ID, err)
+			}
+			ctx := cost.WithAttribution(cmd.Context(), cost.Attribution{SessionID: sessionID})
+			runErr := trn.Run(ctx, flags.executor(original.Contestants))
+			warnLedger(cmd.ErrOrStderr(), flags.tracker)
+
+			// Reload so changes made while the tournament ran are kept.
+			st, err = state.Load("")
+			if err != nil {
+				return fmt.Errorf("load state: %w", err)
+			}
+			rerun, err := tournament.Record(&st, trn, tournament.RecordOptions{SessionID: sessionID, RerunOf: record.ID})
+			if err != nil {
+				return fmt.Errorf("record tournament %q: %w", trn.ID, err)
+			}
+			if runErr == nil {
+				if st.Ratings == nil {
+					st.Ratings = map[string]state.Rating{}
+				}
+				rating.Table(st.Ratings).UpdateTournament(trn, rating.Config{})
+			}
+			if err := state.Save("", st); err != nil {
+				return fmt.Errorf("save state: %w", err)
+			}
+			if runErr != nil {
+				return fmt.Errorf("rerun tournament %q as %q: %w", record.ID, trn.ID, runErr)
+			}
+
+			if isJSONOutput(cmd) {
+				return writeJSON(cmd, map[string]any{
+					"tournament_id": trn.ID,
+					"rerun_of":      record.ID,
+					"artifact_ids":  rerun.ArtifactIDs,
+					"standings":     trn.Standings,
+				})
+			}
+
+			out := cmd.OutOrStdout()
+			if _, err := fmt.Fprintf(out, "tournament_id=%s rerun_of=%s artifacts=%d\n", trn.ID, record.ID, len(rerun.ArtifactIDs)); err != nil {
+				return fmt.Errorf("write output: %w", err)
+			}
+			tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			if _, err := fmt.Fprintln(tw, "RANK\tCONTESTANT\tAVG\tPLAYED\tWON\tFAILED"); err != nil {
+				return fmt.Errorf("write standings header: %w", err)
+			}
+			for _, s := range trn.Standings {
+				if _, err := fmt.Fprintf(tw, "%d\t%s\t%.2f\t%d\t%d\t%d\n", s.Rank, s.ContestantID, s.AvgScore, s.BoutsPlayed, s.BoutsWon, s.BoutsFailed); err != nil {
+					return fmt.Errorf("write standing row %q: %w", s.ContestantID, err)
+				}
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			if budget.set() {
+				writeSpend(out, flags.tracker.Summarize())
+			}
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+	budget.register(cmd)
+	cmd.Flags().IntVar(&concurrency, "concurrency", 0, "Bouts in flight (default: the original schedule)")
+	cmd.Flags().IntVar(&repetitions, "repetitions", 0, "Bouts per contestant and challenge (default: the original schedule)")
+
+	return cmd
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Perttulands/chiron/internal/state"
+	"github.com/Perttulands/chiron/internal/tournament"
+	"github.com/spf13/cobra"
+)
+
+func newTournamentShowCmd() *cobra.Command {
+	var showBouts bool
+
+	cmd := &cobra.Command{
+		Use:   "show <tournament-id>",
+		Short: "Show a stored tournament's standings and bouts",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			st, err := state.Load("")
+			if err != nil {
+				return fmt.Errorf("load state: %w", err)
+			}
+			record, sessionID, err := state.FindTournamentRecord(st, args[0])
+			if err != nil {
+				return err
+			}
+			trn, err := tournament.FromRecord(record)
+			if err != nil {
+				return err
+			}
+
+			if isJSONOutput(cmd) {
+				return writeJSON(cmd, map[string]any{
+					"session_id": sessionID,
+					"record":     record,
+					"tournament": trn,
+				})
+			}
+
+			agents := map[string]string{}
+			for _, c := range trn.Contestants {
+				agents[c.ID] = c.Agent.ID
+			}
+			lineages := map[string]string{}
+			if session, ok := st.Sessions[sessionID]; ok {
+				for _, lineage := range session.Lineages {
+					lineages[lineage.ID] = lineage.Name
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			format := trn.Format
+			if format == "" {
+				format = tournament.FormatRoundRobin
+			}
+			lines := []string{
+				fmt.Sprintf("ID:          %s", record.ID),
+				fmt.Sprintf("Name:        %s", record.Name),
+				fmt.Sprintf("Session:     %s", sessionID),
+				fmt.Sprintf("Status:      %s", record.Status),
+				fmt.Sprintf("Format:      %s", format),
+				fmt.Sprintf("Challenges:  %d", len(trn.Challenges)),
+				fmt.Sprintf("Created:     %s", record.CreatedAt),
+			}
+			if record.LoopID != "" {
+				lines = append(lines, fmt.Sprintf("Training:    loop %s, generation %d", record.LoopID, record.Generation))
+			}
+			if record.RerunOf != "" {
+				lines = append(lines, fmt.Sprintf("Rerun of:    %s", record.RerunOf))
+			}
+			if len(record.Selected) > 0 {
+				lines = append(lines, fmt.Sprintf("Selected:    %s", strings.Join(record.Selected, ", ")))
+			}
+			if _, err := fmt.Fprintln(out, strings.Join(lines, "\n")+"\n"); err != nil {
+				return fmt.Errorf("write output: %w", err)
+			}
+
+			tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			if _, err := fmt.Fprintln(tw, "RANK\tCONTESTANT\tAGENT\tLINEAGE\tPOINTS\tAVG\tCI\tPLAYED\tWON\tFAILED"); err != nil {
+				return fmt.Errorf("write standings header: %w", err)
+			}
+			for _, s := range trn.Standings {
+				rank := fmt.Sprintf("%d", s.Rank)
+				if s.Tied {
+					rank += "="
+				}
+				if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.1f\t%.2f\t%.2f-%.2f\t%d\t%d\t%d\n",
+					rank, s.ContestantID, agents[s.ContestantID], lineageLabel(lineages, s.LineageID),
+					s.Points, s.AvgScore, s.CILow, s.CIHigh, s.BoutsPlayed, s.BoutsWon, s.BoutsFailed); err != nil {
+					return fmt.Errorf("write standing row %q: %w", s.ContestantID, err)
+				}
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+
+			if !showBouts {
+				return nil
+			}
+			if _, err := fmt.Fprintln(out); err != nil {
+				return fmt.Errorf("write output: %w", err)
+			}
+			tw = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			if _, err := fmt.Fprintln(tw, "STAGE\tCHALLENGE\tCONTESTANT\tREP\tHARNESS\tSCORE\tARTIFACT\tERROR"); err != nil {
+				return fmt.Errorf("write bout header: %w", err)
+			}
+			for _, round := range trn.Rounds {
+				for _, b := range round.Bouts {
+					errText := "-"
+					if b.ErrorKind != "" {
+						errText = b.ErrorKind + ": " + truncateSandboxField(b.Error, 40)
+					}
+					if _, err := fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%d/%d\t%.2f\t%s\t%s\n",
+						round.Stage, round.ChallengeID, b.ContestantID, b.Repetition,
+						b.HarnessResult.Passed, b.HarnessResult.Passed+b.HarnessResult.Failed,
+						b.CompositeScore.FinalScore, truncateSandboxField(b.ArtifactID, 16), errText); err != nil {
+						return fmt.Errorf("write bout row: %w", err)
+					}
+				}
+			}
+			return tw.Flush()
+		},
+	}
+
+	cmd.Flags().BoolVar(&showBouts, "bouts", false, "Also list every bout with its artifact id")
+
+	return cmd
+}
+
+func lineageLabel(names map[string]string, lineageID string) string {
+	if name, ok := names[lineageID]; ok {
+		return name
+	}
+	return truncateSandboxField(lineageID, 16)
+}
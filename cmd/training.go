@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Perttulands/chiron/internal/cost"
 	"github.com/Perttulands/chiron/internal/engine"
 	"github.com/Perttulands/chiron/internal/provider"
 	"github.com/Perttulands/chiron/internal/state"
@@ -39,6 +40,8 @@ func newTrainingInitCmd() *cobra.Command {
 	var model string
 	var baseURL string
 	var apiKey string
+	var suitePath string
+	var challengesPath string
 
 	cmd := &cobra.Command{
 		Use:   "init",
@@ -49,6 +52,11 @@ func newTrainingInitCmd() *cobra.Command {
 				return fmt.Errorf("load state: %w", err)
 			}
 
+			sessionHarness, err := initialSessionHarness(suitePath, challengesPath)
+			if err != nil {
+				return fmt.Errorf("load session harness: %w", err)
+			}
+
 			now := time.Now().UTC().Format(time.RFC3339)
 			sessionID := newPrefixedID("ses")
 
@@ -61,6 +69,8 @@ func newTrainingInitCmd() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("initialize provider: %w", err)
 			}
+			tracker := newLedgerTracker()
+			adapter = cost.Track(adapter, tracker, cost.OpGenerate)
 
 			lineages := make(map[string]state.Lineage, len(defaultTrainingVariants))
 			lineageIDsByName := make(map[string]string, len(defaultTrainingVariants))
@@ -70,7 +80,7 @@ func newTrainingInitCmd() *cobra.Command {
 				agentID := newPrefixedID("agt")
 				variantNeed := fmt.Sprintf("%s\n\nVariation strategy: %s", need, variant.strategy)
 
-				agentDef, generationMeta, err := engine.GenerateAgentDefinitionWithMetadata(cmd.Context(), variantNeed, nil, adapter)
+				agentDef, generationMeta, err := engine.GenerateAgentDefinitionWithMetadata(cost.WithAttribution(cmd.Context(), cost.Attribution{SessionID: sessionID, LineageID: lineageID, AgentID: agentID}), variantNeed, nil, adapter)
 				if err != nil {
 					return fmt.Errorf("generate agent for lineage %s: %w", variant.name, err)
 				}
@@ -103,11 +113,13 @@ func newTrainingInitCmd() *cobra.Command {
 				CreatedAt: now,
 				Status:    "active",
 				Lineages:  lineages,
+				Harness:   sessionHarness,
 			}
 
 			if err := state.Save("", st); err != nil {
 				return fmt.Errorf("save state: %w", err)
 			}
+			warnLedger(cmd.ErrOrStderr(), tracker)
 
 			if isJSONOutput(cmd) {
 				payload := map[string]any{"session_id": sessionID}
@@ -134,6 +146,8 @@ func newTrainingInitCmd() *cobra.Command {
 	cmd.Flags().StringVar(&model, "model", "", "Override provider model")
 	cmd.Flags().StringVar(&baseURL, "base-url", "", "Override provider base URL")
 	cmd.Flags().StringVar(&apiKey, "api-key", "", "Override provider API key")
+	cmd.Flags().StringVar(&suitePath, "suite", "", "Test suite that scores every run in the session")
+	cmd.Flags().StringVar(&challengesPath, "challenges", "", "Challenge set whose challenges become the session's dataset rows")
 	_ = cmd.MarkFlagRequired("need")
 
 	return cmd
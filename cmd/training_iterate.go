@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Perttulands/chiron/internal/cost"
 	"github.com/Perttulands/chiron/internal/engine"
 	"github.com/Perttulands/chiron/internal/provider"
 	"github.com/Perttulands/chiron/internal/state"
@@ -41,6 +42,7 @@ func newTrainingIterateCmd() *cobra.Command {
 			}
 
 			regenerated := []string{}
+			tracker := newLedgerTracker()
 			locked := []string{}
 
 			for _, variant := range defaultTrainingVariants {
@@ -77,12 +79,17 @@ func newTrainingIterateCmd() *cobra.Command {
 				if err != nil {
 					return fmt.Errorf("initialize provider for lineage %s: %w", lineage.Name, err)
 				}
+				adapter = cost.Track(adapter, tracker, cost.OpGenerate)
 
-				newDefinition, generationMeta, err := engine.GenerateAgentDefinitionWithMetadata(cmd.Context(), evolutionPrompt, nil, adapter)
+				newDefinition, generationMeta, err := engine.GenerateAgentDefinitionWithMetadata(cost.WithAttribution(cmd.Context(), cost.Attribution{SessionID: sessionID, LineageID: lineage.ID}), evolutionPrompt, nil, adapter)
 				if err != nil {
 					return fmt.Errorf("generate agent for lineage %s: %w", lineage.Name, err)
 				}
 
+				generationMeta.ParentIDs = []string{prevAgent.ID}
+				generationMeta.Operator = engine.OperatorEvolve
+				generationMeta.MutationPrompt = evolutionPrompt
+
 				newAgent := state.Agent{
 					ID:                 newPrefixedID("agt"),
 					LineageID:          lineage.ID,
@@ -102,6 +109,7 @@ func newTrainingIterateCmd() *cobra.Command {
 			if err := state.Save("", st); err != nil {
 				return fmt.Errorf("save state: %w", err)
 			}
+			warnLedger(cmd.ErrOrStderr(), tracker)
 
 			regeneratedText := "none"
 			if len(regenerated) > 0 {
@@ -0,0 +1,174 @@
+package challenge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FileVersion is the challenge-set file schema version written by this binary.
+	FileVersion = 1
+	// FileKind marks a YAML/JSON document as a challenge set.
+	FileKind = "challenge_set"
+)
+
+// File is the on-disk envelope for a challenge set.
+type File struct {
+	Version      int    `json:"version" yaml:"version"`
+	Kind         string `json:"kind" yaml:"kind"`
+	ChallengeSet `yaml:",inline"`
+}
+
+// LoadSet reads a challenge set from a .yaml, .yml or .json file and
+// resolves its code_exec workspaces against the file's directory, so the set
+// runs the same from any working directory.
+func LoadSet(path string) (*ChallengeSet, error) {
+	set, err := ReadSet(path)
+	if err != nil {
+		return nil, err
+	}
+	baseDir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("resolve challenge set directory: %w", err)
+	}
+	set.ResolveWorkspaces(baseDir)
+	return set, nil
+}
+
+// ReadSet reads a challenge set as written, leaving workspaces relative. Use
+// it to edit a set file in place; LoadSet to run one.
+func ReadSet(path string) (*ChallengeSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read challenge set %q: %w", path, err)
+	}
+
+	var file File
+	if isJSONPath(path) {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&file)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode challenge set %q: %w", path, err)
+	}
+
+	if file.Kind != "" && file.Kind != FileKind {
+		return nil, fmt.Errorf("%q is a %q document, not a %s", path, file.Kind, FileKind)
+	}
+	if file.Version == 0 {
+		return nil, fmt.Errorf("challenge set %q has no version", path)
+	}
+	if file.Version > FileVersion {
+		return nil, fmt.Errorf("challenge set %q has version %d; this binary supports up to %d", path, file.Version, FileVersion)
+	}
+
+	set := file.ChallengeSet
+	return &set, nil
+}
+
+// SaveSet writes a challenge set to path, choosing JSON or YAML by extension.
+func SaveSet(path string, set ChallengeSet) error {
+	file := File{Version: FileVersion, Kind: FileKind, ChallengeSet: set}
+
+	var data []byte
+	var err error
+	if isJSONPath(path) {
+		data, err = json.MarshalIndent(file, "", "  ")
+		data = append(data, '\n')
+	} else {
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		err = enc.Encode(file)
+		data = buf.Bytes()
+	}
+	if err != nil {
+		return fmt.Errorf("encode challenge set: %w", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("create challenge set directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write challenge set %q: %w", path, err)
+	}
+	return nil
+}
+
+// FindSetFiles returns the challenge-set files under dir. Files that are not
+// challenge sets (wrong kind or undecodable) are skipped.
+func FindSetFiles(dir string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isSetExtension(path) {
+			return nil
+		}
+		if isSetFile(path) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan %q: %w", dir, err)
+	}
+	return paths, nil
+}
+
+// ResolveWorkspaces makes relative code_exec workspace paths absolute against
+// baseDir, normally the directory containing the challenge-set file.
+func (s *ChallengeSet) ResolveWorkspaces(baseDir string) {
+	for i := range s.Challenges {
+		s.Challenges[i].TestSuite.ResolveWorkspaces(baseDir)
+	}
+}
+
+func isSetFile(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var header struct {
+		Kind string `json:"kind" yaml:"kind"`
+	}
+	if isJSONPath(path) {
+		err = json.Unmarshal(data, &header)
+	} else {
+		err = yaml.Unmarshal(data, &header)
+	}
+	return err == nil && header.Kind == FileKind
+}
+
+func isJSONPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+func isSetExtension(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
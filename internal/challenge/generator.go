@@ -17,6 +17,7 @@ type GenerateRequest struct {
 	Difficulty string // easy, medium, hard
 	Domain     string // e.g., "web API", "CLI tool", "data processing"
 	Tags       []string
+	Warn       func(string) // receives a note for every generated test case that is dropped; optional
 }
 
 // generatedChallenge is the JSON structure the LLM returns.
@@ -29,10 +30,18 @@ type generatedChallenge struct {
 }
 
 type generatedTestCase struct {
-	Name     string  `json:"name"`
-	Type     string  `json:"type"`
-	Expected string  `json:"expected"`
-	Weight   float64 `json:"weight"`
+	Name       string            `json:"name"`
+	Type       string            `json:"type"`
+	Expected   string            `json:"expected"`
+	Weight     float64           `json:"weight"`
+	Values     []string          `json:"values"`
+	IgnoreCase bool              `json:"ignore_case"`
+	Path       string            `json:"path"`
+	Min        *float64          `json:"min"`
+	Max        *float64          `json:"max"`
+	Tolerance  float64           `json:"tolerance"`
+	Threshold  float64           `json:"threshold"`
+	Exec       *harness.ExecSpec `json:"exec"`
 }
 
 // Generate creates a synthetic challenge using an LLM provider.
@@ -81,19 +90,38 @@ func Generate(ctx context.Context, req GenerateRequest, p provider.Provider, idF
 		if weight <= 0 {
 			weight = 1.0
 		}
-		tcType := tc.Type
+		tcType := strings.ToLower(strings.TrimSpace(tc.Type))
 		if tcType == "" {
-			tcType = "contains"
+			tcType = harness.TypeContains
 		}
-		testCases = append(testCases, harness.TestCase{
-			ID:       tcID,
-			Name:     tc.Name,
-			Type:     tcType,
-			Expected: tc.Expected,
-			Weight:   weight,
-		})
+		testCase := harness.TestCase{
+			ID:         tcID,
+			Name:       tc.Name,
+			Type:       tcType,
+			Expected:   tc.Expected,
+			Weight:     weight,
+			Values:     tc.Values,
+			IgnoreCase: tc.IgnoreCase,
+			Path:       tc.Path,
+			Min:        tc.Min,
+			Max:        tc.Max,
+			Tolerance:  tc.Tolerance,
+			Threshold:  tc.Threshold,
+			Exec:       generatedExec(tc.Exec),
+		}
+		// One malformed case should not cost the whole challenge.
+		if err := testCase.Validate(); err != nil {
+			if req.Warn != nil {
+				req.Warn(fmt.Sprintf("challenge %q: dropped test case %q: %v", parsed.Name, tc.Name, err))
+			}
+			continue
+		}
+		testCases = append(testCases, testCase)
 		_ = i
 	}
+	if len(testCases) == 0 && len(parsed.TestCases) > 0 {
+		return Challenge{}, fmt.Errorf("challenge %q: none of %d generated test cases is usable", parsed.Name, len(parsed.TestCases))
+	}
 
 	return Challenge{
 		ID:          challengeID,
@@ -131,17 +159,37 @@ Output a JSON object:
   "test_cases": [
     {
       "name": "test case name",
-      "type": "contains|not_contains|regex|equals",
+      "type": "one of the test case types below",
       "expected": "the expected pattern or value",
       "weight": 1.0
     }
   ]
 }
 
+Test case types (optional fields in parentheses):
+%s
+
 Include 3-5 test cases that verify the agent's output quality.
-For %s difficulty, calibrate complexity accordingly.`, challengeType, domain, difficulty, challengeType, difficulty)
+Prefer assertions that give partial credit over brittle exact matches.
+For %s difficulty, calibrate complexity accordingly.`, challengeType, domain, difficulty, challengeType, testCaseTypeReference, difficulty)
 }
 
+// testCaseTypeReference documents the harness assertion types for the generator.
+const testCaseTypeReference = `- contains / not_contains: "expected" substring is present / absent (ignore_case)
+- icontains: case-insensitive contains
+- contains_all / contains_any: every / at least one string in "values" is present (ignore_case)
+- starts_with: trimmed output begins with "expected" (ignore_case)
+- regex: output matches the "expected" regular expression
+- equals: trimmed output equals "expected" (ignore_case)
+- word_count / char_count: output length within "min" and/or "max"
+- json_path: JSON output has "path" (e.g. "$.items[0].id"); if "expected" is set the value must match (tolerance for numbers)
+- numeric: first number in the output (or the value at "path") equals "expected" within "tolerance"
+- valid_json / valid_yaml: output parses as JSON / YAML
+- levenshtein / rouge_l: output similarity to "expected" is at least "threshold" (0.0-1.0, default 0.8)
+- llm_rubric: a judge model decides whether the output meets the natural-language criterion in "expected"
+- code_exec: fenced code blocks in the output are written to a sandboxed workspace and "exec" runs tests against them,
+  e.g. "exec": {"command": "go test -v ./...", "files": {"main_test.go": "..."}, "default_file": "main.go"}; scored by tests passed`
+
 // GenerateBatch creates multiple challenges at once.
 func GenerateBatch(ctx context.Context, count int, req GenerateRequest, p provider.Provider, idFunc func(string) string) ([]Challenge, error) {
 	if count <= 0 {
@@ -158,3 +206,17 @@ func GenerateBatch(ctx context.Context, count int, req GenerateRequest, p provid
 	}
 	return challenges, nil
 }
+
+// generatedExec keeps what a generated code_exec test case may set. The
+// sandbox engine, a host workspace to copy and network access are left to
+// hand-written challenge files.
+func generatedExec(spec *harness.ExecSpec) *harness.ExecSpec {
+	if spec == nil {
+		return nil
+	}
+	safe := *spec
+	safe.Sandbox = ""
+	safe.Workspace = ""
+	safe.Network = false
+	return &safe
+}
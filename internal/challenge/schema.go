@@ -1,6 +1,7 @@
 package challenge
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -25,27 +26,30 @@ const (
 	DifficultyHard   = "hard"
 )
 
+// ValidDifficulties lists all recognized difficulty levels, easiest first.
+var ValidDifficulties = []string{DifficultyEasy, DifficultyMedium, DifficultyHard}
+
 // Challenge defines a synthetic evaluation task for agent training.
 type Challenge struct {
-	ID          string            `json:"id"`
-	Name        string            `json:"name"`
-	Type        string            `json:"type"`       // feature, bugfix, refactor, review
-	Difficulty  string            `json:"difficulty"`  // easy, medium, hard
-	Description string            `json:"description"` // what the agent must do
-	Input       string            `json:"input"`       // the input/prompt given to the agent
-	Context     string            `json:"context,omitempty"` // optional code or context
-	TestSuite   harness.TestSuite `json:"test_suite"`  // how to verify the output
-	Tags        []string          `json:"tags,omitempty"`
-	CreatedAt   string            `json:"created_at"`
-	MaxDurationMS int             `json:"max_duration_ms,omitempty"` // expected time budget
+	ID            string            `json:"id" yaml:"id"`
+	Name          string            `json:"name" yaml:"name"`
+	Type          string            `json:"type" yaml:"type"`                           // feature, bugfix, refactor, review
+	Difficulty    string            `json:"difficulty" yaml:"difficulty"`               // easy, medium, hard
+	Description   string            `json:"description" yaml:"description"`             // what the agent must do
+	Input         string            `json:"input" yaml:"input"`                         // the input/prompt given to the agent
+	Context       string            `json:"context,omitempty" yaml:"context,omitempty"` // optional code or context
+	TestSuite     harness.TestSuite `json:"test_suite" yaml:"test_suite"`               // how to verify the output
+	Tags          []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
+	CreatedAt     string            `json:"created_at" yaml:"created_at"`
+	MaxDurationMS int               `json:"max_duration_ms,omitempty" yaml:"max_duration_ms,omitempty"` // expected time budget
 }
 
 // ChallengeSet groups challenges for a tournament.
 type ChallengeSet struct {
-	ID         string      `json:"id"`
-	Name       string      `json:"name"`
-	Challenges []Challenge `json:"challenges"`
-	CreatedAt  string      `json:"created_at"`
+	ID         string      `json:"id" yaml:"id"`
+	Name       string      `json:"name" yaml:"name"`
+	Challenges []Challenge `json:"challenges" yaml:"challenges"`
+	CreatedAt  string      `json:"created_at" yaml:"created_at"`
 }
 
 // Validate checks that a challenge has required fields and valid types.
@@ -65,9 +69,71 @@ func (c Challenge) Validate() error {
 	if strings.TrimSpace(c.Input) == "" {
 		return fmt.Errorf("challenge input is required")
 	}
+	if c.Difficulty != "" && !isValidDifficulty(c.Difficulty) {
+		return fmt.Errorf("invalid difficulty %q; must be one of: %s", c.Difficulty, strings.Join(ValidDifficulties, ", "))
+	}
 	return nil
 }
 
+// Validate checks every challenge and its test suite, reporting all problems.
+func (s ChallengeSet) Validate() error {
+	var errs []error
+	if strings.TrimSpace(s.Name) == "" {
+		errs = append(errs, fmt.Errorf("challenge set name is required"))
+	}
+	if len(s.Challenges) == 0 {
+		errs = append(errs, fmt.Errorf("challenge set has no challenges"))
+	}
+
+	seen := map[string]bool{}
+	for i, c := range s.Challenges {
+		label := fmt.Sprintf("challenges[%d]", i)
+		if c.ID != "" {
+			label = fmt.Sprintf("challenge %q", c.ID)
+			if seen[c.ID] {
+				errs = append(errs, fmt.Errorf("%s: duplicate id", label))
+			}
+			seen[c.ID] = true
+		}
+		if err := c.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", label, err))
+		}
+		if err := c.TestSuite.Validate(); err != nil {
+			for _, suiteErr := range unjoin(err) {
+				errs = append(errs, fmt.Errorf("%s: %w", label, suiteErr))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Find returns the challenge with the given id.
+func (s ChallengeSet) Find(id string) (Challenge, bool) {
+	for _, c := range s.Challenges {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return Challenge{}, false
+}
+
+// unjoin splits an errors.Join result so each problem can carry its own prefix.
+func unjoin(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+func isValidDifficulty(d string) bool {
+	for _, valid := range ValidDifficulties {
+		if d == valid {
+			return true
+		}
+	}
+	return false
+}
+
 func isValidType(t string) bool {
 	for _, valid := range ValidTypes {
 		if t == valid {
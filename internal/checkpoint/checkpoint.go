@@ -1,10 +1,16 @@
 package checkpoint
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/Perttulands/chiron/internal/training"
@@ -13,22 +19,66 @@ import (
 const (
 	checkpointDir  = ".chiron"
 	checkpointFile = "checkpoint.json"
+	historyDir     = "checkpoints" // per-loop generation history, under the checkpoint's directory
 )
 
+// SchemaVersion is the checkpoint format written by this binary. Version 0
+// is the unversioned format without a checksum, which still loads.
+const SchemaVersion = 1
+
+// DefaultKeep is how many generation checkpoints Save keeps per loop.
+const DefaultKeep = 5
+
+// ErrChecksum is returned, wrapped, when a checkpoint's loop does not match
+// its checksum.
+var ErrChecksum = errors.New("checkpoint checksum mismatch")
+
 // Checkpoint captures the full state of a training loop for resume.
 type Checkpoint struct {
-	Loop      training.Loop `json:"loop"`
-	SavedAt   string        `json:"saved_at"`
-	Reason    string        `json:"reason"` // "generation_complete", "paused", "error"
+	Version    int           `json:"version"`
+	Checksum   string        `json:"checksum,omitempty"` // "sha256:<hex>" of the compact JSON of Loop
+	Loop       training.Loop `json:"loop"`
+	SavedAt    string        `json:"saved_at"`
+	Reason     string        `json:"reason"`          // "generation_complete", "round_complete", "budget_exhausted", "error"
+	Generation int           `json:"generation"`      // generations completed
+	Round      int           `json:"round,omitempty"` // rounds finished in the generation in progress
+
+	Path      string `json:"-"` // file the checkpoint was loaded from
+	Recovered string `json:"-"` // why the latest checkpoint was skipped, when an older one was loaded
+}
+
+// file is the on-disk form; Loop stays raw so its checksum covers the
+// exact bytes that were written.
+type file struct {
+	Version    int             `json:"version"`
+	Checksum   string          `json:"checksum,omitempty"`
+	SavedAt    string          `json:"saved_at"`
+	Reason     string          `json:"reason"`
+	Generation int             `json:"generation"`
+	Round      int             `json:"round,omitempty"`
+	Loop       json.RawMessage `json:"loop"`
+}
+
+// Options controls how checkpoints are written.
+type Options struct {
+	Keep int // generation checkpoints kept per loop; default DefaultKeep, negative keeps all
 }
 
 // Save persists a training loop checkpoint to disk.
 func Save(loop *training.Loop, reason string) error {
-	return SaveTo("", loop, reason)
+	return Write("", loop, reason, Options{})
 }
 
 // SaveTo persists a checkpoint to a specific path.
 func SaveTo(path string, loop *training.Loop, reason string) error {
+	return Write(path, loop, reason, Options{})
+}
+
+// Write atomically replaces the checkpoint at path (DefaultPath when empty)
+// with loop. Between generations the checkpoint is also copied into the
+// loop's history, which keeps the last opts.Keep generations; checkpoints
+// taken mid-generation or awaiting review only replace the latest.
+func Write(path string, loop *training.Loop, reason string, opts Options) error {
 	if loop == nil {
 		return fmt.Errorf("loop is nil")
 	}
@@ -37,46 +87,168 @@ func SaveTo(path string, loop *training.Loop, reason string) error {
 		path = DefaultPath(loop.ID)
 	}
 
-	cp := Checkpoint{
-		Loop:    *loop,
-		SavedAt: time.Now().UTC().Format(time.RFC3339),
-		Reason:  reason,
+	data, err := encode(loop, reason)
+	if err != nil {
+		return err
+	}
+	if err := writeAtomic(path, data); err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
 	}
 
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return fmt.Errorf("create checkpoint directory: %w", err)
+	if loop.Partial != nil || loop.Review != nil || len(loop.Generations) == 0 {
+		return nil
+	}
+	dir := filepath.Join(filepath.Dir(path), historyDir, loop.ID)
+	generation := filepath.Join(dir, fmt.Sprintf("gen_%04d.json", len(loop.Generations)))
+	if err := writeAtomic(generation, data); err != nil {
+		return fmt.Errorf("write checkpoint history: %w", err)
+	}
+	return rotate(dir, opts.Keep)
+}
+
+func encode(loop *training.Loop, reason string) ([]byte, error) {
+	loopJSON, err := json.Marshal(loop)
+	if err != nil {
+		return nil, fmt.Errorf("encode checkpoint: %w", err)
+	}
+	f := file{
+		Version:    SchemaVersion,
+		Checksum:   checksum(loopJSON),
+		SavedAt:    time.Now().UTC().Format(time.RFC3339),
+		Reason:     reason,
+		Generation: len(loop.Generations),
+		Loop:       loopJSON,
+	}
+	if loop.Partial != nil {
+		f.Round = len(loop.Partial.Rounds)
 	}
 
-	data, err := json.MarshalIndent(cp, "", "  ")
+	data, err := json.MarshalIndent(f, "", "  ")
 	if err != nil {
-		return fmt.Errorf("encode checkpoint: %w", err)
+		return nil, fmt.Errorf("encode checkpoint: %w", err)
 	}
-	data = append(data, '\n')
+	return append(data, '\n'), nil
+}
 
-	if err := os.WriteFile(path, data, 0o644); err != nil {
-		return fmt.Errorf("write checkpoint: %w", err)
+func checksum(compact []byte) string {
+	sum := sha256.Sum256(compact)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// writeAtomic writes data to a temporary file beside path and renames it
+// over path, so readers see the old checkpoint or the new one, never a
+// partial write.
+func writeAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create checkpoint directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
 	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
 
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// rotate removes all but the newest keep generation checkpoints in dir.
+func rotate(dir string, keep int) error {
+	if keep == 0 {
+		keep = DefaultKeep
+	}
+	if keep < 0 {
+		return nil
+	}
+	paths, err := generationFiles(dir)
+	if err != nil {
+		return err
+	}
+	for len(paths) > keep {
+		if err := os.Remove(paths[len(paths)-1]); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("rotate checkpoints: %w", err)
+		}
+		paths = paths[:len(paths)-1]
+	}
 	return nil
 }
 
-// Load reads a checkpoint from the default location.
+// Load reads the latest checkpoint of a loop. When it is missing or fails
+// verification, the newest generation checkpoint that verifies is loaded
+// instead, with Recovered set to why the latest was skipped.
 func Load(loopID string) (*Checkpoint, error) {
-	return LoadFrom(DefaultPath(loopID))
+	cp, err := LoadFrom(DefaultPath(loopID))
+	if err == nil {
+		return cp, nil
+	}
+	history, histErr := History(loopID)
+	if histErr != nil {
+		return nil, errors.Join(err, histErr)
+	}
+	for _, path := range history {
+		older, olderErr := LoadFrom(path)
+		if olderErr == nil {
+			older.Recovered = err.Error()
+			return older, nil
+		}
+	}
+	return nil, err
 }
 
-// LoadFrom reads a checkpoint from a specific path.
+// LoadFrom reads a checkpoint from a specific path and verifies its
+// checksum and schema version.
 func LoadFrom(path string) (*Checkpoint, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read checkpoint %q: %w", path, err)
 	}
 
-	var cp Checkpoint
-	if err := json.Unmarshal(data, &cp); err != nil {
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
 		return nil, fmt.Errorf("decode checkpoint %q: %w", path, err)
 	}
+	if f.Version > SchemaVersion {
+		return nil, fmt.Errorf("checkpoint %q has version %d; this binary supports up to %d", path, f.Version, SchemaVersion)
+	}
+	if len(f.Loop) == 0 {
+		return nil, fmt.Errorf("checkpoint %q has no loop", path)
+	}
+	if f.Version > 0 {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, f.Loop); err != nil {
+			return nil, fmt.Errorf("decode checkpoint %q: %w", path, err)
+		}
+		if got := checksum(compact.Bytes()); got != f.Checksum {
+			return nil, fmt.Errorf("checkpoint %q: %w (recorded %s, computed %s)", path, ErrChecksum, orNone(f.Checksum), got)
+		}
+	}
 
+	cp := Checkpoint{
+		Version:    f.Version,
+		Checksum:   f.Checksum,
+		SavedAt:    f.SavedAt,
+		Reason:     f.Reason,
+		Generation: f.Generation,
+		Round:      f.Round,
+		Path:       path,
+	}
+	if err := json.Unmarshal(f.Loop, &cp.Loop); err != nil {
+		return nil, fmt.Errorf("decode checkpoint %q: %w", path, err)
+	}
 	return &cp, nil
 }
 
@@ -91,9 +263,15 @@ func ExistsAt(path string) bool {
 	return err == nil
 }
 
-// Remove deletes a checkpoint file.
+// Remove deletes a loop's checkpoint and its generation history.
 func Remove(loopID string) error {
-	return RemoveAt(DefaultPath(loopID))
+	if err := RemoveAt(DefaultPath(loopID)); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(HistoryDir(loopID)); err != nil {
+		return fmt.Errorf("remove checkpoint history of %q: %w", loopID, err)
+	}
+	return nil
 }
 
 // RemoveAt deletes a checkpoint at a specific path.
@@ -109,12 +287,43 @@ func DefaultPath(loopID string) string {
 	return filepath.Join(checkpointDir, fmt.Sprintf("checkpoint_%s.json", loopID))
 }
 
+// HistoryDir returns where a loop's generation checkpoints are kept.
+func HistoryDir(loopID string) string {
+	return filepath.Join(checkpointDir, historyDir, loopID)
+}
+
+// History returns a loop's generation checkpoints, newest first.
+func History(loopID string) ([]string, error) {
+	return generationFiles(HistoryDir(loopID))
+}
+
+// generationFiles returns the gen_NNNN.json files in dir, newest first.
+func generationFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read checkpoint history %q: %w", dir, err)
+	}
+	paths := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, "gen_") && filepath.Ext(name) == ".json" {
+			paths = append(paths, filepath.Join(dir, name))
+		}
+	}
+	// Zero-padded generation numbers sort lexically.
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	return paths, nil
+}
+
 // List returns all checkpoint files found in the default directory.
 func List() ([]string, error) {
 	return ListIn(checkpointDir)
 }
 
-// ListIn returns all checkpoint files in a directory.
+// ListIn returns the latest checkpoint file of every loop in a directory.
 // Returns empty slice (not error) when directory does not exist.
 func ListIn(dir string) ([]string, error) {
 	entries, err := os.ReadDir(dir)
@@ -125,14 +334,22 @@ func ListIn(dir string) ([]string, error) {
 		return nil, fmt.Errorf("read checkpoint dir %q: %w", dir, err)
 	}
 
-	var paths []string
+	paths := []string{}
 	for _, entry := range entries {
 		if entry.IsDir() {
 			continue
 		}
-		if filepath.Ext(entry.Name()) == ".json" && len(entry.Name()) > len("checkpoint_") {
-			paths = append(paths, filepath.Join(dir, entry.Name()))
+		name := entry.Name()
+		if strings.HasPrefix(name, "checkpoint_") && filepath.Ext(name) == ".json" && len(name) > len("checkpoint_.json") {
+			paths = append(paths, filepath.Join(dir, name))
 		}
 	}
 	return paths, nil
 }
+
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
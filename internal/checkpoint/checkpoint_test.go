@@ -0,0 +1,165 @@
+package checkpoint
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Perttulands/chiron/internal/training"
+)
+
+// testLoop returns a loop that has completed generations generations.
+func testLoop(generations int) *training.Loop {
+	loop := &training.Loop{ID: "loop_test", Status: "running", BestScore: 7.5}
+	for n := 1; n <= generations; n++ {
+		loop.Generations = append(loop.Generations, training.Generation{Number: n, BestScore: float64(n)})
+	}
+	return loop
+}
+
+func TestWriteLoadFromRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	if err := Write(path, testLoop(2), "generation_complete", Options{}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	cp, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom() error = %v", err)
+	}
+	if cp.Version != SchemaVersion || !strings.HasPrefix(cp.Checksum, "sha256:") || cp.Path != path {
+		t.Errorf("LoadFrom() version %d checksum %q path %q", cp.Version, cp.Checksum, cp.Path)
+	}
+	if cp.Loop.ID != "loop_test" || cp.Loop.BestScore != 7.5 || len(cp.Loop.Generations) != 2 || cp.Generation != 2 || cp.Reason != "generation_complete" {
+		t.Errorf("LoadFrom() = %+v, want the written loop", cp)
+	}
+}
+
+func TestLoadFromRejects(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "checkpoint.json")
+	if err := Write(path, testLoop(1), "generation_complete", Options{}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		data     []byte
+		checksum bool // error wraps ErrChecksum
+	}{
+		{name: "edited loop", data: bytes.Replace(written, []byte(`"best_score": 7.5`), []byte(`"best_score": 9.5`), 1), checksum: true},
+		{name: "future version", data: bytes.Replace(written, []byte(`"version": 1`), []byte(`"version": 99`), 1)},
+		{name: "truncated", data: written[:len(written)/2]},
+		{name: "no loop", data: []byte(`{"version": 1}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if bytes.Equal(tt.data, written) {
+				t.Fatal("test data did not change the checkpoint")
+			}
+			bad := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "_")+".json")
+			if err := os.WriteFile(bad, tt.data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadFrom(bad)
+			if err == nil {
+				t.Fatal("LoadFrom() accepted a bad checkpoint")
+			}
+			if got := errors.Is(err, ErrChecksum); got != tt.checksum {
+				t.Errorf("LoadFrom() error = %v, checksum mismatch %v, want %v", err, got, tt.checksum)
+			}
+		})
+	}
+}
+
+func TestLoadFromUnversioned(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	data := `{"saved_at": "2025-01-01T00:00:00Z", "reason": "generation_complete", "generation": 1, "loop": {"id": "loop_old", "status": "running"}}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cp, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom() error = %v", err)
+	}
+	if cp.Version != 0 || cp.Loop.ID != "loop_old" {
+		t.Errorf("LoadFrom() = version %d loop %q, want version 0 loop_old", cp.Version, cp.Loop.ID)
+	}
+}
+
+func TestLoadRecovery(t *testing.T) {
+	t.Chdir(t.TempDir())
+	loop := testLoop(0)
+	for n := 1; n <= 3; n++ {
+		loop.Generations = append(loop.Generations, training.Generation{Number: n})
+		if err := Write("", loop, "generation_complete", Options{}); err != nil {
+			t.Fatalf("Write() generation %d error = %v", n, err)
+		}
+	}
+
+	cp, err := Load(loop.ID)
+	if err != nil || cp.Generation != 3 || cp.Recovered != "" {
+		t.Fatalf("Load() = %+v, %v; want generation 3, not recovered", cp, err)
+	}
+
+	// A corrupt latest checkpoint falls back to the newest generation
+	// checkpoint that verifies.
+	if err := os.WriteFile(DefaultPath(loop.ID), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	history, err := History(loop.ID)
+	if err != nil || len(history) != 3 {
+		t.Fatalf("History() = %v, %v; want 3 generation checkpoints", history, err)
+	}
+	if err := os.WriteFile(history[0], []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cp, err = Load(loop.ID)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cp.Generation != 2 || cp.Path != history[1] || cp.Recovered == "" {
+		t.Errorf("Load() = generation %d from %q recovered %q; want generation 2 from %q", cp.Generation, cp.Path, cp.Recovered, history[1])
+	}
+
+	if err := os.RemoveAll(HistoryDir(loop.ID)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(loop.ID); err == nil {
+		t.Error("Load() without a checkpoint that verifies succeeded")
+	}
+}
+
+func TestWriteRotatesHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	loop := testLoop(0)
+	for n := 1; n <= 4; n++ {
+		loop.Generations = append(loop.Generations, training.Generation{Number: n})
+		if err := Write(path, loop, "generation_complete", Options{Keep: 2}); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	loop.Partial = &training.PartialGeneration{Number: 5}
+	if err := Write(path, loop, "round_complete", Options{Keep: 2}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	paths, err := generationFiles(filepath.Join(filepath.Dir(path), historyDir, loop.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range paths {
+		names = append(names, filepath.Base(p))
+	}
+	if want := "gen_0004.json,gen_0003.json"; strings.Join(names, ",") != want {
+		t.Errorf("history = %v, want %s", names, want)
+	}
+}
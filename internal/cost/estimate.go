@@ -0,0 +1,59 @@
+package cost
+
+import (
+	"unicode/utf8"
+
+	"github.com/Perttulands/chiron/internal/provider"
+)
+
+// DefaultOutputTokens is the assumed response length of a call whose
+// definition sets no MaxTokens.
+const DefaultOutputTokens = 512
+
+// Projection is the estimated spend of planned calls.
+type Projection struct {
+	Calls     int     `json:"calls"`
+	TokensIn  int     `json:"tokens_in"`
+	TokensOut int     `json:"tokens_out"`
+	CostUSD   float64 `json:"cost_usd"`
+	Unpriced  int     `json:"unpriced_calls,omitempty"` // calls to models without a known price, counted as free
+}
+
+// Tokens returns the projected input plus output tokens.
+func (p Projection) Tokens() int {
+	return p.TokensIn + p.TokensOut
+}
+
+// AddCalls adds n calls of tokensIn and tokensOut each to the projection,
+// priced as model under providerName.
+func (p *Projection) AddCalls(n int, providerName, model string, tokensIn, tokensOut int) {
+	if n <= 0 {
+		return
+	}
+	p.Calls += n
+	p.TokensIn += n * tokensIn
+	p.TokensOut += n * tokensOut
+	price, ok := provider.PriceFor(providerName, model)
+	if !ok {
+		p.Unpriced += n
+		return
+	}
+	p.CostUSD += float64(n) * price.Cost(tokensIn, tokensOut)
+}
+
+// Add returns the sum of two projections.
+func (p Projection) Add(q Projection) Projection {
+	return Projection{
+		Calls:     p.Calls + q.Calls,
+		TokensIn:  p.TokensIn + q.TokensIn,
+		TokensOut: p.TokensOut + q.TokensOut,
+		CostUSD:   p.CostUSD + q.CostUSD,
+		Unpriced:  p.Unpriced + q.Unpriced,
+	}
+}
+
+// EstimateTokens approximates the token count of text at four characters
+// per token.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
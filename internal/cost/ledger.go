@@ -0,0 +1,136 @@
+package cost
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DefaultLedgerPath is where the cost ledger lives, next to state.json.
+const DefaultLedgerPath = ".chiron/cost_ledger.jsonl"
+
+// Ledger is an append-only JSON-lines file of cost events. Lines are never
+// rewritten, so concurrent commands can share it.
+type Ledger struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewLedger returns the ledger at path, or DefaultLedgerPath when empty.
+// The file is created on the first append.
+func NewLedger(path string) *Ledger {
+	if path == "" {
+		path = DefaultLedgerPath
+	}
+	return &Ledger{path: path}
+}
+
+// Path returns the ledger file.
+func (l *Ledger) Path() string {
+	return l.path
+}
+
+// Append writes events to the end of the ledger, one line each.
+func (l *Ledger) Append(events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, e := range events {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("encode ledger event: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("create ledger directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open ledger: %w", err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("append ledger: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close ledger: %w", err)
+	}
+	return nil
+}
+
+// ReadLedger returns every event in the ledger at path (DefaultLedgerPath
+// when empty), oldest first. A missing ledger is empty. A final line cut
+// off by an interrupted write is ignored.
+func ReadLedger(path string) ([]Event, error) {
+	if path == "" {
+		path = DefaultLedgerPath
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Event{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read ledger: %w", err)
+	}
+
+	events := []Event{}
+	lines := bytes.Split(data, []byte("\n"))
+	for i, line := range lines {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(line, &e); err != nil {
+			if i == len(lines)-1 {
+				break // unterminated last line
+			}
+			return nil, fmt.Errorf("ledger %s line %d: %w", path, i+1, err)
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// Attribution names what a call was made for. Tracked providers copy it
+// from the context onto every event they record.
+type Attribution struct {
+	SessionID string
+	LineageID string
+	AgentID   string
+}
+
+type attributionKey struct{}
+
+// WithAttribution returns ctx carrying a, with empty fields of a inherited
+// from any attribution ctx already carries.
+func WithAttribution(ctx context.Context, a Attribution) context.Context {
+	parent := AttributionFrom(ctx)
+	if a.SessionID == "" {
+		a.SessionID = parent.SessionID
+	}
+	if a.LineageID == "" {
+		a.LineageID = parent.LineageID
+	}
+	if a.AgentID == "" {
+		a.AgentID = parent.AgentID
+	}
+	return context.WithValue(ctx, attributionKey{}, a)
+}
+
+// AttributionFrom returns the attribution ctx carries, if any.
+func AttributionFrom(ctx context.Context) Attribution {
+	a, _ := ctx.Value(attributionKey{}).(Attribution)
+	return a
+}
@@ -0,0 +1,73 @@
+package cost
+
+import (
+	"context"
+
+	"github.com/Perttulands/chiron/internal/provider"
+)
+
+// TrackedProvider records every call of the wrapped provider on a tracker
+// under one operation.
+type TrackedProvider struct {
+	provider.Provider
+	Tracker   *Tracker
+	Operation string
+}
+
+// trackedSeeder is a TrackedProvider whose wrapped provider accepts a
+// sampling seed, so provider.SupportsSeed sees through the wrapper.
+type trackedSeeder struct {
+	*TrackedProvider
+}
+
+func (p trackedSeeder) SetSeed(seed int64) {
+	p.Provider.(provider.Seeder).SetSeed(seed)
+}
+
+// Track wraps p so its calls are recorded on t as operation. The wrapper is
+// a provider.Seeder exactly when p is. A nil tracker or provider returns p
+// unchanged.
+func Track(p provider.Provider, t *Tracker, operation string) provider.Provider {
+	if p == nil || t == nil {
+		return p
+	}
+	tracked := &TrackedProvider{Provider: p, Tracker: t, Operation: operation}
+	if _, ok := p.(provider.Seeder); ok {
+		return trackedSeeder{tracked}
+	}
+	return tracked
+}
+
+func (p *TrackedProvider) GenerateAgent(ctx context.Context, need string, directives []string) (provider.AgentDefinition, provider.Metadata, error) {
+	def, meta, err := p.Provider.GenerateAgent(ctx, need, directives)
+	p.record(ctx, meta)
+	return def, meta, err
+}
+
+func (p *TrackedProvider) ExecuteAgent(ctx context.Context, agent provider.AgentDefinition, input string) (string, provider.Metadata, error) {
+	out, meta, err := p.Provider.ExecuteAgent(ctx, agent, input)
+	p.record(ctx, meta)
+	return out, meta, err
+}
+
+// record adds one call to the tracker, attributed from ctx. Failed calls
+// are recorded too when the provider reported usage for them.
+func (p *TrackedProvider) record(ctx context.Context, meta provider.Metadata) {
+	if meta.TokensInput == 0 && meta.TokensOutput == 0 && meta.CostUSD == 0 && meta.DurationMs == 0 {
+		return
+	}
+	info := p.GetMetadata()
+	a := AttributionFrom(ctx)
+	p.Tracker.Record(Event{
+		Operation:  p.Operation,
+		Provider:   info.Provider,
+		Model:      info.Model,
+		SessionID:  a.SessionID,
+		LineageID:  a.LineageID,
+		AgentID:    a.AgentID,
+		TokensIn:   meta.TokensInput,
+		TokensOut:  meta.TokensOutput,
+		CostUSD:    meta.CostUSD,
+		DurationMS: meta.DurationMs,
+	})
+}
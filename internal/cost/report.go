@@ -0,0 +1,104 @@
+package cost
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Groupings for Report.
+const (
+	BySession   = "session"
+	ByModel     = "model"
+	ByOperation = "operation"
+	ByLineage   = "lineage"
+	ByDay       = "day"
+)
+
+// ValidGroupings lists the keys Report can group by.
+var ValidGroupings = []string{BySession, ByModel, ByOperation, ByLineage, ByDay}
+
+// ReportRow is the spend of one group of events.
+type ReportRow struct {
+	Key        string  `json:"key"`
+	Calls      int     `json:"calls"`
+	TokensIn   int     `json:"tokens_in"`
+	TokensOut  int     `json:"tokens_out"`
+	CostUSD    float64 `json:"cost_usd"`
+	DurationMS int     `json:"duration_ms"`
+}
+
+// Report groups the events at or after since by key, most expensive first.
+// Events without a value for the key are grouped under "-". A zero since
+// keeps every event.
+func Report(events []Event, since time.Time, by string) ([]ReportRow, error) {
+	key, err := groupKey(by)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := map[string]*ReportRow{}
+	for _, e := range events {
+		if !since.IsZero() {
+			at, err := time.Parse(time.RFC3339, e.Timestamp)
+			if err != nil || at.Before(since) {
+				continue
+			}
+		}
+		k := key(e)
+		if k == "" {
+			k = "-"
+		}
+		row := rows[k]
+		if row == nil {
+			row = &ReportRow{Key: k}
+			rows[k] = row
+		}
+		row.Calls++
+		row.TokensIn += e.TokensIn
+		row.TokensOut += e.TokensOut
+		row.CostUSD += e.CostUSD
+		row.DurationMS += e.DurationMS
+	}
+
+	out := make([]ReportRow, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, *row)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if by == ByDay {
+			return out[i].Key < out[j].Key
+		}
+		if out[i].CostUSD != out[j].CostUSD {
+			return out[i].CostUSD > out[j].CostUSD
+		}
+		if out[i].TokensIn+out[i].TokensOut != out[j].TokensIn+out[j].TokensOut {
+			return out[i].TokensIn+out[i].TokensOut > out[j].TokensIn+out[j].TokensOut
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out, nil
+}
+
+func groupKey(by string) (func(Event) string, error) {
+	switch by {
+	case BySession:
+		return func(e Event) string { return e.SessionID }, nil
+	case ByModel:
+		return func(e Event) string { return e.Model }, nil
+	case ByOperation:
+		return func(e Event) string { return e.Operation }, nil
+	case ByLineage:
+		return func(e Event) string { return e.LineageID }, nil
+	case ByDay:
+		return func(e Event) string {
+			if len(e.Timestamp) >= len("2006-01-02") {
+				return e.Timestamp[:len("2006-01-02")]
+			}
+			return ""
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown grouping %q; choose from: %s", by, strings.Join(ValidGroupings, ", "))
+	}
+}
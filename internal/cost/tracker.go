@@ -1,15 +1,32 @@
 package cost
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 )
 
+// Operations recorded on cost events.
+const (
+	OpGenerate  = "generate"  // agent generation and evolution
+	OpExecute   = "execute"   // agent runs, including tournament bouts
+	OpMutate    = "mutate"    // mutation operators
+	OpChallenge = "challenge" // challenge generation
+	OpJudge     = "judge"     // llm_rubric grading
+)
+
+// ErrBudgetExceeded is returned, wrapped, when spend would pass a budget.
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
 // Event records a single cost-generating operation.
 type Event struct {
-	Operation  string  `json:"operation"` // "generate", "execute", "mutate", "challenge"
+	Operation  string  `json:"operation"` // "generate", "execute", "mutate", "challenge", "judge"
+	Provider   string  `json:"provider,omitempty"`
 	Model      string  `json:"model"`
+	SessionID  string  `json:"session_id,omitempty"`
+	LineageID  string  `json:"lineage_id,omitempty"`
+	AgentID    string  `json:"agent_id,omitempty"`
 	TokensIn   int     `json:"tokens_in"`
 	TokensOut  int     `json:"tokens_out"`
 	CostUSD    float64 `json:"cost_usd"`
@@ -28,14 +45,19 @@ type Summary struct {
 	ByModel         map[string]float64 `json:"by_model"`
 	BudgetUSD       float64            `json:"budget_usd"`
 	Remaining       float64            `json:"remaining"`
+	BudgetTokens    int                `json:"budget_tokens,omitempty"`
+	RemainingTokens int                `json:"remaining_tokens,omitempty"`
 	OverBudget      bool               `json:"over_budget"`
 }
 
 // Tracker monitors costs and enforces budgets.
 type Tracker struct {
-	mu        sync.Mutex
-	events    []Event
-	budgetUSD float64
+	mu           sync.Mutex
+	events       []Event
+	budgetUSD    float64
+	budgetTokens int
+	ledger       *Ledger
+	ledgerErr    error // first failed ledger append
 }
 
 // New creates a cost tracker with a budget.
@@ -46,7 +68,24 @@ func New(budgetUSD float64) *Tracker {
 	}
 }
 
-// Record adds a cost event to the tracker.
+// NewWithTokens creates a cost tracker with a USD and a token budget. A
+// budget of 0 is unlimited.
+func NewWithTokens(budgetUSD float64, budgetTokens int) *Tracker {
+	t := New(budgetUSD)
+	t.budgetTokens = budgetTokens
+	return t
+}
+
+// WithLedger makes the tracker append every event it records to l and
+// returns the tracker.
+func (t *Tracker) WithLedger(l *Ledger) *Tracker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ledger = l
+	return t
+}
+
+// Record adds a cost event to the tracker, and to its ledger if it has one.
 func (t *Tracker) Record(event Event) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -55,6 +94,17 @@ func (t *Tracker) Record(event Event) {
 		event.Timestamp = time.Now().UTC().Format(time.RFC3339)
 	}
 	t.events = append(t.events, event)
+	if t.ledger != nil && t.ledgerErr == nil {
+		t.ledgerErr = t.ledger.Append(event)
+	}
+}
+
+// LedgerErr returns the first error appending to the ledger. Recording
+// carries on in memory after a failure, so callers check it once at the end.
+func (t *Tracker) LedgerErr() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.ledgerErr
 }
 
 // TotalCost returns the current total cost.
@@ -69,6 +119,18 @@ func (t *Tracker) TotalCost() float64 {
 	return total
 }
 
+// TotalTokens returns the input plus output tokens recorded so far.
+func (t *Tracker) TotalTokens() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var total int
+	for _, e := range t.events {
+		total += e.TokensIn + e.TokensOut
+	}
+	return total
+}
+
 // Remaining returns how much budget is left.
 func (t *Tracker) Remaining() float64 {
 	return t.budgetUSD - t.TotalCost()
@@ -79,6 +141,12 @@ func (t *Tracker) OverBudget() bool {
 	return t.budgetUSD > 0 && t.TotalCost() > t.budgetUSD
 }
 
+// Exhausted reports whether spend has reached either budget.
+func (t *Tracker) Exhausted() bool {
+	return t.budgetUSD > 0 && t.TotalCost() >= t.budgetUSD ||
+		t.budgetTokens > 0 && t.TotalTokens() >= t.budgetTokens
+}
+
 // CheckBudget returns an error if the budget would be exceeded.
 func (t *Tracker) CheckBudget(estimatedCostUSD float64) error {
 	if t.budgetUSD <= 0 {
@@ -86,8 +154,24 @@ func (t *Tracker) CheckBudget(estimatedCostUSD float64) error {
 	}
 	projected := t.TotalCost() + estimatedCostUSD
 	if projected > t.budgetUSD {
-		return fmt.Errorf("budget exceeded: projected $%.4f > budget $%.4f (remaining: $%.4f)",
-			projected, t.budgetUSD, t.Remaining())
+		return fmt.Errorf("%w: projected $%.4f > budget $%.4f (remaining: $%.4f)",
+			ErrBudgetExceeded, projected, t.budgetUSD, t.Remaining())
+	}
+	return nil
+}
+
+// CheckProjection returns an error if spending p would pass either budget.
+func (t *Tracker) CheckProjection(p Projection) error {
+	if err := t.CheckBudget(p.CostUSD); err != nil {
+		return err
+	}
+	if t.budgetTokens <= 0 {
+		return nil
+	}
+	used := t.TotalTokens()
+	if projected := used + p.Tokens(); projected > t.budgetTokens {
+		return fmt.Errorf("%w: projected %d tokens > budget %d tokens (remaining: %d)",
+			ErrBudgetExceeded, projected, t.budgetTokens, t.budgetTokens-used)
 	}
 	return nil
 }
@@ -116,7 +200,12 @@ func (t *Tracker) Summarize() Summary {
 	}
 
 	s.Remaining = t.budgetUSD - s.TotalCostUSD
-	s.OverBudget = t.budgetUSD > 0 && s.TotalCostUSD > t.budgetUSD
+	s.BudgetTokens = t.budgetTokens
+	if t.budgetTokens > 0 {
+		s.RemainingTokens = t.budgetTokens - s.TotalTokensIn - s.TotalTokensOut
+	}
+	s.OverBudget = t.budgetUSD > 0 && s.TotalCostUSD > t.budgetUSD ||
+		t.budgetTokens > 0 && s.TotalTokensIn+s.TotalTokensOut > t.budgetTokens
 
 	return s
 }
@@ -0,0 +1,332 @@
+// Package diversity measures how different contestants' prompts are and
+// applies diversity pressure (fitness sharing or novelty search) to
+// tournament standings before selection.
+package diversity
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Perttulands/chiron/internal/tournament"
+)
+
+// MethodNGram is the distance method of every Report: cosine distance
+// between character n-gram profiles.
+const MethodNGram = "ngram"
+
+// Diversity pressure modes for Config.Pressure.
+const (
+	PressureNone    = "none"
+	PressureSharing = "sharing"
+	PressureNovelty = "novelty"
+)
+
+// Config tunes the diversity metric and pressure. The zero value measures
+// character trigram distance and applies no pressure. Pressure re-ranks on
+// adjusted scores, so it changes what a tie means: contestants the
+// tournament tied as not significantly different stay tied and adjacent,
+// and contestants level on points and adjusted score tie as well.
+type Config struct {
+	NGram              int     `json:"ngram,omitempty"`               // character n-gram size; default 3
+	DuplicateThreshold float64 `json:"duplicate_threshold,omitempty"` // distance below which two prompts are near-duplicates; default 0.1
+	Pressure           string  `json:"pressure,omitempty"`            // none (default), sharing or novelty
+	ShareRadius        float64 `json:"share_radius,omitempty"`        // fitness sharing niche radius; default 0.3
+	ShareAlpha         float64 `json:"share_alpha,omitempty"`         // fitness sharing kernel shape; default 1
+	NoveltyK           int     `json:"novelty_k,omitempty"`           // nearest neighbours for novelty; default 3
+	NoveltyWeight      float64 `json:"novelty_weight,omitempty"`      // novelty vs fitness in [0, 1]; default 0.3
+	ArchiveSize        int     `json:"archive_size,omitempty"`        // past prompts kept for novelty; default 50
+}
+
+// Validate reports an unknown pressure mode.
+func (c Config) Validate() error {
+	switch c.Pressure {
+	case "", PressureNone, PressureSharing, PressureNovelty:
+		return nil
+	default:
+		return fmt.Errorf("unknown diversity pressure %q; choose from: %s, %s, %s", c.Pressure, PressureNone, PressureSharing, PressureNovelty)
+	}
+}
+
+func (c Config) ngram() int {
+	if c.NGram <= 0 {
+		return 3
+	}
+	return c.NGram
+}
+
+func (c Config) duplicateThreshold() float64 {
+	if c.DuplicateThreshold <= 0 {
+		return 0.1
+	}
+	return c.DuplicateThreshold
+}
+
+// ArchiveLimit returns the maximum number of past prompts kept for novelty.
+func (c Config) ArchiveLimit() int {
+	if c.ArchiveSize <= 0 {
+		return 50
+	}
+	return c.ArchiveSize
+}
+
+// Report summarizes the diversity of one population.
+type Report struct {
+	Method         string  `json:"method"`          // ngram
+	MeanDistance   float64 `json:"mean_distance"`   // average pairwise distance; 0 is identical prompts
+	MinDistance    float64 `json:"min_distance"`    // closest pair
+	NearDuplicates int     `json:"near_duplicates"` // pairs closer than the duplicate threshold
+}
+
+// Space holds pairwise distances between a population's prompts and an
+// archive of past prompts.
+type Space struct {
+	Method string
+
+	index   map[string]int
+	members int // population size; archive entries follow
+	dist    [][]float64
+}
+
+// NewSpace computes distances between the prompts of ids (the population)
+// and archive (past prompts, used only for novelty).
+func NewSpace(ids []string, prompts map[string]string, archive []string, cfg Config) *Space {
+	texts := make([]string, 0, len(ids)+len(archive))
+	for _, id := range ids {
+		texts = append(texts, prompts[id])
+	}
+	texts = append(texts, archive...)
+
+	s := &Space{Method: MethodNGram, index: make(map[string]int, len(ids)), members: len(ids)}
+	for i, id := range ids {
+		s.index[id] = i
+	}
+
+	vectors := make([]map[string]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = ngramProfile(text, cfg.ngram())
+	}
+
+	s.dist = make([][]float64, len(texts))
+	for i := range texts {
+		s.dist[i] = make([]float64, len(texts))
+	}
+	for i := range texts {
+		for j := i + 1; j < len(texts); j++ {
+			d := 1 - cosine(vectors[i], vectors[j])
+			s.dist[i][j], s.dist[j][i] = d, d
+		}
+	}
+	return s
+}
+
+// Distance returns the distance between two population members by id, or
+// 1 if either is unknown. It satisfies selection.DistanceFunc.
+func (s *Space) Distance(a, b string) float64 {
+	i, okA := s.index[a]
+	j, okB := s.index[b]
+	if !okA || !okB {
+		return 1
+	}
+	return s.dist[i][j]
+}
+
+// Report summarizes the population's pairwise distances.
+func (s *Space) Report(cfg Config) Report {
+	r := Report{Method: s.Method}
+	pairs := 0
+	total := 0.0
+	r.MinDistance = 1
+	for i := 0; i < s.members; i++ {
+		for j := i + 1; j < s.members; j++ {
+			d := s.dist[i][j]
+			total += d
+			pairs++
+			r.MinDistance = math.Min(r.MinDistance, d)
+			if d < cfg.duplicateThreshold() {
+				r.NearDuplicates++
+			}
+		}
+	}
+	if pairs == 0 {
+		r.MinDistance = 0
+		return r
+	}
+	r.MeanDistance = total / float64(pairs)
+	return r
+}
+
+// Adjust returns a copy of standings re-scored and re-ranked under the
+// configured pressure, for selection to act on. Sharing divides each average
+// score by its niche count, the summed similarity of contestants within
+// ShareRadius, so a cluster of near-duplicates splits one niche's fitness.
+// Novelty blends the average score with the mean distance to the NoveltyK
+// nearest prompts in the population and archive, scaled to the 0-10 score
+// range. Format points still rank first, as in the tournament's standings.
+// A group the tournament tied stays together, ranked by its best adjusted
+// score and sharing a rank; contestants level on points and adjusted score
+// share a rank too. PValueNext is cleared, since it compared against the
+// unadjusted neighbour. With no pressure standings are returned unchanged.
+func Adjust(standings []tournament.Standing, s *Space, cfg Config) []tournament.Standing {
+	if cfg.Pressure == "" || cfg.Pressure == PressureNone || len(standings) == 0 {
+		return standings
+	}
+
+	adjusted := make([]tournament.Standing, len(standings))
+	copy(adjusted, standings)
+	// group numbers the runs of contestants the tournament tied.
+	group := make(map[string]int, len(standings))
+	for i, st := range standings {
+		group[st.ContestantID] = i
+		if i > 0 && st.Tied {
+			group[st.ContestantID] = group[standings[i-1].ContestantID]
+		}
+	}
+	for i := range adjusted {
+		row, ok := s.index[adjusted[i].ContestantID]
+		if !ok {
+			continue
+		}
+		switch cfg.Pressure {
+		case PressureSharing:
+			adjusted[i].AvgScore /= s.nicheCount(row, cfg)
+		case PressureNovelty:
+			weight := cfg.NoveltyWeight
+			if weight <= 0 || weight > 1 {
+				weight = 0.3
+			}
+			adjusted[i].AvgScore = (1-weight)*adjusted[i].AvgScore + weight*10*s.novelty(row, cfg)
+		}
+	}
+
+	// A tied group sorts as one entry at its best adjusted score.
+	groupScore := map[int]float64{}
+	for _, st := range adjusted {
+		g := group[st.ContestantID]
+		if best, ok := groupScore[g]; !ok || st.AvgScore > best {
+			groupScore[g] = st.AvgScore
+		}
+	}
+	sort.SliceStable(adjusted, func(i, j int) bool {
+		if adjusted[i].Points != adjusted[j].Points {
+			return adjusted[i].Points > adjusted[j].Points
+		}
+		if (adjusted[i].BoutsPlayed == 0) != (adjusted[j].BoutsPlayed == 0) {
+			return adjusted[j].BoutsPlayed == 0
+		}
+		gi, gj := group[adjusted[i].ContestantID], group[adjusted[j].ContestantID]
+		if gi != gj && groupScore[gi] != groupScore[gj] {
+			return groupScore[gi] > groupScore[gj]
+		}
+		if gi != gj {
+			return gi < gj
+		}
+		return adjusted[i].AvgScore > adjusted[j].AvgScore
+	})
+	for i := range adjusted {
+		adjusted[i].Rank = i + 1
+		adjusted[i].Tied = false
+		adjusted[i].PValueNext = nil
+		if i == 0 || adjusted[i].Points != adjusted[i-1].Points {
+			continue
+		}
+		sameGroup := group[adjusted[i].ContestantID] == group[adjusted[i-1].ContestantID]
+		if sameGroup || adjusted[i].AvgScore == adjusted[i-1].AvgScore {
+			adjusted[i].Rank = adjusted[i-1].Rank
+			adjusted[i].Tied = true
+		}
+	}
+	return adjusted
+}
+
+// nicheCount sums the sharing kernel 1-(d/radius)^alpha over the population,
+// including the contestant itself, so it is at least 1.
+func (s *Space) nicheCount(row int, cfg Config) float64 {
+	radius := cfg.ShareRadius
+	if radius <= 0 {
+		radius = 0.3
+	}
+	alpha := cfg.ShareAlpha
+	if alpha <= 0 {
+		alpha = 1
+	}
+	count := 0.0
+	for j := 0; j < s.members; j++ {
+		if d := s.dist[row][j]; d < radius {
+			count += 1 - math.Pow(d/radius, alpha)
+		}
+	}
+	return math.Max(count, 1)
+}
+
+// novelty is the mean distance from row to its k nearest other prompts in
+// the population and archive.
+func (s *Space) novelty(row int, cfg Config) float64 {
+	k := cfg.NoveltyK
+	if k <= 0 {
+		k = 3
+	}
+	others := make([]float64, 0, len(s.dist)-1)
+	for j, d := range s.dist[row] {
+		if j != row {
+			others = append(others, d)
+		}
+	}
+	if len(others) == 0 {
+		return 0
+	}
+	sort.Float64s(others)
+	if k > len(others) {
+		k = len(others)
+	}
+	total := 0.0
+	for _, d := range others[:k] {
+		total += d
+	}
+	return total / float64(k)
+}
+
+// NGramDistance returns the cosine distance between the character n-gram
+// profiles of a and b: 0 for identical text, 1 for nothing in common.
+func NGramDistance(a, b string, n int) float64 {
+	return 1 - cosine(ngramProfile(a, n), ngramProfile(b, n))
+}
+
+// ngramProfile counts the character n-grams of text after lowercasing and
+// collapsing whitespace.
+func ngramProfile(text string, n int) map[string]float64 {
+	runes := []rune(strings.Join(strings.Fields(strings.ToLower(text)), " "))
+	profile := map[string]float64{}
+	if len(runes) == 0 {
+		return profile
+	}
+	if len(runes) < n {
+		profile[string(runes)]++
+		return profile
+	}
+	for i := 0; i+n <= len(runes); i++ {
+		profile[string(runes[i:i+n])]++
+	}
+	return profile
+}
+
+// cosine returns the cosine similarity of two sparse vectors, clamped to
+// [0, 1]. Two empty vectors are identical.
+func cosine(a, b map[string]float64) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	var dot, normA, normB float64
+	for k, x := range a {
+		dot += x * b[k]
+		normA += x * x
+	}
+	for _, y := range b {
+		normB += y * y
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return math.Min(math.Max(dot/math.Sqrt(normA*normB), 0), 1)
+}
@@ -8,8 +8,13 @@ import (
 	"github.com/Perttulands/chiron/internal/state"
 )
 
-// GenerateEvolutionPrompt synthesizes artifact evaluations and directives into
-// a structured prompt used to produce the next agent version.
+// OperatorEvolve is the provenance operator of agents produced from an
+// evolution prompt by iterate.
+const OperatorEvolve = "evolve"
+
+// GenerateEvolutionPrompt synthesizes artifact evaluations, harness failures
+// and directives into a structured prompt used to produce the next agent
+// version.
 func GenerateEvolutionPrompt(agents []state.Agent, artifacts []state.Artifact, directives []state.Directive) string {
 	currentVersion, currentSystemPrompt := latestAgentPrompt(agents)
 	evaluated := evaluatedArtifacts(artifacts)
@@ -59,6 +64,7 @@ func GenerateEvolutionPrompt(agents []state.Agent, artifacts []state.Artifact, d
 		}
 	}
 
+	harnessSummary, failedAssertions := summarizeHarnessResults(artifacts)
 	directiveText := formatDirectives(directives)
 
 	return fmt.Sprintf(`You are a master AI agent trainer. Improve the following agent based on evaluation feedback.
@@ -81,6 +87,12 @@ LOW-SCORING PATTERNS (score < 5):
 HIGH-SCORING PATTERNS (score >= 8):
 %s
 
+HARNESS RESULTS:
+%s
+
+FAILED ASSERTIONS:
+%s
+
 DIRECTIVES:
 %s
 
@@ -90,7 +102,7 @@ Output a JSON object with the following structure:
   "reasoning": "brief explanation of changes made"
 }
 
-Focus on addressing low-scoring feedback while preserving high-scoring behaviors.`,
+Focus on addressing low-scoring feedback and failed assertions while preserving high-scoring behaviors.`,
 		currentVersion,
 		currentSystemPrompt,
 		totalArtifacts,
@@ -100,6 +112,8 @@ Focus on addressing low-scoring feedback while preserving high-scoring behaviors
 		feedbackList,
 		lowPatterns,
 		highPatterns,
+		harnessSummary,
+		failedAssertions,
 		directiveText,
 	)
 }
@@ -134,6 +148,73 @@ func evaluatedArtifacts(artifacts []state.Artifact) []state.Artifact {
 	return out
 }
 
+// maxFailedAssertions caps the FAILED ASSERTIONS section so a large suite
+// does not crowd the rest of the prompt out.
+const maxFailedAssertions = 20
+
+type assertionFailure struct {
+	label  string
+	count  int
+	detail string
+}
+
+// summarizeHarnessResults aggregates harness-scored artifacts into a summary
+// line block and a list of failed assertions, most frequent first.
+func summarizeHarnessResults(artifacts []state.Artifact) (string, string) {
+	scored := 0
+	passRate := 0.0
+	failures := map[string]*assertionFailure{}
+	order := []string{}
+
+	for _, artifact := range artifacts {
+		if artifact.HarnessResult == nil {
+			continue
+		}
+		scored++
+		passRate += artifact.HarnessResult.PassRate
+		for _, result := range artifact.HarnessResult.FailedResults() {
+			key := result.TestCaseID
+			if key == "" {
+				key = result.TestName
+			}
+			failure, ok := failures[key]
+			if !ok {
+				label := result.TestName
+				if label == "" {
+					label = result.TestCaseID
+				}
+				failure = &assertionFailure{label: label}
+				failures[key] = failure
+				order = append(order, key)
+			}
+			failure.count++
+			failure.detail = strings.TrimSpace(result.Detail)
+		}
+	}
+
+	if scored == 0 {
+		return "- No harness-scored artifacts", "- None"
+	}
+
+	summary := fmt.Sprintf("- Harness-scored artifacts: %d\n- Average pass rate: %.0f%%", scored, 100*passRate/float64(scored))
+	if len(order) == 0 {
+		return summary, "- None"
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return failures[order[i]].count > failures[order[j]].count
+	})
+	if len(order) > maxFailedAssertions {
+		order = order[:maxFailedAssertions]
+	}
+	lines := make([]string, 0, len(order))
+	for _, key := range order {
+		failure := failures[key]
+		lines = append(lines, fmt.Sprintf("- [failed %d/%d runs] %s: %s", failure.count, scored, failure.label, failure.detail))
+	}
+	return summary, strings.Join(lines, "\n")
+}
+
 func formatScoreHistogram(histogram map[int]int) string {
 	if len(histogram) == 0 {
 		return "No evaluation yet"
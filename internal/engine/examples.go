@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"github.com/Perttulands/chiron/internal/provider"
+	"github.com/Perttulands/chiron/internal/state"
+)
+
+// Few-shot example modes for state.AgentDefinition.ExampleMode.
+const (
+	ExampleModePrompt   = "prompt"   // render examples into the system prompt
+	ExampleModeMessages = "messages" // send examples as prior chat turns
+)
+
+// ProviderDefinition converts def for a provider call. In messages mode the
+// examples travel as prior turns; otherwise they are rendered into the
+// system prompt.
+func ProviderDefinition(def state.AgentDefinition) provider.AgentDefinition {
+	out := provider.AgentDefinition{
+		SystemPrompt: def.SystemPrompt,
+		Model:        def.Model,
+		Temperature:  def.Temperature,
+		MaxTokens:    def.MaxTokens,
+	}
+	if def.ExampleMode == ExampleModeMessages {
+		out.Examples = providerExamples(def.Examples)
+	} else {
+		out.SystemPrompt = RenderedSystemPrompt(def)
+	}
+	return out
+}
+
+// RenderedSystemPrompt returns def's system prompt with its few-shot
+// examples appended, for executors that take a single prompt.
+func RenderedSystemPrompt(def state.AgentDefinition) string {
+	return provider.RenderExamples(def.SystemPrompt, providerExamples(def.Examples))
+}
+
+func providerExamples(examples []state.FewShotExample) []provider.Example {
+	if len(examples) == 0 {
+		return nil
+	}
+	out := make([]provider.Example, 0, len(examples))
+	for _, ex := range examples {
+		out = append(out, provider.Example{Input: ex.Input, Output: ex.Output})
+	}
+	return out
+}
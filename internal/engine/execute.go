@@ -63,12 +63,7 @@ func executeAPI(ctx context.Context, req ExecuteRequest) (ExecuteResult, error)
 		return ExecuteResult{}, fmt.Errorf("provider is required for api mode")
 	}
 
-	out, meta, err := req.Provider.ExecuteAgent(ctx, provider.AgentDefinition{
-		SystemPrompt: req.Definition.SystemPrompt,
-		Model:        req.Definition.Model,
-		Temperature:  req.Definition.Temperature,
-		MaxTokens:    req.Definition.MaxTokens,
-	}, req.Input)
+	out, meta, err := req.Provider.ExecuteAgent(ctx, ProviderDefinition(req.Definition), req.Input)
 	if err != nil {
 		return ExecuteResult{}, fmt.Errorf("execute provider call: %w", err)
 	}
@@ -104,7 +99,7 @@ func executeCLI(ctx context.Context, req ExecuteRequest) (ExecuteResult, error)
 	}
 
 	start := time.Now()
-	cliInput := fmt.Sprintf("system_prompt:\n%s\n\nuser_input:\n%s\n", req.Definition.SystemPrompt, req.Input)
+	cliInput := fmt.Sprintf("system_prompt:\n%s\n\nuser_input:\n%s\n", RenderedSystemPrompt(req.Definition), req.Input)
 	cmd := exec.CommandContext(ctx, commandPath)
 	cmd.Stdin = strings.NewReader(cliInput)
 	output, err := cmd.CombinedOutput()
@@ -169,7 +164,7 @@ func executeSealed(ctx context.Context, req ExecuteRequest) (ExecuteResult, erro
 	}
 	defer os.Remove(promptFile.Name())
 
-	if _, err := promptFile.WriteString(req.Definition.SystemPrompt); err != nil {
+	if _, err := promptFile.WriteString(RenderedSystemPrompt(req.Definition)); err != nil {
 		promptFile.Close()
 		return ExecuteResult{}, fmt.Errorf("write system prompt: %w", err)
 	}
@@ -0,0 +1,139 @@
+package experiment
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Perttulands/chiron/internal/cost"
+)
+
+// Forecast bases.
+const (
+	BasisHistory = "history" // average tokens of the model's completed cells
+	BasisPrompt  = "prompt"  // prompt size only; multi-turn runs cost more
+)
+
+// ModelForecast is the projected spend of one model's pending cells.
+type ModelForecast struct {
+	Model     string          `json:"model"`
+	Provider  string          `json:"provider"`
+	Pending   int             `json:"pending"`
+	Completed int             `json:"completed"`
+	Basis     string          `json:"basis"`
+	Cost      cost.Projection `json:"projection"`
+}
+
+// PerCell returns the projected spend of one of the model's pending cells.
+func (f ModelForecast) PerCell() cost.Projection {
+	if f.Pending == 0 {
+		return cost.Projection{}
+	}
+	n := f.Pending
+	return cost.Projection{
+		Calls:     f.Cost.Calls / n,
+		TokensIn:  f.Cost.TokensIn / n,
+		TokensOut: f.Cost.TokensOut / n,
+		CostUSD:   f.Cost.CostUSD / float64(n),
+		Unpriced:  f.Cost.Unpriced / n,
+	}
+}
+
+// cellTokens mirrors the usage fields of meta.json.
+type cellTokens struct {
+	TokensIn  int `json:"tokens_in"`
+	TokensOut int `json:"tokens_out"`
+}
+
+// Forecast projects the spend of the cells Run would execute for cfg and
+// opts. Completed cells are skipped, as Run skips them. Each pending cell
+// costs the average tokens of the model's completed cells in any
+// condition; a model without completed cells is estimated from the size of
+// its system and user prompts, which undercounts runs that take many turns.
+func Forecast(cfg *Config, baseDir string, opts RunOptions) ([]ModelForecast, error) {
+	userPrompt, err := os.ReadFile(filepath.Join(baseDir, cfg.Scenario.UserPrompt))
+	if err != nil {
+		return nil, fmt.Errorf("reading user prompt: %w", err)
+	}
+	systemPrompts := map[string]int{}
+	for _, cond := range cfg.Conditions {
+		data, err := os.ReadFile(filepath.Join(baseDir, cond.SystemPrompt))
+		if err != nil {
+			return nil, fmt.Errorf("reading system prompt %s: %w", cond.SystemPrompt, err)
+		}
+		systemPrompts[cond.Name] = cost.EstimateTokens(string(data))
+	}
+
+	type pending struct {
+		model ModelConfig
+		cells []Cell
+		done  int
+	}
+	byModel := map[string]*pending{}
+	order := []string{}
+	for _, cell := range MatrixCells(cfg, opts) {
+		p := byModel[cell.Model.ID]
+		if p == nil {
+			p = &pending{model: cell.Model}
+			byModel[cell.Model.ID] = p
+			order = append(order, cell.Model.ID)
+		}
+		outDir := cellOutputDir(baseDir, cell.Model.ID, cell.Condition.Name, cell.Replica)
+		if _, err := os.Stat(filepath.Join(outDir, "meta.json")); err == nil {
+			p.done++
+			continue
+		}
+		p.cells = append(p.cells, cell)
+	}
+
+	out := make([]ModelForecast, 0, len(order))
+	for _, id := range order {
+		p := byModel[id]
+		f := ModelForecast{Model: id, Provider: p.model.Provider, Pending: len(p.cells), Completed: p.done}
+		history, err := completedTokens(baseDir, id)
+		if err != nil {
+			return nil, err
+		}
+		if len(history) > 0 {
+			f.Basis = BasisHistory
+			var in, outTokens int
+			for _, h := range history {
+				in += h.TokensIn
+				outTokens += h.TokensOut
+			}
+			f.Cost.AddCalls(len(p.cells), p.model.Provider, id, in/len(history), outTokens/len(history))
+		} else {
+			f.Basis = BasisPrompt
+			user := cost.EstimateTokens(string(userPrompt))
+			for _, cell := range p.cells {
+				f.Cost.AddCalls(1, p.model.Provider, id, systemPrompts[cell.Condition.Name]+user, cost.DefaultOutputTokens)
+			}
+		}
+		out = append(out, f)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Cost.CostUSD > out[j].Cost.CostUSD })
+	return out, nil
+}
+
+// completedTokens returns the usage of every completed cell of model.
+func completedTokens(baseDir, model string) ([]cellTokens, error) {
+	metas, err := filepath.Glob(filepath.Join(modelOutputDir(baseDir, model), "*", "meta.json"))
+	if err != nil {
+		return nil, err
+	}
+	out := []cellTokens{}
+	for _, path := range metas {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		var t cellTokens
+		if err := json.Unmarshal(data, &t); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
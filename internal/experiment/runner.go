@@ -10,6 +10,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Perttulands/chiron/internal/cost"
+	"github.com/Perttulands/chiron/internal/provider"
 	"github.com/Perttulands/chiron/internal/sandbox"
 )
 
@@ -49,6 +51,12 @@ type RunOptions struct {
 
 	// DryRun prints what would run without executing anything.
 	DryRun bool
+
+	// Budget, if set, records the spend of every cell and stops the run
+	// before a cell that would pass its limits. Cells are projected at the
+	// average spend of the cells run so far; until one has run, at the
+	// Forecast of the cell's model.
+	Budget *cost.Tracker
 }
 
 // Cell is a single point in the model × condition × replica matrix.
@@ -113,6 +121,19 @@ func (r *Runner) Run(ctx context.Context, cfg *Config, opts RunOptions) ([]CellR
 	scenarioDir := filepath.Join(r.baseDir, cfg.Scenario.Workspace)
 
 	var results []CellResult
+	var spent cost.Projection
+	// forecast holds the projected spend of one pending cell per model,
+	// used before any cell has measured what a cell costs.
+	forecast := map[string]cost.Projection{}
+	if opts.Budget != nil {
+		forecasts, err := Forecast(cfg, r.baseDir, opts)
+		if err != nil {
+			return nil, fmt.Errorf("forecast experiment spend: %w", err)
+		}
+		for _, f := range forecasts {
+			forecast[f.Model] = f.PerCell()
+		}
+	}
 
 	for i, cell := range cells {
 		select {
@@ -131,6 +152,17 @@ func (r *Runner) Run(ctx context.Context, cfg *Config, opts RunOptions) ([]CellR
 			continue
 		}
 
+		if opts.Budget != nil {
+			next := forecast[cell.Model.ID]
+			if n := spent.Calls; n > 0 {
+				next = cost.Projection{Calls: 1, TokensIn: spent.TokensIn / n, TokensOut: spent.TokensOut / n, CostUSD: spent.CostUSD / float64(n)}
+			}
+			if err := opts.Budget.CheckProjection(next); err != nil {
+				return results, fmt.Errorf("stopped before model=%s condition=%s replica=%d: %w",
+					cell.Model.ID, cell.Condition.Name, cell.Replica, err)
+			}
+		}
+
 		// Read per-condition system prompt.
 		sysPromptPath := filepath.Join(r.baseDir, cell.Condition.SystemPrompt)
 		sysPromptBytes, err := os.ReadFile(sysPromptPath)
@@ -167,6 +199,20 @@ func (r *Runner) Run(ctx context.Context, cfg *Config, opts RunOptions) ([]CellR
 				cell.Model.ID, cell.Condition.Name, cell.Replica, err)
 		}
 
+		if opts.Budget != nil {
+			spent.AddCalls(1, cell.Model.Provider, cell.Model.ID, res.TokensIn, res.TokensOut)
+			price, _ := provider.PriceFor(cell.Model.Provider, cell.Model.ID)
+			opts.Budget.Record(cost.Event{
+				Operation:  cost.OpExecute,
+				Provider:   cell.Model.Provider,
+				Model:      cell.Model.ID,
+				TokensIn:   res.TokensIn,
+				TokensOut:  res.TokensOut,
+				CostUSD:    price.Cost(res.TokensIn, res.TokensOut),
+				DurationMS: res.DurationMs,
+			})
+		}
+
 		log.Printf("[%d/%d][%d%%] model=%s condition=%s replica=%d turns=%d wall=%ds",
 			i+1, total, pct(i+1, total),
 			cell.Model.ID, cell.Condition.Name, cell.Replica,
@@ -185,13 +231,18 @@ func (r *Runner) Run(ctx context.Context, cfg *Config, opts RunOptions) ([]CellR
 }
 
 // cellOutputDir returns the canonical output directory for a matrix cell.
-// safeModelID replaces ':' and '/' with '-' so it is usable as a path component.
 func cellOutputDir(baseDir, modelID, conditionName string, replica int) string {
-	safeModelID := strings.NewReplacer(":", "-", "/", "-").Replace(modelID)
-	return filepath.Join(baseDir, "runs", safeModelID,
+	return filepath.Join(modelOutputDir(baseDir, modelID),
 		fmt.Sprintf("%s-%d", conditionName, replica))
 }
 
+// modelOutputDir returns the directory holding a model's cells.
+// safeModelID replaces ':' and '/' with '-' so it is usable as a path component.
+func modelOutputDir(baseDir, modelID string) string {
+	safeModelID := strings.NewReplacer(":", "-", "/", "-").Replace(modelID)
+	return filepath.Join(baseDir, "runs", safeModelID)
+}
+
 // saveResults writes all output artefacts for a completed cell.
 func saveResults(outDir string, cell Cell, res *sandbox.RunResult, wall time.Duration, engine string) error {
 	if err := os.MkdirAll(outDir, 0o755); err != nil {
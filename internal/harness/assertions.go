@@ -0,0 +1,343 @@
+package harness
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// evaluate scores one test case against output. It returns the credit
+// earned (0.0-1.0) and a human-readable detail line. llm_rubric and
+// code_exec cases, which can fail for reasons other than the output, are
+// judged by checkRubric and checkCodeExec instead.
+func evaluate(tc TestCase, output string) (float64, string) {
+	switch strings.ToLower(strings.TrimSpace(tc.Type)) {
+	case TypeContains:
+		return checkContains(output, tc.Expected, tc.IgnoreCase)
+
+	case TypeIContains:
+		return checkContains(output, tc.Expected, true)
+
+	case TypeNotContains:
+		if !containsFold(output, tc.Expected, tc.IgnoreCase) {
+			return 1, fmt.Sprintf("output does not contain %q (as expected)", tc.Expected)
+		}
+		return 0, fmt.Sprintf("output contains %q (unexpected)", tc.Expected)
+
+	case TypeContainsAll:
+		return checkContainsAll(output, assertionValues(tc), tc.IgnoreCase)
+
+	case TypeContainsAny:
+		return checkContainsAny(output, assertionValues(tc), tc.IgnoreCase)
+
+	case TypeStartsWith:
+		trimmed := strings.TrimSpace(output)
+		if tc.IgnoreCase {
+			if strings.HasPrefix(strings.ToLower(trimmed), strings.ToLower(tc.Expected)) {
+				return 1, fmt.Sprintf("output starts with %q", tc.Expected)
+			}
+		} else if strings.HasPrefix(trimmed, tc.Expected) {
+			return 1, fmt.Sprintf("output starts with %q", tc.Expected)
+		}
+		return 0, fmt.Sprintf("output does not start with %q", tc.Expected)
+
+	case TypeRegex:
+		re, err := regexp.Compile(tc.Expected)
+		if err != nil {
+			return 0, fmt.Sprintf("invalid regex %q: %v", tc.Expected, err)
+		}
+		if re.MatchString(output) {
+			return 1, fmt.Sprintf("output matches regex %q", tc.Expected)
+		}
+		return 0, fmt.Sprintf("output does not match regex %q", tc.Expected)
+
+	case TypeEquals:
+		got, want := strings.TrimSpace(output), strings.TrimSpace(tc.Expected)
+		if got == want || (tc.IgnoreCase && strings.EqualFold(got, want)) {
+			return 1, "output equals expected"
+		}
+		return 0, "output does not equal expected"
+
+	case TypeWordCount:
+		return checkBounds("word count", float64(len(strings.Fields(output))), tc.Min, tc.Max)
+
+	case TypeCharCount:
+		return checkBounds("character count", float64(utf8.RuneCountInString(strings.TrimSpace(output))), tc.Min, tc.Max)
+
+	case TypeJSONPath:
+		return checkJSONPath(output, tc)
+
+	case TypeNumeric:
+		return checkNumeric(output, tc)
+
+	case TypeValidJSON:
+		var v any
+		if err := json.Unmarshal([]byte(structuredPayload(output)), &v); err != nil {
+			return 0, fmt.Sprintf("output is not valid JSON: %v", err)
+		}
+		return 1, "output is valid JSON"
+
+	case TypeValidYAML:
+		payload := structuredPayload(output)
+		if strings.TrimSpace(payload) == "" {
+			return 0, "output is empty, not YAML"
+		}
+		var v any
+		if err := yaml.Unmarshal([]byte(payload), &v); err != nil {
+			return 0, fmt.Sprintf("output is not valid YAML: %v", err)
+		}
+		return 1, "output is valid YAML"
+
+	case TypeLevenshtein:
+		sim := levenshteinSimilarity(strings.TrimSpace(output), strings.TrimSpace(tc.Expected), tc.IgnoreCase)
+		return checkSimilarity("levenshtein", sim, tc.Threshold)
+
+	case TypeROUGE:
+		sim := rougeL(output, tc.Expected)
+		return checkSimilarity("rouge-l", sim, tc.Threshold)
+
+	default:
+		return 0, fmt.Sprintf("unknown test type %q", tc.Type)
+	}
+}
+
+func containsFold(output, expected string, ignoreCase bool) bool {
+	if ignoreCase {
+		return strings.Contains(strings.ToLower(output), strings.ToLower(expected))
+	}
+	return strings.Contains(output, expected)
+}
+
+func checkContains(output, expected string, ignoreCase bool) (float64, string) {
+	if containsFold(output, expected, ignoreCase) {
+		return 1, fmt.Sprintf("output contains %q", expected)
+	}
+	return 0, fmt.Sprintf("output does not contain %q", expected)
+}
+
+// assertionValues returns the value list for multi-value assertions, falling
+// back to a comma-separated Expected for compact hand-written cases.
+func assertionValues(tc TestCase) []string {
+	if len(tc.Values) > 0 {
+		return tc.Values
+	}
+	values := []string{}
+	for _, part := range strings.Split(tc.Expected, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			values = append(values, trimmed)
+		}
+	}
+	return values
+}
+
+func checkContainsAll(output string, values []string, ignoreCase bool) (float64, string) {
+	if len(values) == 0 {
+		return 0, "contains_all requires at least one value"
+	}
+	missing := []string{}
+	for _, v := range values {
+		if !containsFold(output, v, ignoreCase) {
+			missing = append(missing, v)
+		}
+	}
+	found := len(values) - len(missing)
+	credit := float64(found) / float64(len(values))
+	if len(missing) == 0 {
+		return credit, fmt.Sprintf("output contains all %d values", len(values))
+	}
+	return credit, fmt.Sprintf("output contains %d/%d values; missing %q", found, len(values), missing)
+}
+
+func checkContainsAny(output string, values []string, ignoreCase bool) (float64, string) {
+	if len(values) == 0 {
+		return 0, "contains_any requires at least one value"
+	}
+	for _, v := range values {
+		if containsFold(output, v, ignoreCase) {
+			return 1, fmt.Sprintf("output contains %q", v)
+		}
+	}
+	return 0, fmt.Sprintf("output contains none of %q", values)
+}
+
+// checkBounds gives full credit inside [min, max] and decays linearly with
+// relative distance outside it, reaching zero at twice the bound.
+func checkBounds(label string, value float64, min, max *float64) (float64, string) {
+	if min == nil && max == nil {
+		return 0, fmt.Sprintf("%s assertion requires min or max", label)
+	}
+	bounds := describeBounds(min, max)
+	if min != nil && value < *min {
+		credit := 0.0
+		if *min > 0 {
+			credit = 1 - (*min-value) / *min
+		}
+		return credit, fmt.Sprintf("%s %g below %s", label, value, bounds)
+	}
+	if max != nil && value > *max {
+		credit := 0.0
+		if *max > 0 {
+			credit = 1 - (value-*max) / *max
+		}
+		return credit, fmt.Sprintf("%s %g above %s", label, value, bounds)
+	}
+	return 1, fmt.Sprintf("%s %g within %s", label, value, bounds)
+}
+
+func checkJSONPath(output string, tc TestCase) (float64, string) {
+	if strings.TrimSpace(tc.Path) == "" {
+		return 0, "json_path assertion requires a path"
+	}
+	var doc any
+	if err := json.Unmarshal([]byte(structuredPayload(output)), &doc); err != nil {
+		return 0, fmt.Sprintf("output is not valid JSON: %v", err)
+	}
+	value, err := lookupJSONPath(doc, tc.Path)
+	if err != nil {
+		return 0, fmt.Sprintf("json path %s: %v", tc.Path, err)
+	}
+	if strings.TrimSpace(tc.Expected) == "" {
+		return 1, fmt.Sprintf("json path %s exists", tc.Path)
+	}
+	if number, ok := value.(float64); ok {
+		if want, err := strconv.ParseFloat(strings.TrimSpace(tc.Expected), 64); err == nil {
+			return compareNumbers(fmt.Sprintf("json path %s", tc.Path), number, want, tc.Tolerance)
+		}
+	}
+	got := jsonScalarString(value)
+	want := strings.TrimSpace(tc.Expected)
+	if got == want || (tc.IgnoreCase && strings.EqualFold(got, want)) {
+		return 1, fmt.Sprintf("json path %s equals %q", tc.Path, want)
+	}
+	return 0, fmt.Sprintf("json path %s is %q, expected %q", tc.Path, got, want)
+}
+
+func checkNumeric(output string, tc TestCase) (float64, string) {
+	want, err := strconv.ParseFloat(strings.TrimSpace(tc.Expected), 64)
+	if err != nil {
+		return 0, fmt.Sprintf("numeric assertion has non-numeric expected %q", tc.Expected)
+	}
+
+	if strings.TrimSpace(tc.Path) != "" {
+		return checkJSONPath(output, tc)
+	}
+
+	got, ok := firstNumber(output)
+	if !ok {
+		return 0, "output contains no number"
+	}
+	return compareNumbers("number", got, want, tc.Tolerance)
+}
+
+// compareNumbers gives full credit within tolerance and tolerance/diff
+// credit beyond it, so twice the tolerance earns half credit.
+func compareNumbers(label string, got, want, tolerance float64) (float64, string) {
+	diff := math.Abs(got - want)
+	if diff <= tolerance {
+		return 1, fmt.Sprintf("%s %g within %g of %g", label, got, tolerance, want)
+	}
+	credit := 0.0
+	if tolerance > 0 {
+		credit = tolerance / diff
+	}
+	return credit, fmt.Sprintf("%s %g differs from %g by %g (tolerance %g)", label, got, want, diff, tolerance)
+}
+
+var numberPattern = regexp.MustCompile(`[-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?`)
+
+func firstNumber(output string) (float64, bool) {
+	match := numberPattern.FindString(output)
+	if match == "" {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(match, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// checkSimilarity gives full credit at or above the threshold and
+// proportional credit below it.
+func checkSimilarity(label string, sim, threshold float64) (float64, string) {
+	if threshold <= 0 || threshold > 1 {
+		threshold = DefaultSimilarityThreshold
+	}
+	if sim >= threshold {
+		return 1, fmt.Sprintf("%s similarity %.2f >= %.2f", label, sim, threshold)
+	}
+	return sim / threshold, fmt.Sprintf("%s similarity %.2f below %.2f", label, sim, threshold)
+}
+
+func jsonScalarString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return "null"
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+// structuredPayload returns the body of the first fenced code block when the
+// output has one, otherwise the trimmed output. Agents routinely wrap JSON
+// and YAML answers in markdown fences.
+func structuredPayload(output string) string {
+	blocks := FencedBlocks(output)
+	if len(blocks) > 0 {
+		return blocks[0].Body
+	}
+	return strings.TrimSpace(output)
+}
+
+// CodeBlock is one fenced code block extracted from markdown output.
+type CodeBlock struct {
+	Lang string // first word of the info string
+	Info string // full info string after the opening fence
+	Body string
+}
+
+// FencedBlocks extracts ``` fenced code blocks from output in order.
+func FencedBlocks(output string) []CodeBlock {
+	blocks := []CodeBlock{}
+	lines := strings.Split(output, "\n")
+	inBlock := false
+	var current CodeBlock
+	var body []string
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "```") {
+			if inBlock {
+				body = append(body, line)
+			}
+			continue
+		}
+		if !inBlock {
+			inBlock = true
+			info := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			current = CodeBlock{Info: info}
+			if fields := strings.Fields(info); len(fields) > 0 {
+				current.Lang = strings.ToLower(fields[0])
+			}
+			body = body[:0]
+			continue
+		}
+		current.Body = strings.Join(body, "\n")
+		blocks = append(blocks, current)
+		inBlock = false
+	}
+
+	return blocks
+}
@@ -0,0 +1,285 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Perttulands/chiron/internal/sandbox"
+)
+
+const defaultExecTimeoutSeconds = 120
+
+// ExecSpec configures a code_exec test case: the agent's fenced code blocks
+// are written into a scratch workspace and Command is run there.
+type ExecSpec struct {
+	Command        string            `json:"command" yaml:"command"`                                     // test command, e.g. "go test -v ./..."
+	Workspace      string            `json:"workspace,omitempty" yaml:"workspace,omitempty"`             // base directory copied into the scratch workspace
+	Files          map[string]string `json:"files,omitempty" yaml:"files,omitempty"`                     // fixture files written after the agent's code (path -> content)
+	DefaultFile    string            `json:"default_file,omitempty" yaml:"default_file,omitempty"`       // destination for code blocks without a filename
+	Languages      []string          `json:"languages,omitempty" yaml:"languages,omitempty"`             // only extract blocks in these languages
+	Sandbox        string            `json:"sandbox,omitempty" yaml:"sandbox,omitempty"`                 // "bwrap", the only engine; see WithUnsandboxedExec
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty" yaml:"timeout_seconds,omitempty"` // default 120
+	Network        bool              `json:"network,omitempty" yaml:"network,omitempty"`                 // allow network access inside bwrap
+}
+
+type unsandboxedKey struct{}
+
+// WithUnsandboxedExec returns a context under which code_exec runs test
+// commands directly on the host instead of inside bwrap. It is an operator
+// decision (chiron --unsandboxed-exec); a challenge file cannot ask for it.
+func WithUnsandboxedExec(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unsandboxedKey{}, true)
+}
+
+// checkCodeExec runs a code_exec case. Failures of the sandbox itself (a
+// workspace that cannot be prepared, a missing bwrap, the bout being
+// cancelled or running out of time) are returned as errors rather than
+// scored: they say nothing about the agent's code.
+func checkCodeExec(ctx context.Context, tc TestCase, output string) (float64, string, error) {
+	spec := tc.Exec
+	if spec == nil || strings.TrimSpace(spec.Command) == "" {
+		return 0, "code_exec assertion requires exec.command", nil
+	}
+
+	files, err := CodeFiles(output, *spec)
+	if err != nil {
+		return 0, fmt.Sprintf("code_exec: %v", err), nil
+	}
+	if len(files) == 0 {
+		return 0, "code_exec: output contains no fenced code blocks", nil
+	}
+	// Fixtures win so an agent cannot overwrite the tests that grade it.
+	for path, content := range spec.Files {
+		files[path] = content
+	}
+
+	workspace, cleanup, err := sandbox.PrepareWorkspace(spec.Workspace, files)
+	if err != nil {
+		return 0, "", fmt.Errorf("code_exec: prepare workspace: %w", err)
+	}
+	defer cleanup()
+
+	engine := "bwrap"
+	if unsandboxed, _ := ctx.Value(unsandboxedKey{}).(bool); unsandboxed {
+		engine = "none"
+	}
+	timeout := spec.TimeoutSeconds
+	if timeout <= 0 {
+		timeout = defaultExecTimeoutSeconds
+	}
+
+	res, err := sandbox.RunCommand(ctx, sandbox.Config{
+		Engine:  engine,
+		Timeout: time.Duration(timeout) * time.Second,
+		Offline: !spec.Network,
+	}, workspace, spec.Command)
+	if err != nil {
+		return 0, "", fmt.Errorf("code_exec: %w", err)
+	}
+	if ctx.Err() != nil {
+		// The bout, not the test command, was cancelled or timed out.
+		return 0, "", fmt.Errorf("code_exec: %w", ctx.Err())
+	}
+	if res.TimedOut {
+		return 0, fmt.Sprintf("code_exec: %q timed out after %ds", spec.Command, timeout), nil
+	}
+
+	combined := res.Output()
+	passed, failed, framework, ok := ParseTestCounts(combined)
+	if !ok {
+		if res.ExitCode == 0 {
+			return 1, fmt.Sprintf("%q exited 0", spec.Command), nil
+		}
+		return 0, fmt.Sprintf("%q exited %d: %s", spec.Command, res.ExitCode, outputTail(combined, 300)), nil
+	}
+
+	total := passed + failed
+	credit := float64(passed) / float64(total)
+	if failed == 0 && res.ExitCode != 0 {
+		// All reported tests passed but the command still failed (e.g. a
+		// package that did not compile); do not award full credit.
+		credit = float64(passed) / float64(total+1)
+	}
+	detail := fmt.Sprintf("%d/%d tests passed (%s)", passed, total, framework)
+	if credit < 1 {
+		detail += ": " + outputTail(combined, 300)
+	}
+	return credit, detail, nil
+}
+
+// CodeFiles maps the fenced code blocks in output to workspace files.
+// Filenames come from the fence info string ("```go main.go",
+// "```python title=app.py") or a leading "// file: path" comment; unnamed
+// blocks go to spec.DefaultFile (concatenated) or solution[_N].<ext>.
+func CodeFiles(output string, spec ExecSpec) (map[string]string, error) {
+	files := map[string]string{}
+	unnamed := []CodeBlock{}
+
+	for _, block := range FencedBlocks(output) {
+		if !languageAllowed(block.Lang, spec.Languages) {
+			continue
+		}
+		name := blockFilename(block)
+		if name == "" {
+			unnamed = append(unnamed, block)
+			continue
+		}
+		if err := checkRelativePath(name); err != nil {
+			return nil, err
+		}
+		files[name] = block.Body + "\n"
+	}
+
+	if len(unnamed) == 0 {
+		return files, nil
+	}
+
+	if defaultFile := strings.TrimSpace(spec.DefaultFile); defaultFile != "" {
+		if err := checkRelativePath(defaultFile); err != nil {
+			return nil, err
+		}
+		bodies := make([]string, 0, len(unnamed))
+		for _, block := range unnamed {
+			bodies = append(bodies, block.Body)
+		}
+		files[defaultFile] = strings.Join(bodies, "\n\n") + "\n"
+		return files, nil
+	}
+
+	for i, block := range unnamed {
+		name := "solution" + extensionFor(block.Lang)
+		if i > 0 {
+			name = fmt.Sprintf("solution_%d%s", i+1, extensionFor(block.Lang))
+		}
+		files[name] = block.Body + "\n"
+	}
+	return files, nil
+}
+
+var fileCommentPattern = regexp.MustCompile(`^\s*(?://|#|--|/\*)\s*(?:file|filename|path)\s*:\s*(\S+)`)
+
+func blockFilename(block CodeBlock) string {
+	fields := strings.Fields(block.Info)
+	for i, field := range fields {
+		for _, prefix := range []string{"title=", "file=", "filename="} {
+			if strings.HasPrefix(field, prefix) {
+				return strings.Trim(strings.TrimPrefix(field, prefix), `"'`)
+			}
+		}
+		if i > 0 && looksLikePath(field) {
+			return field
+		}
+	}
+	if len(fields) == 1 && looksLikePath(fields[0]) {
+		return fields[0]
+	}
+
+	firstLine, _, _ := strings.Cut(block.Body, "\n")
+	if m := fileCommentPattern.FindStringSubmatch(firstLine); m != nil {
+		return strings.TrimSuffix(m[1], "*/")
+	}
+	return ""
+}
+
+func looksLikePath(s string) bool {
+	return strings.Contains(s, ".") || strings.Contains(s, "/")
+}
+
+func checkRelativePath(name string) error {
+	clean := filepath.Clean(name)
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("code block path %q escapes the workspace", name)
+	}
+	return nil
+}
+
+func languageAllowed(lang string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(strings.TrimSpace(a), lang) {
+			return true
+		}
+	}
+	return false
+}
+
+func extensionFor(lang string) string {
+	switch lang {
+	case "go", "golang":
+		return ".go"
+	case "python", "py":
+		return ".py"
+	case "javascript", "js":
+		return ".js"
+	case "typescript", "ts":
+		return ".ts"
+	case "rust", "rs":
+		return ".rs"
+	case "bash", "sh", "shell":
+		return ".sh"
+	default:
+		return ".txt"
+	}
+}
+
+var (
+	goPassPattern     = regexp.MustCompile(`(?m)^\s*--- PASS: `)
+	goFailPattern     = regexp.MustCompile(`(?m)^\s*--- FAIL: `)
+	pytestPassPattern = regexp.MustCompile(`(\d+) passed`)
+	pytestFailPattern = regexp.MustCompile(`(\d+) (?:failed|errors?)`)
+	jestPattern       = regexp.MustCompile(`Tests:\s+(?:(\d+) failed, )?(?:\d+ skipped, )?(\d+) passed`)
+	tapPassPattern    = regexp.MustCompile(`(?m)^ok \d+`)
+	tapFailPattern    = regexp.MustCompile(`(?m)^not ok \d+`)
+)
+
+// ParseTestCounts extracts passed/failed test counts from test runner output.
+// It recognizes go test -v, jest, pytest and TAP output; ok is false when no
+// format matched.
+func ParseTestCounts(output string) (passed, failed int, framework string, ok bool) {
+	if p, f := len(goPassPattern.FindAllString(output, -1)), len(goFailPattern.FindAllString(output, -1)); p+f > 0 {
+		return p, f, "go test", true
+	}
+	if m := jestPattern.FindStringSubmatch(output); m != nil {
+		f, _ := strconv.Atoi(m[1])
+		p, _ := strconv.Atoi(m[2])
+		if p+f > 0 {
+			return p, f, "jest", true
+		}
+	}
+	if m := pytestPassPattern.FindStringSubmatch(output); m != nil {
+		p, _ := strconv.Atoi(m[1])
+		f := 0
+		for _, fm := range pytestFailPattern.FindAllStringSubmatch(output, -1) {
+			n, _ := strconv.Atoi(fm[1])
+			f += n
+		}
+		return p, f, "pytest", true
+	}
+	if m := pytestFailPattern.FindAllStringSubmatch(output, -1); m != nil && strings.Contains(output, "short test summary") {
+		f := 0
+		for _, fm := range m {
+			n, _ := strconv.Atoi(fm[1])
+			f += n
+		}
+		return 0, f, "pytest", true
+	}
+	if p, f := len(tapPassPattern.FindAllString(output, -1)), len(tapFailPattern.FindAllString(output, -1)); p+f > 0 {
+		return p, f, "tap", true
+	}
+	return 0, 0, "", false
+}
+
+func outputTail(output string, limit int) string {
+	trimmed := strings.TrimSpace(output)
+	if len(trimmed) <= limit {
+		return trimmed
+	}
+	return "..." + trimmed[len(trimmed)-limit:]
+}
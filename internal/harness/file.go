@@ -0,0 +1,56 @@
+package harness
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadSuite reads a standalone test suite from a .yaml, .yml or .json file.
+// Relative exec.workspace paths are resolved against the file's directory.
+func LoadSuite(path string) (*TestSuite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read test suite %q: %w", path, err)
+	}
+
+	var suite TestSuite
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&suite)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&suite)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode test suite %q: %w", path, err)
+	}
+
+	baseDir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("resolve test suite directory: %w", err)
+	}
+	suite.ResolveWorkspaces(baseDir)
+	return &suite, nil
+}
+
+// ResolveWorkspaces rewrites relative code_exec workspaces to be relative to
+// baseDir, so a suite can be run from any working directory.
+func (s *TestSuite) ResolveWorkspaces(baseDir string) {
+	for i := range s.TestCases {
+		exec := s.TestCases[i].Exec
+		if exec == nil || exec.Workspace == "" || filepath.IsAbs(exec.Workspace) {
+			continue
+		}
+		resolved := *exec
+		resolved.Workspace = filepath.Join(baseDir, exec.Workspace)
+		s.TestCases[i].Exec = &resolved
+	}
+}
@@ -0,0 +1,146 @@
+package harness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Perttulands/chiron/internal/provider"
+)
+
+// Verdict is a judge's decision on one rubric criterion.
+type Verdict struct {
+	Pass   bool   `json:"pass"`
+	Reason string `json:"reason"`
+}
+
+// Grader judges agent output against a natural-language criterion.
+type Grader interface {
+	Grade(ctx context.Context, criterion, output string) (Verdict, error)
+}
+
+const judgeSystemPrompt = `You are a strict, impartial grader of AI agent output.
+You receive one grading criterion and one agent output. Decide whether the output satisfies the criterion.
+Judge only the stated criterion; ignore style unless the criterion mentions it.
+
+Respond with only a JSON object:
+{"pass": true or false, "reason": "one or two sentences citing the output"}`
+
+// LLMGrader grades rubric criteria with a judge model behind a provider.
+type LLMGrader struct {
+	Provider  provider.Provider
+	Model     string // optional override; defaults to the provider's model
+	MaxTokens int
+}
+
+// NewLLMGrader creates a grader that uses p as the judge model.
+func NewLLMGrader(p provider.Provider) *LLMGrader {
+	return &LLMGrader{Provider: p, MaxTokens: 512}
+}
+
+// Grade asks the judge model whether output satisfies criterion.
+func (g *LLMGrader) Grade(ctx context.Context, criterion, output string) (Verdict, error) {
+	if g == nil || g.Provider == nil {
+		return Verdict{}, fmt.Errorf("judge provider is required")
+	}
+
+	model := strings.TrimSpace(g.Model)
+	if model == "" {
+		model = g.Provider.GetMetadata().Model
+	}
+	maxTokens := g.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = 512
+	}
+
+	input := fmt.Sprintf("CRITERION:\n%s\n\nAGENT OUTPUT:\n%s", strings.TrimSpace(criterion), output)
+	response, _, err := g.Provider.ExecuteAgent(ctx, provider.AgentDefinition{
+		SystemPrompt: judgeSystemPrompt,
+		Model:        model,
+		Temperature:  0,
+		MaxTokens:    maxTokens,
+	}, input)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("judge call: %w", err)
+	}
+
+	return parseVerdict(response)
+}
+
+// parseVerdict extracts the judge's JSON verdict, tolerating code fences and
+// surrounding prose.
+func parseVerdict(response string) (Verdict, error) {
+	payload := structuredPayload(response)
+	if start, end := strings.Index(payload, "{"), strings.LastIndex(payload, "}"); start >= 0 && end > start {
+		payload = payload[start : end+1]
+	}
+
+	var verdict Verdict
+	if err := json.Unmarshal([]byte(payload), &verdict); err != nil {
+		return Verdict{}, fmt.Errorf("parse judge verdict: %w", err)
+	}
+	verdict.Reason = strings.TrimSpace(verdict.Reason)
+	return verdict, nil
+}
+
+// FakeGrader is a deterministic Grader for tests and dry runs. Verdicts are
+// looked up by exact criterion; unmatched criteria get Default. Every call is
+// recorded; see Calls.
+type FakeGrader struct {
+	Verdicts map[string]Verdict
+	Default  Verdict
+	Err      error
+
+	mu    sync.Mutex
+	calls []FakeGraderCall
+}
+
+// FakeGraderCall records one Grade invocation.
+type FakeGraderCall struct {
+	Criterion string
+	Output    string
+}
+
+// Grade returns the configured verdict for criterion.
+func (f *FakeGrader) Grade(_ context.Context, criterion, output string) (Verdict, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, FakeGraderCall{Criterion: criterion, Output: output})
+	if f.Err != nil {
+		return Verdict{}, f.Err
+	}
+	if v, ok := f.Verdicts[criterion]; ok {
+		return v, nil
+	}
+	return f.Default, nil
+}
+
+// Calls returns a copy of the Grade invocations so far, in call order.
+func (f *FakeGrader) Calls() []FakeGraderCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]FakeGraderCall(nil), f.calls...)
+}
+
+// checkRubric judges an llm_rubric case. A missing grader or a grader error
+// is returned rather than scored, since the output was never judged.
+func checkRubric(ctx context.Context, tc TestCase, output string, grader Grader) (float64, string, error) {
+	if strings.TrimSpace(tc.Expected) == "" {
+		return 0, "llm_rubric assertion requires a criterion in expected", nil
+	}
+	if grader == nil {
+		return 0, "", fmt.Errorf("llm_rubric assertion needs a grader; none configured")
+	}
+	verdict, err := grader.Grade(ctx, tc.Expected, output)
+	if err != nil {
+		return 0, "", err
+	}
+	if verdict.Pass {
+		return 1, fmt.Sprintf("rubric passed: %s", verdict.Reason), nil
+	}
+	return 0, fmt.Sprintf("rubric failed: %s", verdict.Reason), nil
+}
@@ -0,0 +1,104 @@
+package harness
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParseVerdict(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     Verdict
+		wantErr  bool
+	}{
+		{name: "bare json", response: `{"pass": true, "reason": "greets the user"}`, want: Verdict{Pass: true, Reason: "greets the user"}},
+		{name: "fenced", response: "```json\n{\"pass\": false, \"reason\": \" no greeting \"}\n```", want: Verdict{Reason: "no greeting"}},
+		{name: "surrounding prose", response: `Verdict: {"pass": true, "reason": "ok"} as requested.`, want: Verdict{Pass: true, Reason: "ok"}},
+		{name: "not json", response: "PASS", wantErr: true},
+		{name: "empty", response: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseVerdict(tt.response)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseVerdict() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseVerdict() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunSuiteRubric(t *testing.T) {
+	judgeDown := errors.New("judge unavailable")
+	suite := TestSuite{ID: "s", TestCases: []TestCase{
+		{ID: "greets", Type: TypeLLMRubric, Expected: "greets the user", Weight: 0.5},
+		{ID: "polite", Type: TypeLLMRubric, Expected: "is polite"},
+	}}
+
+	tests := []struct {
+		name       string
+		grader     Grader
+		fails      bool
+		wantErr    error // matched with errors.Is when set
+		wantTotal  float64
+		wantPassed int
+	}{
+		{
+			name: "credit per verdict",
+			grader: &FakeGrader{Verdicts: map[string]Verdict{
+				"greets the user": {Pass: true, Reason: "says hello"},
+				"is polite":       {Pass: false, Reason: "curt"},
+			}},
+			wantTotal:  0.5,
+			wantPassed: 1,
+		},
+		{name: "default verdict", grader: &FakeGrader{Default: Verdict{Pass: true}}, wantTotal: 1.5, wantPassed: 2},
+		{name: "judge error", grader: &FakeGrader{Err: judgeDown}, fails: true, wantErr: judgeDown},
+		{name: "nil grader", grader: nil, fails: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RunSuite(context.Background(), suite, "hello there", tt.grader)
+			if tt.fails {
+				if err == nil {
+					t.Fatalf("RunSuite() = %+v, want error", result)
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RunSuite() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RunSuite() error = %v", err)
+			}
+			if result.TotalScore != tt.wantTotal || result.MaxScore != 1.5 || result.Passed != tt.wantPassed {
+				t.Errorf("RunSuite() total %.2f/%.2f passed %d, want %.2f/1.50 passed %d",
+					result.TotalScore, result.MaxScore, result.Passed, tt.wantTotal, tt.wantPassed)
+			}
+		})
+	}
+}
+
+func TestRunSuiteRubricCalls(t *testing.T) {
+	grader := &FakeGrader{Default: Verdict{Pass: true}}
+	suite := TestSuite{TestCases: []TestCase{
+		{ID: "empty", Type: TypeLLMRubric},
+		{ID: "greets", Type: TypeLLMRubric, Expected: "greets the user"},
+	}}
+
+	result, err := RunSuite(context.Background(), suite, "hello", grader)
+	if err != nil {
+		t.Fatalf("RunSuite() error = %v", err)
+	}
+	if result.Results[0].Credit != 0 {
+		t.Errorf("case without criterion got credit %.2f, want 0", result.Results[0].Credit)
+	}
+	calls := grader.Calls()
+	if len(calls) != 1 || calls[0] != (FakeGraderCall{Criterion: "greets the user", Output: "hello"}) {
+		t.Errorf("grader calls = %+v, want one for the criterion", calls)
+	}
+}
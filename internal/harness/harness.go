@@ -1,27 +1,69 @@
 package harness
 
 import (
+	"context"
 	"fmt"
-	"regexp"
 	"strings"
 	"time"
 )
 
+// Test case types understood by the harness.
+const (
+	TypeContains    = "contains"
+	TypeIContains   = "icontains"
+	TypeNotContains = "not_contains"
+	TypeContainsAll = "contains_all"
+	TypeContainsAny = "contains_any"
+	TypeStartsWith  = "starts_with"
+	TypeRegex       = "regex"
+	TypeEquals      = "equals"
+	TypeWordCount   = "word_count"
+	TypeCharCount   = "char_count"
+	TypeJSONPath    = "json_path"
+	TypeNumeric     = "numeric"
+	TypeValidJSON   = "valid_json"
+	TypeValidYAML   = "valid_yaml"
+	TypeLevenshtein = "levenshtein"
+	TypeROUGE       = "rouge_l"
+	TypeLLMRubric   = "llm_rubric"
+	TypeCodeExec    = "code_exec"
+)
+
+// ValidTypes lists all recognized test case types.
+var ValidTypes = []string{
+	TypeContains, TypeIContains, TypeNotContains, TypeContainsAll, TypeContainsAny,
+	TypeStartsWith, TypeRegex, TypeEquals, TypeWordCount, TypeCharCount,
+	TypeJSONPath, TypeNumeric, TypeValidJSON, TypeValidYAML, TypeLevenshtein, TypeROUGE,
+	TypeLLMRubric, TypeCodeExec,
+}
+
+// DefaultSimilarityThreshold is the pass mark for similarity assertions
+// when a test case does not set its own threshold.
+const DefaultSimilarityThreshold = 0.8
+
 // TestCase defines one assertion against agent output.
 type TestCase struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Type        string `json:"type"` // "contains", "regex", "not_contains", "equals"
-	Expected    string `json:"expected"`
-	Weight      float64 `json:"weight"` // 0.0-1.0, default 1.0
-	Description string `json:"description,omitempty"`
+	ID          string    `json:"id" yaml:"id"`
+	Name        string    `json:"name" yaml:"name"`
+	Type        string    `json:"type" yaml:"type"`         // see ValidTypes
+	Expected    string    `json:"expected" yaml:"expected"` // value, pattern, or llm_rubric criterion
+	Weight      float64   `json:"weight" yaml:"weight"`     // 0.0-1.0, default 1.0
+	Description string    `json:"description,omitempty" yaml:"description,omitempty"`
+	Values      []string  `json:"values,omitempty" yaml:"values,omitempty"`           // contains_all, contains_any
+	IgnoreCase  bool      `json:"ignore_case,omitempty" yaml:"ignore_case,omitempty"` // string comparisons
+	Path        string    `json:"path,omitempty" yaml:"path,omitempty"`               // JSONPath for json_path and numeric
+	Min         *float64  `json:"min,omitempty" yaml:"min,omitempty"`                 // word_count, char_count lower bound
+	Max         *float64  `json:"max,omitempty" yaml:"max,omitempty"`                 // word_count, char_count upper bound
+	Tolerance   float64   `json:"tolerance,omitempty" yaml:"tolerance,omitempty"`     // numeric, json_path absolute tolerance
+	Threshold   float64   `json:"threshold,omitempty" yaml:"threshold,omitempty"`     // similarity pass mark (0.0-1.0)
+	Exec        *ExecSpec `json:"exec,omitempty" yaml:"exec,omitempty"`               // code_exec configuration
 }
 
 // TestSuite groups related test cases for evaluation.
 type TestSuite struct {
-	ID        string     `json:"id"`
-	Name      string     `json:"name"`
-	TestCases []TestCase `json:"test_cases"`
+	ID        string     `json:"id" yaml:"id"`
+	Name      string     `json:"name" yaml:"name"`
+	TestCases []TestCase `json:"test_cases" yaml:"test_cases"`
 }
 
 // TestResult captures the outcome of running one test case.
@@ -29,7 +71,8 @@ type TestResult struct {
 	TestCaseID string  `json:"test_case_id"`
 	TestName   string  `json:"test_name"`
 	Passed     bool    `json:"passed"`
-	Score      float64 `json:"score"` // weighted score: weight * (1.0 if passed, 0.0 otherwise)
+	Credit     float64 `json:"credit"` // 0.0-1.0 partial credit
+	Score      float64 `json:"score"`  // weighted score: weight * credit
 	Detail     string  `json:"detail,omitempty"`
 }
 
@@ -48,7 +91,11 @@ type SuiteResult struct {
 }
 
 // RunSuite executes all test cases in a suite against the given output.
-func RunSuite(suite TestSuite, output string) SuiteResult {
+// grader judges llm_rubric cases; it may be nil only when the suite has none
+// (see NeedsGrader). If a case cannot be judged (no grader, or the grader
+// or the code_exec sandbox fails), RunSuite stops and returns the error:
+// that is not the agent's fault, so the result must not be scored.
+func RunSuite(ctx context.Context, suite TestSuite, output string, grader Grader) (SuiteResult, error) {
 	start := time.Now()
 	results := make([]TestResult, 0, len(suite.TestCases))
 
@@ -56,14 +103,13 @@ func RunSuite(suite TestSuite, output string) SuiteResult {
 	var passed, failed int
 
 	for _, tc := range suite.TestCases {
-		result := runTestCase(tc, output)
+		result, err := runTestCase(ctx, tc, output, grader)
+		if err != nil {
+			return SuiteResult{SuiteID: suite.ID, SuiteName: suite.Name, Results: results}, fmt.Errorf("grade test case %q: %w", tc.ID, err)
+		}
 		results = append(results, result)
 		totalScore += result.Score
-		weight := tc.Weight
-		if weight <= 0 {
-			weight = 1.0
-		}
-		maxScore += weight
+		maxScore += effectiveWeight(tc)
 		if result.Passed {
 			passed++
 		} else {
@@ -87,64 +133,62 @@ func RunSuite(suite TestSuite, output string) SuiteResult {
 		Failed:     failed,
 		DurationMS: int(time.Since(start).Milliseconds()),
 		RunAt:      time.Now().UTC().Format(time.RFC3339),
-	}
+	}, nil
 }
 
-func runTestCase(tc TestCase, output string) TestResult {
-	weight := tc.Weight
-	if weight <= 0 {
-		weight = 1.0
+func runTestCase(ctx context.Context, tc TestCase, output string, grader Grader) (TestResult, error) {
+	var credit float64
+	var detail string
+	var err error
+	switch strings.ToLower(strings.TrimSpace(tc.Type)) {
+	case TypeLLMRubric:
+		credit, detail, err = checkRubric(ctx, tc, output, grader)
+	case TypeCodeExec:
+		credit, detail, err = checkCodeExec(ctx, tc, output)
+	default:
+		credit, detail = evaluate(tc, output)
 	}
-
-	pass, detail := evaluate(tc.Type, tc.Expected, output)
-
-	score := 0.0
-	if pass {
-		score = weight
+	if err != nil {
+		return TestResult{}, err
 	}
+	credit = clampCredit(credit)
 
 	return TestResult{
 		TestCaseID: tc.ID,
 		TestName:   tc.Name,
-		Passed:     pass,
-		Score:      score,
+		Passed:     credit >= 1.0,
+		Credit:     credit,
+		Score:      effectiveWeight(tc) * credit,
 		Detail:     detail,
-	}
+	}, nil
 }
 
-func evaluate(checkType, expected, output string) (bool, string) {
-	switch strings.ToLower(strings.TrimSpace(checkType)) {
-	case "contains":
-		if strings.Contains(output, expected) {
-			return true, fmt.Sprintf("output contains %q", expected)
-		}
-		return false, fmt.Sprintf("output does not contain %q", expected)
-
-	case "not_contains":
-		if !strings.Contains(output, expected) {
-			return true, fmt.Sprintf("output does not contain %q (as expected)", expected)
-		}
-		return false, fmt.Sprintf("output contains %q (unexpected)", expected)
+func effectiveWeight(tc TestCase) float64 {
+	if tc.Weight <= 0 {
+		return 1.0
+	}
+	return tc.Weight
+}
 
-	case "regex":
-		re, err := regexp.Compile(expected)
-		if err != nil {
-			return false, fmt.Sprintf("invalid regex %q: %v", expected, err)
-		}
-		if re.MatchString(output) {
-			return true, fmt.Sprintf("output matches regex %q", expected)
-		}
-		return false, fmt.Sprintf("output does not match regex %q", expected)
+func clampCredit(credit float64) float64 {
+	if credit < 0 {
+		return 0
+	}
+	if credit > 1 {
+		return 1
+	}
+	return credit
+}
 
-	case "equals":
-		if strings.TrimSpace(output) == strings.TrimSpace(expected) {
-			return true, "output equals expected"
+// IsValidType reports whether t names a known test case type.
+func IsValidType(t string) bool {
+	normalized := strings.ToLower(strings.TrimSpace(t))
+	for _, valid := range ValidTypes {
+		if normalized == valid {
+			return true
 		}
-		return false, "output does not equal expected"
-
-	default:
-		return false, fmt.Sprintf("unknown test type %q", checkType)
 	}
+	return false
 }
 
 // NormalizedScore returns the suite score as 1-10 scale for integration with evaluation.
@@ -162,3 +206,37 @@ func (sr SuiteResult) NormalizedScore() int {
 	}
 	return score
 }
+
+// NeedsGrader reports whether any test case requires a judge model.
+func (s TestSuite) NeedsGrader() bool {
+	for _, tc := range s.TestCases {
+		if strings.EqualFold(strings.TrimSpace(tc.Type), TypeLLMRubric) {
+			return true
+		}
+	}
+	return false
+}
+
+// FailedResults returns the results that did not earn full credit.
+func (sr SuiteResult) FailedResults() []TestResult {
+	failed := make([]TestResult, 0, sr.Failed)
+	for _, r := range sr.Results {
+		if !r.Passed {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+func describeBounds(min, max *float64) string {
+	switch {
+	case min != nil && max != nil:
+		return fmt.Sprintf("[%g, %g]", *min, *max)
+	case min != nil:
+		return fmt.Sprintf(">= %g", *min)
+	case max != nil:
+		return fmt.Sprintf("<= %g", *max)
+	default:
+		return "(unbounded)"
+	}
+}
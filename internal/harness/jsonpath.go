@@ -0,0 +1,111 @@
+package harness
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// lookupJSONPath resolves a JSONPath subset against a decoded JSON document.
+// Supported syntax: $, .key, ['key'], ["key"] and [index] (negative indexes
+// count from the end). Wildcards and filters are intentionally unsupported.
+func lookupJSONPath(doc any, path string) (any, error) {
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := doc
+	for _, step := range steps {
+		switch node := current.(type) {
+		case map[string]any:
+			if step.isIndex {
+				return nil, fmt.Errorf("cannot index object with [%d]", step.index)
+			}
+			value, ok := node[step.key]
+			if !ok {
+				return nil, fmt.Errorf("key %q not found", step.key)
+			}
+			current = value
+		case []any:
+			if !step.isIndex {
+				return nil, fmt.Errorf("cannot read key %q from array", step.key)
+			}
+			idx := step.index
+			if idx < 0 {
+				idx += len(node)
+			}
+			if idx < 0 || idx >= len(node) {
+				return nil, fmt.Errorf("index %d out of range (len %d)", step.index, len(node))
+			}
+			current = node[idx]
+		default:
+			return nil, fmt.Errorf("cannot descend into %T", current)
+		}
+	}
+	return current, nil
+}
+
+type jsonPathStep struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// ValidateJSONPath reports whether path uses the supported JSONPath subset.
+func ValidateJSONPath(path string) error {
+	_, err := parseJSONPath(path)
+	return err
+}
+
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	p := strings.TrimSpace(path)
+	if p == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+	if strings.HasPrefix(p, "$") {
+		p = p[1:]
+	} else if !strings.HasPrefix(p, ".") && !strings.HasPrefix(p, "[") {
+		p = "." + p
+	}
+
+	steps := []jsonPathStep{}
+	for len(p) > 0 {
+		switch p[0] {
+		case '.':
+			p = p[1:]
+			end := strings.IndexAny(p, ".[")
+			if end < 0 {
+				end = len(p)
+			}
+			key := p[:end]
+			if key == "" {
+				return nil, fmt.Errorf("empty key in path %q", path)
+			}
+			if key == "*" {
+				return nil, fmt.Errorf("wildcards are not supported in path %q", path)
+			}
+			steps = append(steps, jsonPathStep{key: key})
+			p = p[end:]
+		case '[':
+			end := strings.Index(p, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated bracket in path %q", path)
+			}
+			inner := strings.TrimSpace(p[1:end])
+			p = p[end+1:]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				steps = append(steps, jsonPathStep{key: inner[1 : len(inner)-1]})
+				continue
+			}
+			idx, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, fmt.Errorf("unsupported selector [%s] in path %q", inner, path)
+			}
+			steps = append(steps, jsonPathStep{index: idx, isIndex: true})
+		default:
+			return nil, fmt.Errorf("unexpected %q in path %q", p[0], path)
+		}
+	}
+	return steps, nil
+}
@@ -0,0 +1,84 @@
+package harness
+
+import (
+	"strings"
+	"unicode"
+)
+
+// levenshteinSimilarity returns 1 - editDistance/maxLen over runes, so
+// identical strings score 1.0 and fully different strings approach 0.0.
+func levenshteinSimilarity(a, b string, ignoreCase bool) float64 {
+	if ignoreCase {
+		a, b = strings.ToLower(a), strings.ToLower(b)
+	}
+	ra, rb := []rune(a), []rune(b)
+	maxLen := len(ra)
+	if len(rb) > maxLen {
+		maxLen = len(rb)
+	}
+	if maxLen == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(maxLen)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// rougeL returns the ROUGE-L F1 score between candidate and reference,
+// computed on lowercased word tokens via longest common subsequence.
+func rougeL(candidate, reference string) float64 {
+	c, r := rougeTokens(candidate), rougeTokens(reference)
+	if len(c) == 0 || len(r) == 0 {
+		if len(c) == len(r) {
+			return 1
+		}
+		return 0
+	}
+	lcs := lcsLength(c, r)
+	if lcs == 0 {
+		return 0
+	}
+	precision := float64(lcs) / float64(len(c))
+	recall := float64(lcs) / float64(len(r))
+	return 2 * precision * recall / (precision + recall)
+}
+
+func rougeTokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				curr[j] = prev[j-1] + 1
+			} else {
+				curr[j] = max(prev[j], curr[j-1])
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
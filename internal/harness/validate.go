@@ -0,0 +1,96 @@
+package harness
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Validate checks that every test case in the suite is well formed: known
+// type, compilable regex, parseable JSONPath and numbers, and the fields each
+// type requires. All problems are reported together.
+func (s TestSuite) Validate() error {
+	var errs []error
+	seen := map[string]bool{}
+	for i, tc := range s.TestCases {
+		label := fmt.Sprintf("test case %d", i+1)
+		if strings.TrimSpace(tc.ID) != "" {
+			label = fmt.Sprintf("test case %q", tc.ID)
+			if seen[tc.ID] {
+				errs = append(errs, fmt.Errorf("%s: duplicate id", label))
+			}
+			seen[tc.ID] = true
+		}
+		if err := tc.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", label, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks that a single test case is well formed.
+func (tc TestCase) Validate() error {
+	if !IsValidType(tc.Type) {
+		return fmt.Errorf("unknown type %q; must be one of: %s", tc.Type, strings.Join(ValidTypes, ", "))
+	}
+	if tc.Weight < 0 {
+		return fmt.Errorf("weight must be >= 0")
+	}
+
+	switch strings.ToLower(strings.TrimSpace(tc.Type)) {
+	case TypeContains, TypeIContains, TypeNotContains, TypeStartsWith, TypeLLMRubric:
+		if tc.Expected == "" {
+			return fmt.Errorf("%s requires expected", tc.Type)
+		}
+	case TypeContainsAll, TypeContainsAny:
+		if len(assertionValues(tc)) == 0 {
+			return fmt.Errorf("%s requires values", tc.Type)
+		}
+	case TypeRegex:
+		if _, err := regexp.Compile(tc.Expected); err != nil {
+			return fmt.Errorf("invalid regex %q: %w", tc.Expected, err)
+		}
+	case TypeWordCount, TypeCharCount:
+		if tc.Min == nil && tc.Max == nil {
+			return fmt.Errorf("%s requires min or max", tc.Type)
+		}
+		if tc.Min != nil && tc.Max != nil && *tc.Min > *tc.Max {
+			return fmt.Errorf("min %g is greater than max %g", *tc.Min, *tc.Max)
+		}
+	case TypeJSONPath:
+		if err := ValidateJSONPath(tc.Path); err != nil {
+			return fmt.Errorf("invalid path %q: %w", tc.Path, err)
+		}
+	case TypeNumeric:
+		if _, err := strconv.ParseFloat(strings.TrimSpace(tc.Expected), 64); err != nil {
+			return fmt.Errorf("numeric expected %q is not a number", tc.Expected)
+		}
+		if strings.TrimSpace(tc.Path) != "" {
+			if err := ValidateJSONPath(tc.Path); err != nil {
+				return fmt.Errorf("invalid path %q: %w", tc.Path, err)
+			}
+		}
+		if tc.Tolerance < 0 {
+			return fmt.Errorf("tolerance must be >= 0")
+		}
+	case TypeLevenshtein, TypeROUGE:
+		if tc.Threshold < 0 || tc.Threshold > 1 {
+			return fmt.Errorf("threshold must be between 0 and 1")
+		}
+	case TypeCodeExec:
+		if tc.Exec == nil || strings.TrimSpace(tc.Exec.Command) == "" {
+			return fmt.Errorf("code_exec requires exec.command")
+		}
+		if s := strings.TrimSpace(tc.Exec.Sandbox); s != "" && s != "bwrap" {
+			return fmt.Errorf("exec.sandbox must be \"bwrap\"; unsandboxed runs need chiron --unsandboxed-exec")
+		}
+		for path := range tc.Exec.Files {
+			if err := checkRelativePath(path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/Perttulands/chiron/internal/mutation"
+	"github.com/Perttulands/chiron/internal/selection"
 	"github.com/Perttulands/chiron/internal/tournament"
 	"github.com/Perttulands/chiron/internal/training"
 )
@@ -22,6 +24,8 @@ type TrainedPrompt struct {
 	Generation   int     `json:"generation"`
 	LineageID    string  `json:"lineage_id"`
 	TrainedAt    string  `json:"trained_at"`
+	// Objective values, for prompts exported from the Pareto front.
+	Objectives map[string]float64 `json:"objectives,omitempty"`
 }
 
 // TrainingReport summarizes a training run for the learning loop.
@@ -30,11 +34,36 @@ type TrainingReport struct {
 	Generations    int             `json:"generations"`
 	BestScore      float64         `json:"best_score"`
 	TrainedPrompts []TrainedPrompt `json:"trained_prompts"`
-	CreatedAt      string          `json:"created_at"`
+	// Non-dominated contestants of the final generation and the objectives
+	// they trade off; see selection.ParetoFront.
+	Objectives  []string                `json:"objectives,omitempty"`
+	ParetoFront []selection.ParetoPoint `json:"pareto_front,omitempty"`
+	// Operator effectiveness: mean score gain of each mutation operator's
+	// children over their parents, most effective first.
+	OperatorSchedule string                     `json:"operator_schedule,omitempty"`
+	Operators        []mutation.OperatorSummary `json:"operators,omitempty"`
+	CreatedAt        string                     `json:"created_at"`
 }
 
-// ExportReport generates a training report from a completed loop.
+// Which final-generation contestants a report exports as trained prompts.
+const (
+	ExportWinners     = "winners"      // the contestants selection kept
+	ExportParetoFront = "pareto_front" // every contestant on the Pareto front
+)
+
+// ExportOptions controls ExportReportWith.
+type ExportOptions struct {
+	Prompts string // ExportWinners (default) or ExportParetoFront
+}
+
+// ExportReport generates a training report from a completed loop, exporting
+// the final generation's winners.
 func ExportReport(loop *training.Loop) (*TrainingReport, error) {
+	return ExportReportWith(loop, ExportOptions{})
+}
+
+// ExportReportWith generates a training report from a completed loop.
+func ExportReportWith(loop *training.Loop, opts ExportOptions) (*TrainingReport, error) {
 	if loop == nil {
 		return nil, fmt.Errorf("loop is nil")
 	}
@@ -44,37 +73,64 @@ func ExportReport(loop *training.Loop) (*TrainingReport, error) {
 	if len(loop.Generations) == 0 {
 		return nil, fmt.Errorf("loop has no generations")
 	}
-
-	// Extract winner prompts from the final generation standings
 	lastGen := loop.Generations[len(loop.Generations)-1]
-	prompts := make([]TrainedPrompt, 0, len(lastGen.Winners))
 
-	for _, winner := range lastGen.Winners {
-		contestant := findContestant(loop.Contestants, winner.ContestantID)
+	// Pick the exported standings from the final generation
+	exported := lastGen.Winners
+	objectives := map[string]map[string]float64{}
+	switch opts.Prompts {
+	case "", ExportWinners:
+	case ExportParetoFront:
+		standings := map[string]tournament.Standing{}
+		for _, s := range lastGen.Tournament.Standings {
+			standings[s.ContestantID] = s
+		}
+		exported = make([]tournament.Standing, 0, len(lastGen.ParetoFront))
+		for _, p := range lastGen.ParetoFront {
+			if s, ok := standings[p.ContestantID]; ok {
+				exported = append(exported, s)
+				objectives[p.ContestantID] = p.Objectives
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown export %q; choose from: %s, %s", opts.Prompts, ExportWinners, ExportParetoFront)
+	}
+
+	prompts := make([]TrainedPrompt, 0, len(exported))
+	for _, standing := range exported {
+		contestant := findContestant(loop.Contestants, standing.ContestantID)
 		if contestant == nil {
 			continue
 		}
 
 		prompts = append(prompts, TrainedPrompt{
-			PromptID:     fmt.Sprintf("%s_%s", loop.ID, winner.ContestantID),
+			PromptID:     fmt.Sprintf("%s_%s", loop.ID, standing.ContestantID),
 			SystemPrompt: contestant.Agent.Definition.SystemPrompt,
 			Model:        contestant.Agent.Definition.Model,
-			AvgScore:     winner.AvgScore,
-			BoutsPlayed:  winner.BoutsPlayed,
-			BoutsWon:     winner.BoutsWon,
+			AvgScore:     standing.AvgScore,
+			BoutsPlayed:  standing.BoutsPlayed,
+			BoutsWon:     standing.BoutsWon,
 			Generation:   lastGen.Number,
-			LineageID:    winner.LineageID,
+			LineageID:    standing.LineageID,
 			TrainedAt:    time.Now().UTC().Format(time.RFC3339),
+			Objectives:   objectives[standing.ContestantID],
 		})
 	}
 
-	return &TrainingReport{
+	report := &TrainingReport{
 		LoopID:         loop.ID,
 		Generations:    len(loop.Generations),
 		BestScore:      loop.BestScore,
 		TrainedPrompts: prompts,
+		Objectives:     lastGen.Objectives,
+		ParetoFront:    lastGen.ParetoFront,
 		CreatedAt:      time.Now().UTC().Format(time.RFC3339),
-	}, nil
+	}
+	if loop.Operators != nil {
+		report.OperatorSchedule = loop.Operators.Strategy
+		report.Operators = loop.Operators.Summary()
+	}
+	return report, nil
 }
 
 // WriteReport saves a training report as JSON to disk.
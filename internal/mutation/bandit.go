@@ -0,0 +1,186 @@
+package mutation
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// Operator scheduling strategies.
+const (
+	ScheduleRandom   = "random"   // uniform over the arms
+	ScheduleUCB1     = "ucb1"     // mean gain plus an exploration bonus
+	ScheduleThompson = "thompson" // sample each arm's mean gain from a normal posterior
+)
+
+// ValidSchedules lists the operator scheduling strategies.
+var ValidSchedules = []string{ScheduleRandom, ScheduleUCB1, ScheduleThompson}
+
+// DefaultExploration is the UCB1 exploration constant. Rewards are score
+// gains in composite points, so it is on that scale rather than [0, 1].
+var DefaultExploration = math.Sqrt2
+
+// Bandit learns which mutation operators produce children that beat their
+// parents. Each operator is an arm; its reward is the child's score gain
+// over the parent. Arms that were never tried are chosen first.
+type Bandit struct {
+	Strategy    string    `json:"strategy"`
+	Exploration float64   `json:"exploration,omitempty"` // UCB1 constant; default DefaultExploration
+	Arms        []ArmStat `json:"arms"`
+}
+
+// ArmStat accumulates the rewards observed for one operator.
+type ArmStat struct {
+	Operator     string  `json:"operator"`
+	Pulls        int     `json:"pulls"`        // children scored
+	TotalGain    float64 `json:"total_gain"`   // sum of child-minus-parent scores
+	SumSquares   float64 `json:"sum_squares"`  // sum of squared gains
+	Improvements int     `json:"improvements"` // children that scored above their parent
+}
+
+// MeanGain is the average score gain of the operator's children.
+func (a ArmStat) MeanGain() float64 {
+	if a.Pulls == 0 {
+		return 0
+	}
+	return a.TotalGain / float64(a.Pulls)
+}
+
+// StdDev is the sample standard deviation of the gains, or 0 below two pulls.
+func (a ArmStat) StdDev() float64 {
+	if a.Pulls < 2 {
+		return 0
+	}
+	mean := a.MeanGain()
+	variance := (a.SumSquares - float64(a.Pulls)*mean*mean) / float64(a.Pulls-1)
+	return math.Sqrt(math.Max(variance, 0))
+}
+
+// NewBandit creates a bandit over operators (default AllOperators).
+func NewBandit(strategy string, operators []string) (*Bandit, error) {
+	strategy = strings.ToLower(strings.TrimSpace(strategy))
+	if strategy == "" {
+		strategy = ScheduleRandom
+	}
+	valid := false
+	for _, s := range ValidSchedules {
+		valid = valid || s == strategy
+	}
+	if !valid {
+		return nil, fmt.Errorf("unknown operator schedule %q; choose from: %s", strategy, strings.Join(ValidSchedules, ", "))
+	}
+	if len(operators) == 0 {
+		operators = AllOperators
+	}
+
+	b := &Bandit{Strategy: strategy, Arms: make([]ArmStat, 0, len(operators))}
+	seen := map[string]bool{}
+	for _, name := range operators {
+		if _, err := NewOperator(name); err != nil {
+			return nil, err
+		}
+		if !seen[name] {
+			seen[name] = true
+			b.Arms = append(b.Arms, ArmStat{Operator: name})
+		}
+	}
+	return b, nil
+}
+
+// Choose picks the operator for the next mutation.
+func (b *Bandit) Choose(rng *rand.Rand) string {
+	if len(b.Arms) == 0 {
+		return ""
+	}
+	rng = orNewRand(rng)
+	if b.Strategy == ScheduleRandom {
+		return b.Arms[rng.Intn(len(b.Arms))].Operator
+	}
+
+	total := 0
+	for _, arm := range b.Arms {
+		if arm.Pulls == 0 {
+			return arm.Operator
+		}
+		total += arm.Pulls
+	}
+
+	best, bestValue := 0, math.Inf(-1)
+	for i, arm := range b.Arms {
+		var value float64
+		switch b.Strategy {
+		case ScheduleUCB1:
+			c := b.Exploration
+			if c == 0 {
+				c = DefaultExploration
+			}
+			value = arm.MeanGain() + c*math.Sqrt(math.Log(float64(total))/float64(arm.Pulls))
+		case ScheduleThompson:
+			// One point of spread until an arm has enough pulls to estimate it.
+			sd := arm.StdDev()
+			if sd == 0 {
+				sd = 1
+			}
+			value = arm.MeanGain() + rng.NormFloat64()*sd/math.Sqrt(float64(arm.Pulls))
+		}
+		if value > bestValue {
+			best, bestValue = i, value
+		}
+	}
+	return b.Arms[best].Operator
+}
+
+// Record credits operator with a child whose score changed by gain relative
+// to its parent. Operators that are not arms are ignored.
+func (b *Bandit) Record(operator string, gain float64) {
+	for i := range b.Arms {
+		if b.Arms[i].Operator != operator {
+			continue
+		}
+		arm := &b.Arms[i]
+		arm.Pulls++
+		arm.TotalGain += gain
+		arm.SumSquares += gain * gain
+		if gain > 0 {
+			arm.Improvements++
+		}
+		return
+	}
+}
+
+// OperatorSummary reports one operator's effectiveness.
+type OperatorSummary struct {
+	Operator        string  `json:"operator"`
+	Pulls           int     `json:"pulls"`
+	MeanGain        float64 `json:"mean_gain"`
+	StdDev          float64 `json:"std_dev"`
+	ImprovementRate float64 `json:"improvement_rate"` // share of children that beat their parent
+}
+
+// Summary reports every arm, most effective first.
+func (b *Bandit) Summary() []OperatorSummary {
+	out := make([]OperatorSummary, 0, len(b.Arms))
+	for _, arm := range b.Arms {
+		s := OperatorSummary{Operator: arm.Operator, Pulls: arm.Pulls, MeanGain: arm.MeanGain(), StdDev: arm.StdDev()}
+		if arm.Pulls > 0 {
+			s.ImprovementRate = float64(arm.Improvements) / float64(arm.Pulls)
+		}
+		out = append(out, s)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if (out[i].Pulls == 0) != (out[j].Pulls == 0) {
+			return out[j].Pulls == 0
+		}
+		return out[i].MeanGain > out[j].MeanGain
+	})
+	return out
+}
+
+func orNewRand(rng *rand.Rand) *rand.Rand {
+	if rng == nil {
+		return rand.New(rand.NewSource(42))
+	}
+	return rng
+}
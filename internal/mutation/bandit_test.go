@@ -0,0 +1,116 @@
+package mutation
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func newTestBandit(t *testing.T, strategy string) *Bandit {
+	t.Helper()
+	b, err := NewBandit(strategy, []string{OpRephrase, OpExpand, OpSimplify})
+	if err != nil {
+		t.Fatalf("NewBandit() error = %v", err)
+	}
+	return b
+}
+
+func TestNewBanditRejectsUnknown(t *testing.T) {
+	if _, err := NewBandit("greedy", nil); err == nil {
+		t.Error("NewBandit() accepted an unknown schedule")
+	}
+	if _, err := NewBandit(ScheduleUCB1, []string{"shuffle"}); err == nil {
+		t.Error("NewBandit() accepted an unknown operator")
+	}
+}
+
+func TestBanditChooseUntriedFirst(t *testing.T) {
+	for _, strategy := range []string{ScheduleUCB1, ScheduleThompson} {
+		t.Run(strategy, func(t *testing.T) {
+			b := newTestBandit(t, strategy)
+			b.Record(OpRephrase, 5)
+			b.Record(OpSimplify, 5)
+			if got := b.Choose(rand.New(rand.NewSource(1))); got != OpExpand {
+				t.Errorf("Choose() = %q, want the untried %q", got, OpExpand)
+			}
+		})
+	}
+}
+
+func TestBanditChooseUCB1(t *testing.T) {
+	b := newTestBandit(t, ScheduleUCB1)
+	for i := 0; i < 20; i++ {
+		b.Record(OpRephrase, -1)
+		b.Record(OpExpand, 2)
+		b.Record(OpSimplify, 0)
+	}
+	if got := b.Choose(nil); got != OpExpand {
+		t.Errorf("Choose() = %q, want the best mean %q", got, OpExpand)
+	}
+
+	// A rarely pulled arm's exploration bonus outweighs a small mean lead.
+	b = newTestBandit(t, ScheduleUCB1)
+	for i := 0; i < 50; i++ {
+		b.Record(OpRephrase, 0.5)
+		b.Record(OpExpand, 0.4)
+	}
+	b.Record(OpSimplify, 0)
+	if got := b.Choose(nil); got != OpSimplify {
+		t.Errorf("Choose() = %q, want the under-explored %q", got, OpSimplify)
+	}
+}
+
+func TestBanditChooseThompson(t *testing.T) {
+	b := newTestBandit(t, ScheduleThompson)
+	for i := 0; i < 30; i++ {
+		b.Record(OpRephrase, -2)
+		b.Record(OpExpand, 3)
+		b.Record(OpSimplify, -2)
+	}
+	rng := rand.New(rand.NewSource(9))
+	counts := map[string]int{}
+	for i := 0; i < 200; i++ {
+		counts[b.Choose(rng)]++
+	}
+	if counts[OpExpand] < 190 {
+		t.Errorf("Choose() picked %q %d of 200 times, want nearly always", OpExpand, counts[OpExpand])
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		first := b.Choose(rand.New(rand.NewSource(seed)))
+		if second := b.Choose(rand.New(rand.NewSource(seed))); first != second {
+			t.Fatalf("Choose() with seed %d gave %q then %q", seed, first, second)
+		}
+	}
+}
+
+func TestBanditChooseRandom(t *testing.T) {
+	b := newTestBandit(t, ScheduleRandom)
+	b.Record(OpExpand, 10)
+	rng := rand.New(rand.NewSource(4))
+	counts := map[string]int{}
+	for i := 0; i < 300; i++ {
+		counts[b.Choose(rng)]++
+	}
+	for _, op := range []string{OpRephrase, OpExpand, OpSimplify} {
+		if counts[op] < 60 {
+			t.Errorf("Choose() picked %q %d of 300 times, want about a third", op, counts[op])
+		}
+	}
+}
+
+func TestBanditRecord(t *testing.T) {
+	b := newTestBandit(t, ScheduleUCB1)
+	b.Record(OpExpand, 2)
+	b.Record(OpExpand, -1)
+	b.Record("unknown", 100)
+
+	arm := b.Arms[1]
+	if arm.Operator != OpExpand || arm.Pulls != 2 || arm.Improvements != 1 || arm.MeanGain() != 0.5 {
+		t.Errorf("expand arm = %+v mean %v, want 2 pulls, 1 improvement, mean 0.5", arm, arm.MeanGain())
+	}
+	for _, other := range []ArmStat{b.Arms[0], b.Arms[2]} {
+		if other.Pulls != 0 {
+			t.Errorf("arm %q has %d pulls, want 0", other.Operator, other.Pulls)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package mutation
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/Perttulands/chiron/internal/provider"
+	"github.com/Perttulands/chiron/internal/state"
+)
+
+// Defaults for few-shot example selection.
+const (
+	DefaultExampleMinScore   = 7.0
+	DefaultExampleCandidates = 20
+	DefaultMaxExamples       = 5
+)
+
+// ExampleOptions controls which artifacts become few-shot candidates.
+type ExampleOptions struct {
+	MinScore float64 // lowest artifact score (0-10) accepted; default DefaultExampleMinScore
+	Limit    int     // most candidates returned; default DefaultExampleCandidates
+}
+
+// ExampleCandidates returns the session's high-scoring artifacts as few-shot
+// examples, best first and one per distinct input. An artifact's score is
+// its composite score, or its reviewer evaluation when it has none; failed
+// and empty outputs are skipped.
+func ExampleCandidates(session state.Session, opts ExampleOptions) []state.FewShotExample {
+	minScore := opts.MinScore
+	if minScore == 0 {
+		minScore = DefaultExampleMinScore
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultExampleCandidates
+	}
+
+	best := map[string]state.FewShotExample{}
+	for _, lineage := range session.Lineages {
+		for _, artifact := range lineage.Artifacts {
+			if artifact.Error != "" || strings.TrimSpace(artifact.Output) == "" || strings.TrimSpace(artifact.Input) == "" {
+				continue
+			}
+			var score float64
+			switch {
+			case artifact.CompositeScore != nil:
+				score = artifact.CompositeScore.FinalScore
+			case artifact.Evaluation != nil:
+				score = float64(artifact.Evaluation.Score)
+			default:
+				continue
+			}
+			if score < minScore {
+				continue
+			}
+			if prev, ok := best[artifact.Input]; ok && (prev.Score > score || prev.Score == score && prev.ArtifactID < artifact.ID) {
+				continue
+			}
+			best[artifact.Input] = state.FewShotExample{
+				Input:      artifact.Input,
+				Output:     artifact.Output,
+				ArtifactID: artifact.ID,
+				Score:      score,
+			}
+		}
+	}
+
+	candidates := make([]state.FewShotExample, 0, len(best))
+	for _, ex := range best {
+		candidates = append(candidates, ex)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].ArtifactID < candidates[j].ArtifactID
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates
+}
+
+// ExampleAddOp adds one candidate the agent does not show yet. Candidates
+// usually come from ExampleCandidates.
+type ExampleAddOp struct {
+	Candidates []state.FewShotExample
+	Max        int // examples an agent may carry; default DefaultMaxExamples
+	Rand       *rand.Rand
+}
+
+func (ExampleAddOp) Name() string { return OpExampleAdd }
+func (o ExampleAddOp) Mutate(ctx context.Context, agent state.AgentDefinition, p provider.Provider) (Mutation, error) {
+	limit := o.Max
+	if limit <= 0 {
+		limit = DefaultMaxExamples
+	}
+	if len(agent.Examples) >= limit {
+		return Mutation{}, fmt.Errorf("%s: agent already has %d examples", OpExampleAdd, len(agent.Examples))
+	}
+	unused := unusedExamples(agent.Examples, o.Candidates)
+	if len(unused) == 0 {
+		return Mutation{}, fmt.Errorf("%s: no unused example candidates", OpExampleAdd)
+	}
+	added := unused[orNewRand(o.Rand).Intn(len(unused))]
+	examples := append(append([]state.FewShotExample(nil), agent.Examples...), added)
+	return exampleMutation(agent, examples, OpExampleAdd, "added "+describeExample(added)), nil
+}
+
+// ExampleRemoveOp drops one of the agent's examples.
+type ExampleRemoveOp struct {
+	Rand *rand.Rand
+}
+
+func (ExampleRemoveOp) Name() string { return OpExampleRemove }
+func (o ExampleRemoveOp) Mutate(ctx context.Context, agent state.AgentDefinition, p provider.Provider) (Mutation, error) {
+	if len(agent.Examples) == 0 {
+		return Mutation{}, fmt.Errorf("%s: agent has no examples", OpExampleRemove)
+	}
+	i := orNewRand(o.Rand).Intn(len(agent.Examples))
+	removed := agent.Examples[i]
+	examples := append(append([]state.FewShotExample(nil), agent.Examples[:i]...), agent.Examples[i+1:]...)
+	return exampleMutation(agent, examples, OpExampleRemove, "removed "+describeExample(removed)), nil
+}
+
+// ExampleSwapOp replaces one of the agent's examples with an unused
+// candidate.
+type ExampleSwapOp struct {
+	Candidates []state.FewShotExample
+	Rand       *rand.Rand
+}
+
+func (ExampleSwapOp) Name() string { return OpExampleSwap }
+func (o ExampleSwapOp) Mutate(ctx context.Context, agent state.AgentDefinition, p provider.Provider) (Mutation, error) {
+	if len(agent.Examples) == 0 {
+		return Mutation{}, fmt.Errorf("%s: agent has no examples", OpExampleSwap)
+	}
+	unused := unusedExamples(agent.Examples, o.Candidates)
+	if len(unused) == 0 {
+		return Mutation{}, fmt.Errorf("%s: no unused example candidates", OpExampleSwap)
+	}
+	rng := orNewRand(o.Rand)
+	i := rng.Intn(len(agent.Examples))
+	added := unused[rng.Intn(len(unused))]
+	examples := append([]state.FewShotExample(nil), agent.Examples...)
+	removed := examples[i]
+	examples[i] = added
+	return exampleMutation(agent, examples, OpExampleSwap,
+		fmt.Sprintf("replaced %s with %s", describeExample(removed), describeExample(added))), nil
+}
+
+func exampleMutation(agent state.AgentDefinition, examples []state.FewShotExample, operator, reasoning string) Mutation {
+	def := agent
+	def.Examples = examples
+	return Mutation{
+		Definition: def,
+		Metadata:   state.GenerationMetadata{Operator: operator, Reasoning: reasoning},
+	}
+}
+
+func unusedExamples(current, candidates []state.FewShotExample) []state.FewShotExample {
+	used := map[string]bool{}
+	for _, ex := range current {
+		used[ex.Input] = true
+	}
+	unused := []state.FewShotExample{}
+	for _, ex := range candidates {
+		if !used[ex.Input] {
+			unused = append(unused, ex)
+		}
+	}
+	return unused
+}
+
+func describeExample(ex state.FewShotExample) string {
+	if ex.ArtifactID == "" {
+		return fmt.Sprintf("example %q", excerpt(ex.Input, 40))
+	}
+	return fmt.Sprintf("example from artifact %s (score %.1f)", ex.ArtifactID, ex.Score)
+}
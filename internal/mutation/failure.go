@@ -0,0 +1,198 @@
+package mutation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Perttulands/chiron/internal/provider"
+	"github.com/Perttulands/chiron/internal/state"
+	"github.com/Perttulands/chiron/internal/tournament"
+)
+
+// Defaults for collecting failure evidence.
+const (
+	DefaultLowScore    = 5.0 // composite score below which an output counts as a failure
+	DefaultMaxFailures = 10
+	maxOutputExcerpt   = 400
+)
+
+// FailureOptions tunes CollectFailures. The zero value uses the defaults.
+type FailureOptions struct {
+	LowScore    float64 // composite score below which an output is a failure
+	MaxFailures int     // most failures kept, lowest scores first
+}
+
+// CollectFailures gathers a contestant's failures from a generation's
+// rounds: every failing test result, and every bout that scored below
+// LowScore (including agent errors and timeouts). Infrastructure failures
+// are skipped. Failures repeated across repetitions are merged and counted.
+func CollectFailures(contestantID string, rounds []tournament.Round, opts FailureOptions) []state.MutationTarget {
+	lowScore := opts.LowScore
+	if lowScore <= 0 {
+		lowScore = DefaultLowScore
+	}
+	limit := opts.MaxFailures
+	if limit <= 0 {
+		limit = DefaultMaxFailures
+	}
+
+	index := map[[2]string]int{}
+	targets := []state.MutationTarget{}
+	add := func(t state.MutationTarget) {
+		key := [2]string{t.ChallengeID, t.TestCaseID}
+		if i, ok := index[key]; ok {
+			targets[i].Occurrences++
+			targets[i].Score = min(targets[i].Score, t.Score)
+			return
+		}
+		t.Occurrences = 1
+		index[key] = len(targets)
+		targets = append(targets, t)
+	}
+
+	for _, round := range rounds {
+		for _, bout := range round.Bouts {
+			if bout.ContestantID != contestantID || bout.InfraFailure() {
+				continue
+			}
+			score := bout.CompositeScore.FinalScore
+			for _, result := range bout.HarnessResult.Results {
+				if result.Passed {
+					continue
+				}
+				add(state.MutationTarget{
+					ChallengeID: bout.ChallengeID,
+					TestCaseID:  result.TestCaseID,
+					TestName:    result.TestName,
+					Detail:      result.Detail,
+					Score:       score,
+				})
+			}
+			if score < lowScore {
+				add(state.MutationTarget{
+					ChallengeID: bout.ChallengeID,
+					Detail:      bout.Error,
+					Output:      excerpt(bout.Output, maxOutputExcerpt),
+					Score:       score,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(targets, func(i, j int) bool { return targets[i].Score < targets[j].Score })
+	if len(targets) > limit {
+		targets = targets[:limit]
+	}
+	return targets
+}
+
+// FailureDrivenOp asks the provider to revise the prompt so that exactly the
+// collected failures stop happening. The Failures are recorded in the
+// mutation's MutationTargets; CheckTargets later reports which were fixed.
+type FailureDrivenOp struct {
+	Failures []state.MutationTarget
+}
+
+func (FailureDrivenOp) Name() string { return OpFailureDriven }
+func (f FailureDrivenOp) Mutate(ctx context.Context, agent state.AgentDefinition, p provider.Provider) (Mutation, error) {
+	if len(f.Failures) == 0 {
+		return Mutation{}, fmt.Errorf("%s: no failures to fix", OpFailureDriven)
+	}
+	m, err := mutateWithPrompt(ctx, OpFailureDriven, agent, p, fmt.Sprintf(
+		`Revise this system prompt so the agent stops making the specific failures listed below.
+Fix exactly these failures. Keep every instruction that is not related to them unchanged.
+
+Original prompt:
+%s
+
+Observed failures:
+%s
+
+Output JSON: {"system_prompt": "the revised prompt"%s}`, agent.SystemPrompt, SummarizeFailures(f.Failures), reasoningField))
+	if err == nil {
+		m.Metadata.MutationTargets = f.Failures
+	}
+	return m, err
+}
+
+// SummarizeFailures renders failures as a numbered list for a mutation prompt.
+func SummarizeFailures(failures []state.MutationTarget) string {
+	var b strings.Builder
+	for i, t := range failures {
+		if t.TestCaseID != "" {
+			name := t.TestName
+			if name == "" {
+				name = t.TestCaseID
+			}
+			fmt.Fprintf(&b, "%d. Challenge %s: failed test %q", i+1, t.ChallengeID, name)
+		} else {
+			fmt.Fprintf(&b, "%d. Challenge %s: low score %.1f/10", i+1, t.ChallengeID, t.Score)
+		}
+		if t.Occurrences > 1 {
+			fmt.Fprintf(&b, " (%d times)", t.Occurrences)
+		}
+		if t.Detail != "" {
+			fmt.Fprintf(&b, "\n   Detail: %s", t.Detail)
+		}
+		if t.Output != "" {
+			fmt.Fprintf(&b, "\n   Output: %s", t.Output)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// TargetCheck reports whether a mutation fixed one of its targets.
+type TargetCheck struct {
+	Target    state.MutationTarget `json:"target"`
+	Evaluated bool                 `json:"evaluated"` // the child played the target's challenge
+	Fixed     bool                 `json:"fixed"`     // the failure did not recur in any of its bouts
+}
+
+// CheckTargets compares a child's bouts in rounds against the targets its
+// mutation was meant to fix. A failing test is fixed when it passed in every
+// bout of that challenge; a low-score target is fixed when every bout scored
+// at least LowScore.
+func CheckTargets(contestantID string, targets []state.MutationTarget, rounds []tournament.Round, opts FailureOptions) []TargetCheck {
+	lowScore := opts.LowScore
+	if lowScore <= 0 {
+		lowScore = DefaultLowScore
+	}
+	checks := make([]TargetCheck, len(targets))
+	for i, t := range targets {
+		checks[i] = TargetCheck{Target: t, Fixed: true}
+		for _, round := range rounds {
+			for _, bout := range round.Bouts {
+				if bout.ContestantID != contestantID || bout.ChallengeID != t.ChallengeID || bout.InfraFailure() {
+					continue
+				}
+				checks[i].Evaluated = true
+				if t.TestCaseID == "" {
+					if bout.CompositeScore.FinalScore < lowScore {
+						checks[i].Fixed = false
+					}
+					continue
+				}
+				for _, result := range bout.HarnessResult.Results {
+					if result.TestCaseID == t.TestCaseID && !result.Passed {
+						checks[i].Fixed = false
+					}
+				}
+			}
+		}
+		if !checks[i].Evaluated {
+			checks[i].Fixed = false
+		}
+	}
+	return checks
+}
+
+func excerpt(text string, limit int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) <= limit {
+		return text
+	}
+	return text[:limit] + "..."
+}
@@ -0,0 +1,289 @@
+package mutation
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strings"
+
+	"github.com/Perttulands/chiron/internal/provider"
+	"github.com/Perttulands/chiron/internal/state"
+)
+
+// Standard prompt sections. Prompts may use any other section names too.
+const (
+	SectionPreamble     = "preamble" // text before the first heading
+	SectionRole         = "role"
+	SectionConstraints  = "constraints"
+	SectionOutputFormat = "output_format"
+	SectionExamples     = "examples"
+)
+
+// Crossover modes for SectionCrossoverOp.
+const (
+	CrossoverUniform  = "uniform"   // each section from either parent with equal probability
+	CrossoverOnePoint = "one_point" // sections before a cut from the parent, after it from the partner
+)
+
+var sectionHeading = regexp.MustCompile(`^#{1,3}\s+(.+?)\s*#*\s*$`)
+
+// ParseSections splits a system prompt into sections at markdown headings
+// ("# Role", "## Output format", ...). Names are the lower-cased heading with
+// spaces as underscores; text before the first heading becomes the preamble.
+func ParseSections(prompt string) []state.PromptSection {
+	sections := []state.PromptSection{}
+	current := state.PromptSection{Name: SectionPreamble}
+	var body []string
+	flush := func() {
+		current.Content = strings.TrimSpace(strings.Join(body, "\n"))
+		if current.Content != "" || current.Name != SectionPreamble {
+			sections = append(sections, current)
+		}
+	}
+	for _, line := range strings.Split(prompt, "\n") {
+		if m := sectionHeading.FindStringSubmatch(line); m != nil {
+			flush()
+			current = state.PromptSection{Name: sectionName(m[1])}
+			body = nil
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+	return sections
+}
+
+// RenderSections joins sections back into a system prompt, one "## Title"
+// heading per section.
+func RenderSections(sections []state.PromptSection) string {
+	parts := make([]string, 0, len(sections))
+	for _, s := range sections {
+		if s.Name == SectionPreamble {
+			parts = append(parts, s.Content)
+			continue
+		}
+		parts = append(parts, "## "+sectionTitle(s.Name)+"\n"+s.Content)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// Structure returns def with Sections parsed from its SystemPrompt, unless
+// it is already structured.
+func Structure(def state.AgentDefinition) state.AgentDefinition {
+	if len(def.Sections) > 0 {
+		return def
+	}
+	return withSections(def, ParseSections(def.SystemPrompt))
+}
+
+// LockSections structures def and locks the named sections.
+func LockSections(def state.AgentDefinition, names ...string) (state.AgentDefinition, error) {
+	def = Structure(def)
+	def.Sections = append([]state.PromptSection(nil), def.Sections...)
+	for _, name := range names {
+		i := sectionIndex(def.Sections, sectionName(name))
+		if i < 0 {
+			return state.AgentDefinition{}, fmt.Errorf("prompt has no section %q", name)
+		}
+		def.Sections[i].Locked = true
+	}
+	return def, nil
+}
+
+// SectionOp rewrites a single section of a structured prompt, leaving every
+// other section untouched. Section names the section to change; when empty
+// an unlocked section is picked with Rand.
+type SectionOp struct {
+	Section     string
+	Instruction string // what to do with the section; default improve it
+	Rand        *rand.Rand
+}
+
+func (SectionOp) Name() string { return OpSection }
+func (o SectionOp) Mutate(ctx context.Context, agent state.AgentDefinition, p provider.Provider) (Mutation, error) {
+	agent = Structure(agent)
+	target := -1
+	if o.Section != "" {
+		target = sectionIndex(agent.Sections, sectionName(o.Section))
+		if target < 0 {
+			return Mutation{}, fmt.Errorf("%s: prompt has no section %q", OpSection, o.Section)
+		}
+		if agent.Sections[target].Locked {
+			return Mutation{}, fmt.Errorf("%s: section %q is locked", OpSection, o.Section)
+		}
+	} else {
+		unlocked := []int{}
+		for i, s := range agent.Sections {
+			if !s.Locked {
+				unlocked = append(unlocked, i)
+			}
+		}
+		if len(unlocked) == 0 {
+			return Mutation{}, fmt.Errorf("%s: every section is locked", OpSection)
+		}
+		target = unlocked[orNewRand(o.Rand).Intn(len(unlocked))]
+	}
+
+	instruction := strings.TrimSpace(o.Instruction)
+	if instruction == "" {
+		instruction = "Improve this section: make it clearer and more effective for the agent's task."
+	}
+	name := agent.Sections[target].Name
+	prompt := fmt.Sprintf(
+		`Rewrite only the %q section of the system prompt below. %s
+Keep it consistent with the other sections, which will not change.
+
+Full prompt:
+%s
+
+Section to rewrite:
+%s
+
+Output JSON: {"section": "the new section content, without its heading"%s}`, name, instruction, agent.SystemPrompt, agent.Sections[target].Content, reasoningField)
+
+	content, reasoning, meta, err := generate(ctx, p, prompt, "section")
+	if err != nil {
+		return Mutation{}, err
+	}
+	sections := append([]state.PromptSection(nil), agent.Sections...)
+	sections[target].Content = content
+	return Mutation{
+		Definition: withSections(agent, sections),
+		Metadata:   provenance(p, meta, OpSection, prompt, reasoning),
+	}, nil
+}
+
+// SectionCrossoverOp recombines two structured prompts at section boundaries
+// without calling a model. Sections are matched by name; a section only one
+// parent has is inherited from it. Locked sections of the primary parent are
+// always kept.
+type SectionCrossoverOp struct {
+	Partner   state.AgentDefinition
+	PartnerID string
+	Mode      string // uniform (default) or one_point
+	Rand      *rand.Rand
+}
+
+func (SectionCrossoverOp) Name() string { return OpSectionCrossover }
+func (c SectionCrossoverOp) Mutate(ctx context.Context, agent state.AgentDefinition, p provider.Provider) (Mutation, error) {
+	mode := c.Mode
+	if mode == "" {
+		mode = CrossoverUniform
+	}
+	if mode != CrossoverUniform && mode != CrossoverOnePoint {
+		return Mutation{}, fmt.Errorf("%s: unknown mode %q; choose from: %s, %s", OpSectionCrossover, mode, CrossoverUniform, CrossoverOnePoint)
+	}
+	agent = Structure(agent)
+	partner := Structure(c.Partner).Sections
+	rng := orNewRand(c.Rand)
+
+	names := []string{}
+	for _, s := range agent.Sections {
+		names = append(names, s.Name)
+	}
+	for _, s := range partner {
+		if sectionIndex(agent.Sections, s.Name) < 0 {
+			names = append(names, s.Name)
+		}
+	}
+	cut := 0
+	if mode == CrossoverOnePoint && len(names) > 1 {
+		cut = 1 + rng.Intn(len(names)-1)
+	}
+
+	sections := make([]state.PromptSection, 0, len(names))
+	taken := []string{}
+	for i, name := range names {
+		own, partnerIdx := sectionIndex(agent.Sections, name), sectionIndex(partner, name)
+		fromPartner := false
+		switch {
+		case own < 0:
+			fromPartner = true
+		case partnerIdx < 0 || agent.Sections[own].Locked:
+		case mode == CrossoverUniform:
+			fromPartner = rng.Intn(2) == 1
+		default:
+			fromPartner = i >= cut
+		}
+		if fromPartner {
+			s := partner[partnerIdx]
+			s.Locked = false
+			sections = append(sections, s)
+			taken = append(taken, name)
+		} else {
+			sections = append(sections, agent.Sections[own])
+		}
+	}
+
+	m := Mutation{
+		Definition: withSections(agent, sections),
+		Metadata: state.GenerationMetadata{
+			Operator:  OpSectionCrossover,
+			Reasoning: fmt.Sprintf("%s crossover; sections from partner: %s", mode, orNone(taken)),
+		},
+	}
+	if p != nil {
+		info := p.GetMetadata()
+		m.Metadata.Provider, m.Metadata.Model = info.Provider, info.Model
+	}
+	if c.PartnerID != "" {
+		m.Metadata.ParentIDs = []string{c.PartnerID}
+	}
+	return m, nil
+}
+
+// keepLocked returns child with every locked parent section restored to the
+// parent's content, re-inserted at its parent position if the child lost it.
+func keepLocked(parent, child []state.PromptSection) []state.PromptSection {
+	out := append([]state.PromptSection(nil), child...)
+	for pi, s := range parent {
+		if !s.Locked {
+			continue
+		}
+		if i := sectionIndex(out, s.Name); i >= 0 {
+			out[i] = s
+			continue
+		}
+		at := pi
+		if at > len(out) {
+			at = len(out)
+		}
+		out = append(out[:at], append([]state.PromptSection{s}, out[at:]...)...)
+	}
+	return out
+}
+
+func withSections(def state.AgentDefinition, sections []state.PromptSection) state.AgentDefinition {
+	def.Sections = sections
+	def.SystemPrompt = RenderSections(sections)
+	return def
+}
+
+func sectionIndex(sections []state.PromptSection, name string) int {
+	for i, s := range sections {
+		if s.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func sectionName(heading string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.TrimSpace(heading))), "_")
+}
+
+func sectionTitle(name string) string {
+	title := strings.ReplaceAll(name, "_", " ")
+	if title == "" {
+		return title
+	}
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+func orNone(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/rand"
 	"strings"
+	"time"
 
 	"github.com/Perttulands/chiron/internal/provider"
 	"github.com/Perttulands/chiron/internal/state"
@@ -13,75 +14,107 @@ import (
 
 // Operator names for mutation strategies.
 const (
-	OpRephrase   = "rephrase"
-	OpExpand     = "expand"
-	OpSimplify   = "simplify"
-	OpCrossover  = "crossover"
-	OpTargeted   = "targeted"
+	OpRephrase         = "rephrase"
+	OpExpand           = "expand"
+	OpSimplify         = "simplify"
+	OpCrossover        = "crossover"
+	OpTargeted         = "targeted"
+	OpFailureDriven    = "failure_driven"
+	OpSection          = "section"
+	OpSectionCrossover = "section_crossover"
+	OpExampleAdd       = "example_add"
+	OpExampleRemove    = "example_remove"
+	OpExampleSwap      = "example_swap"
 )
 
 // AllOperators lists all available mutation operators.
-var AllOperators = []string{OpRephrase, OpExpand, OpSimplify, OpCrossover, OpTargeted}
+var AllOperators = []string{OpRephrase, OpExpand, OpSimplify, OpCrossover, OpTargeted, OpFailureDriven, OpSection, OpSectionCrossover, OpExampleAdd, OpExampleRemove, OpExampleSwap}
 
 // Operator mutates an agent definition to produce a variant.
 type Operator interface {
 	Name() string
-	Mutate(ctx context.Context, agent state.AgentDefinition, p provider.Provider) (state.AgentDefinition, error)
+	Mutate(ctx context.Context, agent state.AgentDefinition, p provider.Provider) (Mutation, error)
+}
+
+// Mutation is the variant an operator produced together with its
+// provenance: the operator, the prompt sent to the provider, the model's
+// reasoning and the provider's usage.
+type Mutation struct {
+	Definition state.AgentDefinition
+	Metadata   state.GenerationMetadata
+}
+
+// Child returns the next version of parent built from m. The child's
+// ParentIDs start with parent.ID, followed by any partner recorded by the
+// operator.
+func (m Mutation) Child(id string, parent state.Agent) state.Agent {
+	meta := m.Metadata
+	meta.ParentIDs = append([]string{parent.ID}, meta.ParentIDs...)
+	return state.Agent{
+		ID:                 id,
+		LineageID:          parent.LineageID,
+		Version:            parent.Version + 1,
+		Definition:         m.Definition,
+		CreatedAt:          time.Now().UTC().Format(time.RFC3339),
+		GenerationMetadata: meta,
+	}
 }
 
 // RephraseOp rewrites the prompt with different wording while preserving intent.
 type RephraseOp struct{}
 
 func (RephraseOp) Name() string { return OpRephrase }
-func (RephraseOp) Mutate(ctx context.Context, agent state.AgentDefinition, p provider.Provider) (state.AgentDefinition, error) {
-	return mutateWithPrompt(ctx, agent, p, fmt.Sprintf(
+func (RephraseOp) Mutate(ctx context.Context, agent state.AgentDefinition, p provider.Provider) (Mutation, error) {
+	return mutateWithPrompt(ctx, OpRephrase, agent, p, fmt.Sprintf(
 		`Rephrase this system prompt using different wording while preserving the exact same intent and instructions.
 Keep the same level of detail. Change sentence structure, vocabulary, and phrasing.
 
 Original prompt:
 %s
 
-Output JSON: {"system_prompt": "the rephrased prompt"}`, agent.SystemPrompt))
+Output JSON: {"system_prompt": "the rephrased prompt"%s}`, agent.SystemPrompt, reasoningField))
 }
 
 // ExpandOp adds more detail, examples, and edge cases to the prompt.
 type ExpandOp struct{}
 
 func (ExpandOp) Name() string { return OpExpand }
-func (ExpandOp) Mutate(ctx context.Context, agent state.AgentDefinition, p provider.Provider) (state.AgentDefinition, error) {
-	return mutateWithPrompt(ctx, agent, p, fmt.Sprintf(
+func (ExpandOp) Mutate(ctx context.Context, agent state.AgentDefinition, p provider.Provider) (Mutation, error) {
+	return mutateWithPrompt(ctx, OpExpand, agent, p, fmt.Sprintf(
 		`Expand this system prompt by adding more detail, examples, and edge case handling.
 Make it more thorough without changing the core instructions.
 
 Original prompt:
 %s
 
-Output JSON: {"system_prompt": "the expanded prompt"}`, agent.SystemPrompt))
+Output JSON: {"system_prompt": "the expanded prompt"%s}`, agent.SystemPrompt, reasoningField))
 }
 
 // SimplifyOp makes the prompt shorter and more direct.
 type SimplifyOp struct{}
 
 func (SimplifyOp) Name() string { return OpSimplify }
-func (SimplifyOp) Mutate(ctx context.Context, agent state.AgentDefinition, p provider.Provider) (state.AgentDefinition, error) {
-	return mutateWithPrompt(ctx, agent, p, fmt.Sprintf(
+func (SimplifyOp) Mutate(ctx context.Context, agent state.AgentDefinition, p provider.Provider) (Mutation, error) {
+	return mutateWithPrompt(ctx, OpSimplify, agent, p, fmt.Sprintf(
 		`Simplify this system prompt. Remove redundancy, tighten wording, keep only essential instructions.
 The result should be shorter but equally effective.
 
 Original prompt:
 %s
 
-Output JSON: {"system_prompt": "the simplified prompt"}`, agent.SystemPrompt))
+Output JSON: {"system_prompt": "the simplified prompt"%s}`, agent.SystemPrompt, reasoningField))
 }
 
-// CrossoverOp combines elements from two prompts.
+// CrossoverOp combines elements from two prompts. PartnerID, when set, is
+// recorded as the child's second parent.
 type CrossoverOp struct {
-	Partner state.AgentDefinition
+	Partner   state.AgentDefinition
+	PartnerID string
 }
 
 func (c CrossoverOp) Name() string { return OpCrossover }
-func (c CrossoverOp) Mutate(ctx context.Context, agent state.AgentDefinition, p provider.Provider) (state.AgentDefinition, error) {
-	return mutateWithPrompt(ctx, agent, p, fmt.Sprintf(
+func (c CrossoverOp) Mutate(ctx context.Context, agent state.AgentDefinition, p provider.Provider) (Mutation, error) {
+	m, err := mutateWithPrompt(ctx, OpCrossover, agent, p, fmt.Sprintf(
 		`Combine the best elements of these two system prompts into a single improved prompt.
 Take the strongest instructions from each.
 
@@ -91,7 +124,11 @@ Prompt A:
 Prompt B:
 %s
 
-Output JSON: {"system_prompt": "the combined prompt"}`, agent.SystemPrompt, c.Partner.SystemPrompt))
+Output JSON: {"system_prompt": "the combined prompt"%s}`, agent.SystemPrompt, c.Partner.SystemPrompt, reasoningField))
+	if err == nil && c.PartnerID != "" {
+		m.Metadata.ParentIDs = []string{c.PartnerID}
+	}
+	return m, err
 }
 
 // TargetedOp applies a specific improvement directive.
@@ -100,59 +137,105 @@ type TargetedOp struct {
 }
 
 func (t TargetedOp) Name() string { return OpTargeted }
-func (t TargetedOp) Mutate(ctx context.Context, agent state.AgentDefinition, p provider.Provider) (state.AgentDefinition, error) {
-	return mutateWithPrompt(ctx, agent, p, fmt.Sprintf(
+func (t TargetedOp) Mutate(ctx context.Context, agent state.AgentDefinition, p provider.Provider) (Mutation, error) {
+	return mutateWithPrompt(ctx, OpTargeted, agent, p, fmt.Sprintf(
 		`Improve this system prompt based on the following specific directive:
 %s
 
 Original prompt:
 %s
 
-Output JSON: {"system_prompt": "the improved prompt"}`, t.Directive, agent.SystemPrompt))
+Output JSON: {"system_prompt": "the improved prompt"%s}`, t.Directive, agent.SystemPrompt, reasoningField))
 }
 
-// mutateWithPrompt sends a mutation prompt to the provider and extracts the result.
-func mutateWithPrompt(ctx context.Context, agent state.AgentDefinition, p provider.Provider, prompt string) (state.AgentDefinition, error) {
+// reasoningField extends each operator's JSON output format so the model
+// explains its changes.
+const reasoningField = `, "reasoning": "one or two sentences on what you changed and why"`
+
+// mutateWithPrompt sends a mutation prompt to the provider and extracts the
+// result, recording the operator, prompt and reasoning as provenance. Locked
+// sections of a structured prompt are restored if the model changed them.
+func mutateWithPrompt(ctx context.Context, operator string, agent state.AgentDefinition, p provider.Provider, prompt string) (Mutation, error) {
+	newPrompt, reasoning, meta, err := generate(ctx, p, prompt, "system_prompt")
+	if err != nil {
+		return Mutation{}, err
+	}
+
+	// The child keeps the parent's model settings and few-shot examples.
+	def := agent
+	def.SystemPrompt = newPrompt
+	def.Sections = nil
+	if len(agent.Sections) > 0 {
+		def = withSections(def, keepLocked(agent.Sections, ParseSections(newPrompt)))
+	}
+	return Mutation{Definition: def, Metadata: provenance(p, meta, operator, prompt, reasoning)}, nil
+}
+
+// generate sends prompt to the provider and returns the requested JSON field
+// and the model's reasoning. Providers return the model's text verbatim, so
+// plain text is accepted as the field's value when the model ignored the
+// output format.
+func generate(ctx context.Context, p provider.Provider, prompt, field string) (string, string, provider.Metadata, error) {
 	if p == nil {
-		return state.AgentDefinition{}, fmt.Errorf("provider is required")
+		return "", "", provider.Metadata{}, fmt.Errorf("provider is required")
 	}
 
-	generated, _, err := p.GenerateAgent(ctx, prompt, nil)
+	generated, meta, err := p.GenerateAgent(ctx, prompt, nil)
 	if err != nil {
-		return state.AgentDefinition{}, fmt.Errorf("mutation failed: %w", err)
+		return "", "", provider.Metadata{}, fmt.Errorf("mutation failed: %w", err)
 	}
 
-	newPrompt := strings.TrimSpace(generated.SystemPrompt)
-	if newPrompt == "" {
-		// Try parsing as JSON in case the provider returned raw JSON
-		var parsed struct {
-			SystemPrompt string `json:"system_prompt"`
-		}
-		if jsonErr := json.Unmarshal([]byte(generated.SystemPrompt), &parsed); jsonErr == nil && parsed.SystemPrompt != "" {
-			newPrompt = parsed.SystemPrompt
+	text := strings.TrimSpace(generated.SystemPrompt)
+	reasoning := ""
+	var parsed map[string]any
+	if jsonErr := json.Unmarshal([]byte(stripCodeFence(text)), &parsed); jsonErr == nil {
+		if value, ok := parsed[field].(string); ok && strings.TrimSpace(value) != "" {
+			text = strings.TrimSpace(value)
+			if r, ok := parsed["reasoning"].(string); ok {
+				reasoning = strings.TrimSpace(r)
+			}
 		}
 	}
 
-	if newPrompt == "" {
-		return state.AgentDefinition{}, fmt.Errorf("mutation produced empty prompt")
+	if text == "" {
+		return "", "", provider.Metadata{}, fmt.Errorf("mutation produced empty prompt")
+	}
+	return text, reasoning, meta, nil
+}
+
+// provenance builds the metadata of a mutation produced through p.
+func provenance(p provider.Provider, meta provider.Metadata, operator, prompt, reasoning string) state.GenerationMetadata {
+	info := p.GetMetadata()
+	return state.GenerationMetadata{
+		Provider:       info.Provider,
+		Model:          info.Model,
+		TokensUsed:     meta.TokensUsed,
+		DurationMS:     meta.DurationMs,
+		CostUSD:        meta.CostUSD,
+		Operator:       operator,
+		MutationPrompt: prompt,
+		Reasoning:      reasoning,
 	}
+}
 
-	return state.AgentDefinition{
-		SystemPrompt: newPrompt,
-		Model:        agent.Model,
-		Temperature:  agent.Temperature,
-		MaxTokens:    agent.MaxTokens,
-		Tools:        agent.Tools,
-	}, nil
+// stripCodeFence removes a surrounding markdown code fence, if any.
+func stripCodeFence(text string) string {
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+	text = strings.TrimPrefix(text, "```")
+	if nl := strings.IndexByte(text, '\n'); nl >= 0 {
+		text = text[nl+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "```"))
 }
 
-// RandomOperator returns a random mutation operator (excluding crossover and targeted).
+// RandomOperator returns a random mutation operator (excluding crossover, targeted and failure_driven).
+// Training loops pass Loop.MutationRand so the loop seed decides; a nil rng
+// falls back to a fixed seed.
 func RandomOperator(rng *rand.Rand) Operator {
 	ops := []Operator{RephraseOp{}, ExpandOp{}, SimplifyOp{}}
-	if rng == nil {
-		rng = rand.New(rand.NewSource(42))
-	}
-	return ops[rng.Intn(len(ops))]
+	return ops[orNewRand(rng).Intn(len(ops))]
 }
 
 // NewOperator creates an operator by name.
@@ -168,6 +251,18 @@ func NewOperator(name string) (Operator, error) {
 		return CrossoverOp{}, nil
 	case OpTargeted:
 		return TargetedOp{}, nil
+	case OpFailureDriven:
+		return FailureDrivenOp{}, nil
+	case OpSection:
+		return SectionOp{}, nil
+	case OpSectionCrossover:
+		return SectionCrossoverOp{}, nil
+	case OpExampleAdd:
+		return ExampleAddOp{}, nil
+	case OpExampleRemove:
+		return ExampleRemoveOp{}, nil
+	case OpExampleSwap:
+		return ExampleSwapOp{}, nil
 	default:
 		return nil, fmt.Errorf("unknown operator %q; choose from: %s", name, strings.Join(AllOperators, ", "))
 	}
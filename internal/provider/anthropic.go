@@ -43,7 +43,7 @@ func NewAnthropicProvider(apiKey, model, baseURL string) *AnthropicProvider {
 }
 
 func (p *AnthropicProvider) GenerateAgent(ctx context.Context, need string, directives []string) (AgentDefinition, Metadata, error) {
-	text, usage, meta, err := p.messagesCall(ctx, "", need, 4096)
+	text, usage, meta, err := p.messagesCall(ctx, "", nil, need, 4096)
 	if err != nil {
 		return AgentDefinition{}, Metadata{}, fmt.Errorf("send request: %w", err)
 	}
@@ -62,7 +62,7 @@ func (p *AnthropicProvider) ExecuteAgent(ctx context.Context, agent AgentDefinit
 		maxTokens = 1024
 	}
 
-	text, usage, meta, err := p.messagesCall(ctx, agent.SystemPrompt, input, maxTokens)
+	text, usage, meta, err := p.messagesCall(ctx, agent.SystemPrompt, agent.Examples, input, maxTokens)
 	if err != nil {
 		return "", Metadata{}, fmt.Errorf("send request: %w", err)
 	}
@@ -106,17 +106,22 @@ type callMeta struct {
 	DurationMs int
 }
 
-func (p *AnthropicProvider) messagesCall(ctx context.Context, system, user string, maxTokens int) (string, anthropicUsage, callMeta, error) {
+func (p *AnthropicProvider) messagesCall(ctx context.Context, system string, examples []Example, user string, maxTokens int) (string, anthropicUsage, callMeta, error) {
 	start := time.Now()
 
+	messages := make([]anthropicMessage, 0, 2*len(examples)+1)
+	for _, ex := range examples {
+		messages = append(messages,
+			anthropicMessage{Role: "user", Content: ex.Input},
+			anthropicMessage{Role: "assistant", Content: ex.Output})
+	}
+	messages = append(messages, anthropicMessage{Role: "user", Content: user})
+
 	reqBody := anthropicMessageRequest{
 		Model:     p.model,
 		MaxTokens: maxTokens,
 		System:    system,
-		Messages: []anthropicMessage{{
-			Role:    "user",
-			Content: user,
-		}},
+		Messages:  messages,
 	}
 	payload, err := json.Marshal(reqBody)
 	if err != nil {
@@ -139,14 +144,16 @@ func (p *AnthropicProvider) messagesCall(ctx context.Context, system, user strin
 	defer resp.Body.Close()
 
 	var out anthropicMessageResponse
-	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
-		return "", anthropicUsage{}, callMeta{}, fmt.Errorf("decode anthropic response: %w", err)
-	}
+	decodeErr := json.NewDecoder(resp.Body).Decode(&out)
 	if resp.StatusCode >= 300 {
-		if out.Error != nil && out.Error.Message != "" {
-			return "", anthropicUsage{}, callMeta{}, fmt.Errorf("anthropic API error: %s", out.Error.Message)
+		statusErr := &StatusError{Provider: "anthropic", StatusCode: resp.StatusCode}
+		if decodeErr == nil && out.Error != nil {
+			statusErr.Message = out.Error.Message
 		}
-		return "", anthropicUsage{}, callMeta{}, fmt.Errorf("anthropic API error: status %d", resp.StatusCode)
+		return "", anthropicUsage{}, callMeta{}, statusErr
+	}
+	if decodeErr != nil {
+		return "", anthropicUsage{}, callMeta{}, fmt.Errorf("decode anthropic response: %w", decodeErr)
 	}
 	if len(out.Content) == 0 {
 		return "", anthropicUsage{}, callMeta{}, fmt.Errorf("anthropic response missing content")
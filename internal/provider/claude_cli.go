@@ -47,7 +47,7 @@ func (p *ClaudeCLIProvider) GenerateAgent(ctx context.Context, need string, dire
 func (p *ClaudeCLIProvider) ExecuteAgent(ctx context.Context, agent AgentDefinition, input string) (string, Metadata, error) {
 	start := time.Now()
 
-	output, meta, err := p.call(ctx, agent.SystemPrompt, input)
+	output, meta, err := p.call(ctx, RenderExamples(agent.SystemPrompt, agent.Examples), input)
 	if err != nil {
 		return "", Metadata{}, fmt.Errorf("execute agent: %w", err)
 	}
@@ -0,0 +1,27 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// StatusError is a non-success HTTP response from a provider API. Callers use
+// it to tell infrastructure failures apart from problems with the agent.
+type StatusError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s API error: %s", e.Provider, e.Message)
+	}
+	return fmt.Sprintf("%s API error: status %d", e.Provider, e.StatusCode)
+}
+
+// Retryable reports whether the same request may succeed later: rate limits,
+// request timeouts and server-side errors.
+func (e *StatusError) Retryable() bool {
+	return e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
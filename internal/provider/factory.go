@@ -12,10 +12,22 @@ type Config struct {
 	Model    string
 	BaseURL  string
 	APIKey   string
+	Seed     int64 // sampling seed for providers that accept one; 0 leaves sampling unseeded
 }
 
 // NewFactory builds a provider adapter from config and environment.
 func NewFactory(cfg Config) (Provider, error) {
+	p, err := newProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if seeder, ok := p.(Seeder); ok && cfg.Seed != 0 {
+		seeder.SetSeed(cfg.Seed)
+	}
+	return p, nil
+}
+
+func newProvider(cfg Config) (Provider, error) {
 	providerName := normalizeProviderName(cfg.Provider)
 
 	switch providerName {
@@ -51,6 +63,47 @@ func NewFactory(cfg Config) (Provider, error) {
 	}
 }
 
+// Pricing is a model's price in USD per million tokens.
+type Pricing struct {
+	InputPerMillion  float64
+	OutputPerMillion float64
+}
+
+// Cost returns the price of a call with the given token counts.
+func (p Pricing) Cost(tokensIn, tokensOut int) float64 {
+	return (float64(tokensIn)*p.InputPerMillion + float64(tokensOut)*p.OutputPerMillion) / 1_000_000.0
+}
+
+// PriceFor returns the price of model under providerName. Local providers
+// are free; ok is false for API models without a known price, and for the
+// Claude CLI, which reports its own cost per call.
+func PriceFor(providerName, model string) (Pricing, bool) {
+	switch normalizeProviderName(providerName) {
+	case "anthropic":
+		rate, ok := anthropicPricing[strings.TrimSpace(model)]
+		return Pricing{InputPerMillion: rate.inputPerMillion, OutputPerMillion: rate.outputPerMillion}, ok
+	case "openai-compatible":
+		rate, ok := openAICompatiblePricing[strings.TrimSpace(model)]
+		return Pricing{InputPerMillion: rate.inputPerMillion, OutputPerMillion: rate.outputPerMillion}, ok
+	case "ollama-native", "pi-cli":
+		return Pricing{}, true
+	default:
+		return Pricing{}, false
+	}
+}
+
+// SeedSupported reports whether the adapters of providerName accept a
+// sampling seed, without constructing one; it agrees with SupportsSeed on
+// adapters built by NewFactory.
+func SeedSupported(providerName string) bool {
+	switch normalizeProviderName(providerName) {
+	case "openai-compatible", "ollama-native":
+		return true
+	default:
+		return false
+	}
+}
+
 func normalizeProviderName(raw string) string {
 	name := strings.ToLower(strings.TrimSpace(raw))
 	if name == "" {
@@ -1,6 +1,10 @@
 package provider
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"strings"
+)
 
 // AgentDefinition is the prompt/model payload needed by providers.
 type AgentDefinition struct {
@@ -9,6 +13,32 @@ type AgentDefinition struct {
 	Temperature      float64
 	MaxTokens        int
 	InferenceOptions map[string]any // Provider-specific options (e.g., num_ctx, top_k for Ollama)
+	Examples         []Example      // few-shot turns sent before the input
+}
+
+// Example is one few-shot input/output pair. Chat APIs receive it as a user
+// and an assistant message; CLI providers render it into the system prompt.
+type Example struct {
+	Input  string
+	Output string
+}
+
+// RenderExamples appends examples to a system prompt for providers that
+// take a single prompt rather than a conversation.
+func RenderExamples(system string, examples []Example) string {
+	if len(examples) == 0 {
+		return system
+	}
+	var b strings.Builder
+	b.WriteString(system)
+	if system != "" {
+		b.WriteString("\n\n")
+	}
+	b.WriteString("## Examples")
+	for i, ex := range examples {
+		fmt.Fprintf(&b, "\n\nExample %d input:\n%s\n\nExample %d output:\n%s", i+1, ex.Input, i+1, ex.Output)
+	}
+	return b.String()
 }
 
 // Metadata captures provider call observability signals.
@@ -42,3 +72,15 @@ type Provider interface {
 	ExecuteAgent(ctx context.Context, agent AgentDefinition, input string) (string, Metadata, error)
 	GetMetadata() ProviderInfo
 }
+
+// Seeder is implemented by providers whose APIs accept a sampling seed.
+// Seeding makes repeated calls reproducible where the backend honours it.
+type Seeder interface {
+	SetSeed(seed int64)
+}
+
+// SupportsSeed reports whether p accepts a sampling seed.
+func SupportsSeed(p Provider) bool {
+	_, ok := p.(Seeder)
+	return ok
+}
@@ -14,6 +14,7 @@ import (
 type OllamaProvider struct {
 	model      string
 	baseURL    string
+	seed       *int64
 	httpClient *http.Client
 }
 
@@ -59,7 +60,8 @@ func (p *OllamaProvider) GenerateAgent(ctx context.Context, need string, directi
 		"num_ctx":     8192,
 		"temperature": 1.0,
 	}
-	text, meta, err := p.chat(ctx, "", need, opts)
+	p.seedOptions(opts)
+	text, meta, err := p.chat(ctx, "", nil, need, opts)
 	if err != nil {
 		return AgentDefinition{}, Metadata{}, fmt.Errorf("ollama generate: %w", err)
 	}
@@ -76,18 +78,31 @@ func (p *OllamaProvider) ExecuteAgent(ctx context.Context, agent AgentDefinition
 		"num_ctx":     8192,
 		"temperature": agent.Temperature,
 	}
+	p.seedOptions(opts)
 	if agent.InferenceOptions != nil {
 		for k, v := range agent.InferenceOptions {
 			opts[k] = v
 		}
 	}
-	text, meta, err := p.chat(ctx, agent.SystemPrompt, input, opts)
+	text, meta, err := p.chat(ctx, agent.SystemPrompt, agent.Examples, input, opts)
 	if err != nil {
 		return "", Metadata{}, fmt.Errorf("ollama execute: %w", err)
 	}
 	return text, meta, nil
 }
 
+// SetSeed sets the sampling seed option on every request; an agent's
+// InferenceOptions may still override it.
+func (p *OllamaProvider) SetSeed(seed int64) {
+	p.seed = &seed
+}
+
+func (p *OllamaProvider) seedOptions(opts map[string]any) {
+	if p.seed != nil {
+		opts["seed"] = *p.seed
+	}
+}
+
 func (p *OllamaProvider) GetMetadata() ProviderInfo {
 	return ProviderInfo{
 		Provider: "ollama",
@@ -96,11 +111,16 @@ func (p *OllamaProvider) GetMetadata() ProviderInfo {
 	}
 }
 
-func (p *OllamaProvider) chat(ctx context.Context, system, user string, opts map[string]any) (string, Metadata, error) {
+func (p *OllamaProvider) chat(ctx context.Context, system string, examples []Example, user string, opts map[string]any) (string, Metadata, error) {
 	var msgs []ollamaMessage
 	if system != "" {
 		msgs = append(msgs, ollamaMessage{Role: "system", Content: system})
 	}
+	for _, ex := range examples {
+		msgs = append(msgs,
+			ollamaMessage{Role: "user", Content: ex.Input},
+			ollamaMessage{Role: "assistant", Content: ex.Output})
+	}
 	msgs = append(msgs, ollamaMessage{Role: "user", Content: user})
 
 	req := ollamaChatRequest{
@@ -131,7 +151,11 @@ func (p *OllamaProvider) chat(ctx context.Context, system, user string, opts map
 	if resp.StatusCode != http.StatusOK {
 		var errBody bytes.Buffer
 		errBody.ReadFrom(resp.Body)
-		return "", Metadata{}, fmt.Errorf("ollama HTTP %d: %s", resp.StatusCode, errBody.String())
+		return "", Metadata{}, &StatusError{
+			Provider:   "ollama",
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("HTTP %d: %s", resp.StatusCode, errBody.String()),
+		}
 	}
 
 	var result ollamaChatResponse
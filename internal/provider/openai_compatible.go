@@ -21,6 +21,7 @@ type OpenAICompatibleProvider struct {
 	apiKey     string
 	model      string
 	baseURL    string
+	seed       *int64
 	httpClient *http.Client
 }
 
@@ -39,8 +40,13 @@ func NewOpenAICompatibleProvider(apiKey, model, baseURL string) *OpenAICompatibl
 	}
 }
 
+// SetSeed sends seed with every chat completion request.
+func (p *OpenAICompatibleProvider) SetSeed(seed int64) {
+	p.seed = &seed
+}
+
 func (p *OpenAICompatibleProvider) GenerateAgent(ctx context.Context, need string, directives []string) (AgentDefinition, Metadata, error) {
-	text, usage, meta, err := p.chatCompletionCall(ctx, "", need, 4096, 1.0)
+	text, usage, meta, err := p.chatCompletionCall(ctx, "", nil, need, 4096, 1.0)
 	if err != nil {
 		return AgentDefinition{}, Metadata{}, fmt.Errorf("send request: %w", err)
 	}
@@ -63,7 +69,7 @@ func (p *OpenAICompatibleProvider) ExecuteAgent(ctx context.Context, agent Agent
 		temp = 1.0
 	}
 
-	text, usage, meta, err := p.chatCompletionCall(ctx, agent.SystemPrompt, input, maxTokens, temp)
+	text, usage, meta, err := p.chatCompletionCall(ctx, agent.SystemPrompt, agent.Examples, input, maxTokens, temp)
 	if err != nil {
 		return "", Metadata{}, fmt.Errorf("send request: %w", err)
 	}
@@ -79,6 +85,7 @@ type openAIChatRequest struct {
 	Messages    []openAIChatMsg `json:"messages"`
 	Temperature float64         `json:"temperature"`
 	MaxTokens   int             `json:"max_tokens"`
+	Seed        *int64          `json:"seed,omitempty"`
 }
 
 type openAIChatMsg struct {
@@ -102,13 +109,18 @@ type openAIUsage struct {
 	TotalTokens      int `json:"total_tokens"`
 }
 
-func (p *OpenAICompatibleProvider) chatCompletionCall(ctx context.Context, system, user string, maxTokens int, temp float64) (string, openAIUsage, callMeta, error) {
+func (p *OpenAICompatibleProvider) chatCompletionCall(ctx context.Context, system string, examples []Example, user string, maxTokens int, temp float64) (string, openAIUsage, callMeta, error) {
 	start := time.Now()
 
 	messages := []openAIChatMsg{}
 	if strings.TrimSpace(system) != "" {
 		messages = append(messages, openAIChatMsg{Role: "system", Content: system})
 	}
+	for _, ex := range examples {
+		messages = append(messages,
+			openAIChatMsg{Role: "user", Content: ex.Input},
+			openAIChatMsg{Role: "assistant", Content: ex.Output})
+	}
 	messages = append(messages, openAIChatMsg{Role: "user", Content: user})
 
 	reqBody := openAIChatRequest{
@@ -116,6 +128,7 @@ func (p *OpenAICompatibleProvider) chatCompletionCall(ctx context.Context, syste
 		Messages:    messages,
 		Temperature: temp,
 		MaxTokens:   maxTokens,
+		Seed:        p.seed,
 	}
 	payload, err := json.Marshal(reqBody)
 	if err != nil {
@@ -137,14 +150,16 @@ func (p *OpenAICompatibleProvider) chatCompletionCall(ctx context.Context, syste
 	defer resp.Body.Close()
 
 	var out openAIChatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
-		return "", openAIUsage{}, callMeta{}, fmt.Errorf("decode openai-compatible response: %w", err)
-	}
+	decodeErr := json.NewDecoder(resp.Body).Decode(&out)
 	if resp.StatusCode >= 300 {
-		if out.Error != nil && out.Error.Message != "" {
-			return "", openAIUsage{}, callMeta{}, fmt.Errorf("openai-compatible API error: %s", out.Error.Message)
+		statusErr := &StatusError{Provider: "openai-compatible", StatusCode: resp.StatusCode}
+		if decodeErr == nil && out.Error != nil {
+			statusErr.Message = out.Error.Message
 		}
-		return "", openAIUsage{}, callMeta{}, fmt.Errorf("openai-compatible API error: status %d", resp.StatusCode)
+		return "", openAIUsage{}, callMeta{}, statusErr
+	}
+	if decodeErr != nil {
+		return "", openAIUsage{}, callMeta{}, fmt.Errorf("decode openai-compatible response: %w", decodeErr)
 	}
 	if len(out.Choices) == 0 {
 		return "", openAIUsage{}, callMeta{}, fmt.Errorf("openai-compatible response missing choices")
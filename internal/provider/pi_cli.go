@@ -55,7 +55,7 @@ func (p *PiCLIProvider) GenerateAgent(ctx context.Context, need string, directiv
 func (p *PiCLIProvider) ExecuteAgent(ctx context.Context, agent AgentDefinition, input string) (string, Metadata, error) {
 	start := time.Now()
 
-	output, meta, err := p.call(ctx, agent.SystemPrompt, input)
+	output, meta, err := p.call(ctx, RenderExamples(agent.SystemPrompt, agent.Examples), input)
 	if err != nil {
 		return "", Metadata{}, fmt.Errorf("execute agent: %w", err)
 	}
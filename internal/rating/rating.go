@@ -0,0 +1,204 @@
+// Package rating maintains persistent Elo and TrueSkill ratings for agents
+// from the pairwise outcomes of tournament rounds.
+package rating
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Perttulands/chiron/internal/state"
+	"github.com/Perttulands/chiron/internal/tournament"
+)
+
+// Rating systems for sorting a leaderboard.
+const (
+	SystemElo       = "elo"
+	SystemTrueSkill = "trueskill"
+)
+
+// Defaults for new ratings.
+const (
+	InitialElo   = 1500.0
+	DefaultK     = 32.0
+	InitialMu    = 25.0
+	InitialSigma = InitialMu / 3
+)
+
+// Config tunes the rating updates. The zero value uses the defaults.
+type Config struct {
+	K                   float64 `json:"k,omitempty"`                     // Elo K-factor; default 32
+	Beta                float64 `json:"beta,omitempty"`                  // TrueSkill performance spread; default sigma0/2
+	Tau                 float64 `json:"tau,omitempty"`                   // TrueSkill dynamics noise; default sigma0/100
+	DrawProbability     float64 `json:"draw_probability,omitempty"`      // TrueSkill draw prior; default 0.1
+	DrawMargin          float64 `json:"draw_margin,omitempty"`           // composite-score gap counted as a draw; default 0
+	IncludeErroredBouts bool    `json:"include_errored_bouts,omitempty"` // rate agent errors and timeouts; infrastructure failures never count
+}
+
+func (c Config) k() float64 {
+	if c.K <= 0 {
+		return DefaultK
+	}
+	return c.K
+}
+
+// Table is a set of ratings keyed by agent id, normally state.State.Ratings.
+type Table map[string]state.Rating
+
+// Get returns the rating for agent, initializing a fresh one if needed.
+func (t Table) Get(agent state.Agent) state.Rating {
+	if r, ok := t[agent.ID]; ok {
+		return r
+	}
+	return state.Rating{
+		AgentID:   agent.ID,
+		LineageID: agent.LineageID,
+		Version:   agent.Version,
+		Elo:       InitialElo,
+		Mu:        InitialMu,
+		Sigma:     InitialSigma,
+	}
+}
+
+// Conservative returns the TrueSkill skill estimate mu - 3*sigma, which
+// ranks an agent only as high as the evidence supports.
+func Conservative(r state.Rating) float64 {
+	return r.Mu - 3*r.Sigma
+}
+
+// UpdateTournament applies every round of a tournament to the table.
+func (t Table) UpdateTournament(trn *tournament.Tournament, cfg Config) {
+	for _, round := range trn.Rounds {
+		t.UpdateRound(trn.Contestants, round, cfg)
+	}
+}
+
+// UpdateRound rates one round as a set of pairwise matches: every pair of
+// contestants is compared on their mean composite score over the round's
+// repetitions. Rounds from paired formats (Swiss, elimination) rate only the
+// pairs that actually met. Updates are computed from the ratings as they
+// stood before the round, so the order of contestants does not matter.
+func (t Table) UpdateRound(contestants []tournament.Contestant, round tournament.Round, cfg Config) {
+	totals := map[string]float64{}
+	counts := map[string]int{}
+	for _, bout := range round.Bouts {
+		if bout.InfraFailure() || (bout.Error != "" && !cfg.IncludeErroredBouts) {
+			continue
+		}
+		totals[bout.ContestantID] += bout.CompositeScore.FinalScore
+		counts[bout.ContestantID]++
+	}
+
+	players := make([]tournament.Contestant, 0, len(contestants))
+	for _, c := range contestants {
+		if counts[c.ID] > 0 {
+			players = append(players, c)
+		}
+	}
+	if len(players) < 2 {
+		return
+	}
+
+	met := map[[2]string]bool{}
+	for _, p := range round.Pairs {
+		met[pairKey(p.A, p.B)] = true
+	}
+
+	before := make([]state.Rating, len(players))
+	for i, c := range players {
+		before[i] = t.Get(c.Agent)
+	}
+	after := make([]state.Rating, len(players))
+	copy(after, before)
+
+	for i := 0; i < len(players); i++ {
+		for j := i + 1; j < len(players); j++ {
+			a := totals[players[i].ID] / float64(counts[players[i].ID])
+			b := totals[players[j].ID] / float64(counts[players[j].ID])
+			if len(round.Pairs) > 0 && !met[pairKey(players[i].ID, players[j].ID)] {
+				continue
+			}
+			outcome := 0.5
+			switch {
+			case a-b > cfg.DrawMargin:
+				outcome = 1
+			case b-a > cfg.DrawMargin:
+				outcome = 0
+			}
+
+			dElo := eloDelta(before[i].Elo, before[j].Elo, outcome, cfg.k())
+			after[i].Elo += dElo
+			after[j].Elo -= dElo
+
+			muI, sigI, muJ, sigJ := trueSkillMatch(before[i], before[j], outcome, cfg)
+			after[i].Mu += muI - before[i].Mu
+			after[j].Mu += muJ - before[j].Mu
+			after[i].Sigma *= sigI / before[i].Sigma
+			after[j].Sigma *= sigJ / before[j].Sigma
+
+			recordOutcome(&after[i], outcome)
+			recordOutcome(&after[j], 1-outcome)
+		}
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	for i, c := range players {
+		after[i].LineageID = c.Agent.LineageID
+		after[i].Version = c.Agent.Version
+		after[i].UpdatedAt = now
+		t[c.Agent.ID] = after[i]
+	}
+}
+
+func pairKey(a, b string) [2]string {
+	if b < a {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
+
+func recordOutcome(r *state.Rating, outcome float64) {
+	r.Matches++
+	switch outcome {
+	case 1:
+		r.Wins++
+	case 0:
+		r.Losses++
+	default:
+		r.Draws++
+	}
+}
+
+// eloDelta returns the change in a's rating after a match with b, where
+// outcome is a's score: 1 win, 0.5 draw, 0 loss.
+func eloDelta(a, b, outcome, k float64) float64 {
+	expected := 1 / (1 + math.Pow(10, (b-a)/400))
+	return k * (outcome - expected)
+}
+
+// Leaderboard returns the ratings sorted best first by system: Elo, or the
+// conservative TrueSkill estimate.
+func (t Table) Leaderboard(system string) ([]state.Rating, error) {
+	var key func(state.Rating) float64
+	switch system {
+	case "", SystemElo:
+		key = func(r state.Rating) float64 { return r.Elo }
+	case SystemTrueSkill:
+		key = Conservative
+	default:
+		return nil, fmt.Errorf("unknown rating system %q; choose from: %s, %s", system, SystemElo, SystemTrueSkill)
+	}
+
+	ratings := make([]state.Rating, 0, len(t))
+	for _, r := range t {
+		ratings = append(ratings, r)
+	}
+	sort.Slice(ratings, func(i, j int) bool {
+		if key(ratings[i]) != key(ratings[j]) {
+			return key(ratings[i]) > key(ratings[j])
+		}
+		return ratings[i].AgentID < ratings[j].AgentID
+	})
+	return ratings, nil
+}
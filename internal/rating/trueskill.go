@@ -0,0 +1,97 @@
+package rating
+
+import (
+	"math"
+
+	"github.com/Perttulands/chiron/internal/state"
+)
+
+// trueSkillMatch returns the updated mu and sigma of a and b after one
+// two-player match, where outcome is a's score: 1 win, 0.5 draw, 0 loss.
+func trueSkillMatch(a, b state.Rating, outcome float64, cfg Config) (muA, sigmaA, muB, sigmaB float64) {
+	beta := cfg.Beta
+	if beta <= 0 {
+		beta = InitialSigma / 2
+	}
+	tau := cfg.Tau
+	if tau <= 0 {
+		tau = InitialSigma / 100
+	}
+	drawProbability := cfg.DrawProbability
+	if drawProbability <= 0 || drawProbability >= 1 {
+		drawProbability = 0.1
+	}
+
+	// Dynamics: uncertainty grows a little between matches.
+	varA := a.Sigma*a.Sigma + tau*tau
+	varB := b.Sigma*b.Sigma + tau*tau
+
+	// Orient the match so "winner" is a unless b won.
+	if outcome == 0 {
+		muB, sigmaB, muA, sigmaA = trueSkillMatch(b, a, 1, cfg)
+		return muA, sigmaA, muB, sigmaB
+	}
+
+	c := math.Sqrt(2*beta*beta + varA + varB)
+	epsilon := inverseNormalCDF((drawProbability+1)/2) * math.Sqrt2 * beta
+	t := (a.Mu - b.Mu) / c
+	e := epsilon / c
+
+	var v, w float64
+	if outcome == 1 {
+		v, w = vWin(t, e), wWin(t, e)
+	} else {
+		v, w = vDraw(t, e), wDraw(t, e)
+	}
+
+	muA = a.Mu + varA/c*v
+	muB = b.Mu - varB/c*v
+	sigmaA = math.Sqrt(varA * math.Max(1-varA/(c*c)*w, 1e-6))
+	sigmaB = math.Sqrt(varB * math.Max(1-varB/(c*c)*w, 1e-6))
+	return muA, sigmaA, muB, sigmaB
+}
+
+func normalPDF(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
+
+func normalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+func inverseNormalCDF(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+func vWin(t, e float64) float64 {
+	denom := normalCDF(t - e)
+	if denom < 1e-12 {
+		return e - t
+	}
+	return normalPDF(t-e) / denom
+}
+
+func wWin(t, e float64) float64 {
+	v := vWin(t, e)
+	return v * (v + t - e)
+}
+
+func vDraw(t, e float64) float64 {
+	denom := normalCDF(e-t) - normalCDF(-e-t)
+	if denom < 1e-12 {
+		if t < 0 {
+			return -t - e
+		}
+		return -t + e
+	}
+	return (normalPDF(-e-t) - normalPDF(e-t)) / denom
+}
+
+func wDraw(t, e float64) float64 {
+	denom := normalCDF(e-t) - normalCDF(-e-t)
+	if denom < 1e-12 {
+		return 1
+	}
+	v := vDraw(t, e)
+	return v*v + ((e-t)*normalPDF(e-t)+(e+t)*normalPDF(e+t))/denom
+}
@@ -0,0 +1,182 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CommandResult captures the outcome of a sandboxed shell command.
+type CommandResult struct {
+	Stdout     string
+	Stderr     string
+	ExitCode   int
+	DurationMs int
+	TimedOut   bool
+}
+
+// Output returns stdout and stderr combined, stdout first.
+func (r *CommandResult) Output() string {
+	if r.Stderr == "" {
+		return r.Stdout
+	}
+	if r.Stdout == "" {
+		return r.Stderr
+	}
+	return r.Stdout + "\n" + r.Stderr
+}
+
+// PrepareWorkspace creates a scratch workspace, optionally seeded from
+// baseDir, and writes files (relative path -> content) into it. The caller
+// must invoke cleanup when done.
+func PrepareWorkspace(baseDir string, files map[string]string) (dir string, cleanup func(), err error) {
+	root, err := os.MkdirTemp("", "chiron-workspace-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("mktemp workspace: %w", err)
+	}
+	cleanup = func() { _ = os.RemoveAll(root) }
+
+	dir = filepath.Join(root, "workspace")
+	if strings.TrimSpace(baseDir) != "" {
+		if err := copyDir(baseDir, dir); err != nil {
+			cleanup()
+			return "", nil, fmt.Errorf("copy base workspace: %w", err)
+		}
+	} else if err := os.MkdirAll(dir, 0o755); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("create workspace: %w", err)
+	}
+
+	for rel, content := range files {
+		target, err := workspacePath(dir, rel)
+		if err != nil {
+			cleanup()
+			return "", nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			cleanup()
+			return "", nil, fmt.Errorf("create dir for %s: %w", rel, err)
+		}
+		if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+			cleanup()
+			return "", nil, fmt.Errorf("write %s: %w", rel, err)
+		}
+	}
+
+	return dir, cleanup, nil
+}
+
+// workspacePath resolves rel inside dir, rejecting absolute paths and
+// traversal outside the workspace.
+func workspacePath(dir, rel string) (string, error) {
+	clean := filepath.Clean(strings.TrimSpace(rel))
+	if clean == "." || clean == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid workspace path %q", rel)
+	}
+	return filepath.Join(dir, clean), nil
+}
+
+// RunCommand runs a bash command with workspace as its working directory,
+// isolated by the configured engine. cfg.Timeout bounds the run; a timed-out
+// command is reported through CommandResult.TimedOut rather than an error.
+// Tools and BrStub are ignored.
+func RunCommand(ctx context.Context, cfg Config, workspace, command string) (*CommandResult, error) {
+	if strings.TrimSpace(command) == "" {
+		return nil, fmt.Errorf("command is required")
+	}
+
+	workParent, err := os.MkdirTemp("", "chiron-exec-*")
+	if err != nil {
+		return nil, fmt.Errorf("mktemp work_parent: %w", err)
+	}
+	defer os.RemoveAll(workParent)
+
+	fakeHome := filepath.Join(workParent, ".fake-home")
+	gopath := filepath.Join(workParent, ".gopath")
+	gomodcache := filepath.Join(workParent, ".gomodcache")
+	tmpDir := filepath.Join(workParent, ".tmp")
+	cacheDir := filepath.Join(workParent, ".cache")
+	for _, d := range []string{fakeHome, gopath, gomodcache, tmpDir, cacheDir} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			return nil, fmt.Errorf("create scratch dir %s: %w", d, err)
+		}
+	}
+
+	goExe, err := exec.LookPath("go")
+	if err != nil {
+		goExe = "/usr/local/go/bin/go"
+	}
+	goRoot := os.Getenv("GOROOT")
+	if goRoot == "" {
+		if out, err2 := exec.Command(goExe, "env", "GOROOT").Output(); err2 == nil {
+			goRoot = strings.TrimSpace(string(out))
+		}
+	}
+	nodeDir := ""
+	if nodeExe, nodeErr := exec.LookPath("node"); nodeErr == nil {
+		nodeDir = filepath.Dir(nodeExe)
+	}
+	sandboxPath := buildSandboxPath(false, workspace, "", filepath.Dir(goExe), nodeDir)
+
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	var cmd *exec.Cmd
+	switch cfg.Engine {
+	case "bwrap", "":
+		bwrapPath, err2 := exec.LookPath("bwrap")
+		if err2 != nil {
+			return nil, fmt.Errorf("bwrap not found: %w", err2)
+		}
+		args := BuildBwrapArgs(cfg, workspace, fakeHome, workParent, goRoot, sandboxPath, filepath.Join(workspace, ".lab-br.log"))
+		if cfg.Offline {
+			args = append(args, "--unshare-net")
+		}
+		args = append(args, "/bin/bash", "-c", command)
+		cmd = exec.CommandContext(ctx, bwrapPath, args...) // ubs:ignore - intentional: bwrap sandbox, command is suite-configured
+
+	case "none":
+		cmd = exec.CommandContext(ctx, "/bin/bash", "-c", command)
+		cmd.Env = buildDirectEnv(workspace, fakeHome, gopath, gomodcache, tmpDir, cacheDir, goRoot, sandboxPath)
+		cmd.Dir = workspace
+
+	default:
+		return nil, fmt.Errorf("unknown sandbox engine %q", cfg.Engine)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	runErr := cmd.Run()
+	result := &CommandResult{
+		Stdout:     stdout.String(),
+		Stderr:     stderr.String(),
+		DurationMs: int(time.Since(start).Milliseconds()),
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		result.TimedOut = true
+		result.ExitCode = -1
+		return result, nil
+	}
+	if runErr != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(runErr, &exitErr) {
+			return nil, fmt.Errorf("run command: %w", runErr)
+		}
+		result.ExitCode = exitErr.ExitCode()
+	}
+
+	return result, nil
+}
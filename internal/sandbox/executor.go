@@ -19,6 +19,7 @@ type Config struct {
 	Tools   []string // Pi tool allowlist
 	BrStub  bool     // inject br stub binary
 	Timeout time.Duration
+	Offline bool // unshare the network namespace (RunCommand only)
 }
 
 // RunResult captures the outcome of a sandboxed pi run.
@@ -235,8 +236,10 @@ func BuildBwrapArgs(cfg Config, workspace, fakeHome, workParent, goRoot, sandbox
 	tmpDir := filepath.Join(workParent, ".tmp")
 	cacheDir := filepath.Join(workParent, ".cache")
 
-	piPath, _ := exec.LookPath("pi")
-	piDir := filepath.Dir(piPath)
+	piDir := ""
+	if piPath, err := exec.LookPath("pi"); err == nil {
+		piDir = filepath.Dir(piPath)
+	}
 	nodeExe, nodeErr := exec.LookPath("node")
 	nodeDir := ""
 	if nodeErr == nil {
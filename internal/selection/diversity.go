@@ -0,0 +1,78 @@
+package selection
+
+import (
+	"github.com/Perttulands/chiron/internal/diversity"
+	"github.com/Perttulands/chiron/internal/tournament"
+)
+
+// DistanceFunc returns how different two contestants are, from 0 (identical)
+// to 1 (nothing in common), by contestant id.
+type DistanceFunc func(a, b string) float64
+
+// PromptDistance returns a DistanceFunc over contestant prompts, keyed by
+// contestant id: the character trigram distance from package diversity.
+// Unknown contestants are treated as maximally distant.
+func PromptDistance(prompts map[string]string) DistanceFunc {
+	return func(a, b string) float64 {
+		pa, okA := prompts[a]
+		pb, okB := prompts[b]
+		if !okA || !okB {
+			return 1
+		}
+		return diversity.NGramDistance(pa, pb, 3)
+	}
+}
+
+// DiversitySelector keeps the best contestant, then greedily adds the one
+// that maximizes (1-Weight)*fitness + Weight*distance, where fitness is the
+// average score scaled to [0, 1] over the field and distance is to the
+// nearest contestant already selected. It avoids keeping several near-copies
+// of one prompt. Without a Distance it behaves like truncation.
+type DiversitySelector struct {
+	Distance DistanceFunc
+	Weight   float64 // in [0, 1]; default 0.5
+}
+
+func (ds DiversitySelector) Select(standings []tournament.Standing, n int) []tournament.Standing {
+	if n <= 0 || len(standings) == 0 {
+		return nil
+	}
+	if ds.Distance == nil {
+		return TruncationSelector{}.Select(standings, n)
+	}
+	weight := ds.Weight
+	if weight <= 0 || weight > 1 {
+		weight = 0.5
+	}
+
+	pool := byRank(standings)
+	low, high := pool[0].AvgScore, pool[0].AvgScore
+	for _, s := range pool {
+		low = min(low, s.AvgScore)
+		high = max(high, s.AvgScore)
+	}
+	fitness := func(s tournament.Standing) float64 {
+		if high == low {
+			return 1
+		}
+		return (s.AvgScore - low) / (high - low)
+	}
+
+	selected := []tournament.Standing{pool[0]}
+	pool = pool[1:]
+	for len(selected) < n && len(pool) > 0 {
+		bestIndex, bestValue := 0, -1.0
+		for i, candidate := range pool {
+			nearest := 1.0
+			for _, s := range selected {
+				nearest = min(nearest, ds.Distance(candidate.ContestantID, s.ContestantID))
+			}
+			if value := (1-weight)*fitness(candidate) + weight*nearest; value > bestValue {
+				bestIndex, bestValue = i, value
+			}
+		}
+		selected = append(selected, pool[bestIndex])
+		pool = append(pool[:bestIndex], pool[bestIndex+1:]...)
+	}
+	return selected
+}
@@ -0,0 +1,337 @@
+package selection
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Perttulands/chiron/internal/tournament"
+)
+
+// Objectives measured from a contestant's bouts for multi-objective
+// selection. Quality is maximised; every other objective is minimised.
+const (
+	ObjectiveQuality      = "quality"       // mean composite score
+	ObjectiveCost         = "cost"          // mean USD per bout
+	ObjectiveTokens       = "tokens"        // mean input plus output tokens per bout
+	ObjectiveLatency      = "latency"       // mean bout duration in milliseconds
+	ObjectivePromptLength = "prompt_length" // characters in the system prompt and few-shot examples
+)
+
+// ValidObjectives lists the objectives Objectives can measure.
+var ValidObjectives = []string{ObjectiveQuality, ObjectiveCost, ObjectiveTokens, ObjectiveLatency, ObjectivePromptLength}
+
+// DefaultObjectives trades quality off against cost, latency and prompt length.
+var DefaultObjectives = []string{ObjectiveQuality, ObjectiveCost, ObjectiveLatency, ObjectivePromptLength}
+
+// ValidateObjectives checks objective names and rejects duplicates.
+func ValidateObjectives(names []string) error {
+	seen := map[string]bool{}
+	for _, name := range names {
+		valid := false
+		for _, o := range ValidObjectives {
+			valid = valid || o == name
+		}
+		if !valid {
+			return fmt.Errorf("unknown objective %q; choose from: %s", name, strings.Join(ValidObjectives, ", "))
+		}
+		if seen[name] {
+			return fmt.Errorf("objective %q listed twice", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// ObjectiveNames returns the configured objectives, or DefaultObjectives.
+func (c Config) ObjectiveNames() []string {
+	if len(c.Objectives) == 0 {
+		return DefaultObjectives
+	}
+	return c.Objectives
+}
+
+// Maximized reports whether higher values of objective are better.
+func Maximized(objective string) bool {
+	return objective == ObjectiveQuality
+}
+
+// Objectives measures names for every contestant with at least one bout
+// that did not fail for infrastructure reasons, keyed by contestant id.
+// Values follow the order of names and are in their natural units.
+func Objectives(t *tournament.Tournament, names []string) map[string][]float64 {
+	type totals struct {
+		score, cost, tokens, latency float64
+		bouts                        int
+	}
+	sums := map[string]*totals{}
+	for _, round := range t.Rounds {
+		for _, b := range round.Bouts {
+			if b.InfraFailure() {
+				continue
+			}
+			s := sums[b.ContestantID]
+			if s == nil {
+				s = &totals{}
+				sums[b.ContestantID] = s
+			}
+			s.score += b.CompositeScore.FinalScore
+			s.cost += b.CostUSD
+			s.tokens += float64(b.TokensIn + b.TokensOut)
+			s.latency += float64(b.DurationMS)
+			s.bouts++
+		}
+	}
+
+	values := map[string][]float64{}
+	for _, c := range t.Contestants {
+		s := sums[c.ID]
+		if s == nil {
+			continue
+		}
+		n := float64(s.bouts)
+		v := make([]float64, len(names))
+		for i, name := range names {
+			switch name {
+			case ObjectiveQuality:
+				v[i] = s.score / n
+			case ObjectiveCost:
+				v[i] = s.cost / n
+			case ObjectiveTokens:
+				v[i] = s.tokens / n
+			case ObjectiveLatency:
+				v[i] = s.latency / n
+			case ObjectivePromptLength:
+				def := c.Agent.Definition
+				length := utf8.RuneCountInString(def.SystemPrompt)
+				for _, ex := range def.Examples {
+					length += utf8.RuneCountInString(ex.Input) + utf8.RuneCountInString(ex.Output)
+				}
+				v[i] = float64(length)
+			}
+		}
+		values[c.ID] = v
+	}
+	return values
+}
+
+// Dominates reports whether a is at least as good as b on every objective
+// and strictly better on one. Values are oriented so that higher is better.
+func Dominates(a, b []float64) bool {
+	strictly := false
+	for i := range a {
+		if a[i] < b[i] {
+			return false
+		}
+		if a[i] > b[i] {
+			strictly = true
+		}
+	}
+	return strictly
+}
+
+// NonDominatedSort splits points into Pareto fronts, NSGA-II style: the
+// first front holds the points no other point dominates, the second those
+// dominated only by the first, and so on. Points are oriented so that
+// higher is better; fronts hold indexes into points.
+func NonDominatedSort(points [][]float64) [][]int {
+	dominatedBy := make([]int, len(points)) // how many points dominate i
+	dominates := make([][]int, len(points)) // points i dominates
+	current := []int{}
+	for i := range points {
+		for j := range points {
+			if i == j {
+				continue
+			}
+			if Dominates(points[i], points[j]) {
+				dominates[i] = append(dominates[i], j)
+			} else if Dominates(points[j], points[i]) {
+				dominatedBy[i]++
+			}
+		}
+		if dominatedBy[i] == 0 {
+			current = append(current, i)
+		}
+	}
+
+	fronts := [][]int{}
+	for len(current) > 0 {
+		fronts = append(fronts, current)
+		next := []int{}
+		for _, i := range current {
+			for _, j := range dominates[i] {
+				dominatedBy[j]--
+				if dominatedBy[j] == 0 {
+					next = append(next, j)
+				}
+			}
+		}
+		sort.Ints(next)
+		current = next
+	}
+	return fronts
+}
+
+// CrowdingDistance returns the NSGA-II crowding distance of each point in
+// front: the sum over objectives of the normalised gap between its
+// neighbours. The extremes of every objective get +Inf so they are kept;
+// an objective on which the whole front is equal has no extremes and adds
+// nothing. Fronts of one or two points are all extremes.
+func CrowdingDistance(points [][]float64, front []int) []float64 {
+	distance := make([]float64, len(front))
+	if len(front) <= 2 {
+		for i := range distance {
+			distance[i] = math.Inf(1)
+		}
+		return distance
+	}
+	order := make([]int, len(front)) // positions in front
+	for m := range points[front[0]] {
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return points[front[order[a]]][m] < points[front[order[b]]][m] })
+		low, high := points[front[order[0]]][m], points[front[order[len(order)-1]]][m]
+		if high == low {
+			continue
+		}
+		distance[order[0]] = math.Inf(1)
+		distance[order[len(order)-1]] = math.Inf(1)
+		for k := 1; k < len(order)-1; k++ {
+			gap := points[front[order[k+1]]][m] - points[front[order[k-1]]][m]
+			distance[order[k]] += gap / (high - low)
+		}
+	}
+	return distance
+}
+
+// oriented returns values with every minimised objective negated, so that
+// higher is better on all of them.
+func oriented(values []float64, names []string) []float64 {
+	out := make([]float64, len(values))
+	for i, v := range values {
+		if i < len(names) && !Maximized(names[i]) {
+			v = -v
+		}
+		out[i] = v
+	}
+	return out
+}
+
+// ParetoSelector keeps whole Pareto fronts in order, then fills the last
+// slots from the next front by crowding distance, preferring contestants in
+// sparse regions of the trade-off. Contestants without Values go last by
+// rank. Without Values it behaves like truncation.
+type ParetoSelector struct {
+	Objectives []string             // objective names, in the order of Values; default DefaultObjectives
+	Values     map[string][]float64 // objective values by contestant id, from Objectives
+}
+
+func (ps ParetoSelector) Select(standings []tournament.Standing, n int) []tournament.Standing {
+	if n <= 0 || len(standings) == 0 {
+		return nil
+	}
+	if len(ps.Values) == 0 {
+		return TruncationSelector{}.Select(standings, n)
+	}
+	names := ps.Objectives
+	if len(names) == 0 {
+		names = DefaultObjectives
+	}
+
+	sorted := byRank(standings)
+	measured := []tournament.Standing{}
+	unmeasured := []tournament.Standing{}
+	points := [][]float64{}
+	for _, s := range sorted {
+		if v, ok := ps.Values[s.ContestantID]; ok {
+			measured = append(measured, s)
+			points = append(points, oriented(v, names))
+		} else {
+			unmeasured = append(unmeasured, s)
+		}
+	}
+
+	selected := make([]tournament.Standing, 0, n)
+	for _, front := range NonDominatedSort(points) {
+		if len(selected) >= n {
+			break
+		}
+		if len(selected)+len(front) > n {
+			distance := CrowdingDistance(points, front)
+			order := make([]int, len(front))
+			for i := range order {
+				order[i] = i
+			}
+			sort.SliceStable(order, func(a, b int) bool { return distance[order[a]] > distance[order[b]] })
+			for _, i := range order[:n-len(selected)] {
+				selected = append(selected, measured[front[i]])
+			}
+			break
+		}
+		for _, i := range front {
+			selected = append(selected, measured[i])
+		}
+	}
+	for _, s := range unmeasured {
+		if len(selected) >= n {
+			break
+		}
+		selected = append(selected, s)
+	}
+	return selected
+}
+
+// ParetoPoint is one contestant on a Pareto front.
+type ParetoPoint struct {
+	ContestantID string             `json:"contestant_id"`
+	LineageID    string             `json:"lineage_id"`
+	Objectives   map[string]float64 `json:"objectives"`
+	Crowding     float64            `json:"crowding,omitempty"` // crowding distance; 0 for boundary points
+	Boundary     bool               `json:"boundary,omitempty"` // best or worst on some objective
+}
+
+// ParetoFront returns the tournament's non-dominated contestants under
+// names, boundary points first, then by crowding distance.
+func ParetoFront(t *tournament.Tournament, names []string) []ParetoPoint {
+	values := Objectives(t, names)
+	ids := []string{}
+	points := [][]float64{}
+	lineages := map[string]string{}
+	for _, c := range t.Contestants {
+		if v, ok := values[c.ID]; ok {
+			ids = append(ids, c.ID)
+			points = append(points, oriented(v, names))
+			lineages[c.ID] = c.LineageID
+		}
+	}
+	fronts := NonDominatedSort(points)
+	if len(fronts) == 0 {
+		return []ParetoPoint{}
+	}
+
+	front := fronts[0]
+	distance := CrowdingDistance(points, front)
+	out := make([]ParetoPoint, 0, len(front))
+	for k, i := range front {
+		p := ParetoPoint{ContestantID: ids[i], LineageID: lineages[ids[i]], Objectives: map[string]float64{}}
+		for m, name := range names {
+			p.Objectives[name] = values[ids[i]][m]
+		}
+		if math.IsInf(distance[k], 1) {
+			p.Boundary = true
+		} else {
+			p.Crowding = distance[k]
+		}
+		out = append(out, p)
+	}
+	sort.SliceStable(out, func(a, b int) bool {
+		if out[a].Boundary != out[b].Boundary {
+			return out[a].Boundary
+		}
+		return out[a].Crowding > out[b].Crowding
+	})
+	return out
+}
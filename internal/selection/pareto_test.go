@@ -0,0 +1,106 @@
+package selection
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDominates(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float64
+		want bool
+	}{
+		{name: "better on all", a: []float64{2, 2}, b: []float64{1, 1}, want: true},
+		{name: "better on one, equal on other", a: []float64{2, 1}, b: []float64{1, 1}, want: true},
+		{name: "equal", a: []float64{1, 1}, b: []float64{1, 1}, want: false},
+		{name: "trade-off", a: []float64{2, 0}, b: []float64{1, 1}, want: false},
+		{name: "worse", a: []float64{0, 0}, b: []float64{1, 1}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Dominates(tt.a, tt.b); got != tt.want {
+				t.Errorf("Dominates(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNonDominatedSort(t *testing.T) {
+	tests := []struct {
+		name   string
+		points [][]float64
+		want   [][]int
+	}{
+		{name: "empty", points: nil, want: [][]int{}},
+		{
+			name:   "layered fronts",
+			points: [][]float64{{1, 5}, {2, 2}, {3, 3}, {1, 1}, {2, 4}, {0, 0}},
+			want:   [][]int{{0, 2, 4}, {1}, {3}, {5}},
+		},
+		{
+			name:   "duplicates share a front",
+			points: [][]float64{{1, 1}, {3, 3}, {1, 1}},
+			want:   [][]int{{1}, {0, 2}},
+		},
+		{
+			name:   "all trade-offs",
+			points: [][]float64{{1, 4}, {2, 3}, {3, 2}, {4, 1}},
+			want:   [][]int{{0, 1, 2, 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NonDominatedSort(tt.points); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NonDominatedSort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCrowdingDistance(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		name   string
+		points [][]float64
+		front  []int
+		want   []float64
+	}{
+		{name: "empty front", points: [][]float64{{1, 1}}, front: nil, want: []float64{}},
+		{name: "single point", points: [][]float64{{1, 1}}, front: []int{0}, want: []float64{inf}},
+		{name: "two equal points", points: [][]float64{{1, 1}, {1, 1}}, front: []int{0, 1}, want: []float64{inf, inf}},
+		{
+			name:   "evenly spaced",
+			points: [][]float64{{1, 4}, {2, 3}, {3, 2}, {4, 1}},
+			front:  []int{0, 1, 2, 3},
+			want:   []float64{inf, 4.0 / 3, 4.0 / 3, inf},
+		},
+		{
+			name:   "crowded middle",
+			points: [][]float64{{0, 10}, {1, 9}, {9, 1}, {10, 0}},
+			front:  []int{0, 1, 2, 3},
+			want:   []float64{inf, 1.8, 1.8, inf},
+		},
+		{
+			name:   "constant objective adds nothing",
+			points: [][]float64{{1, 5}, {2, 5}, {3, 5}, {4, 5}},
+			front:  []int{3, 1, 0, 2},
+			want:   []float64{inf, 2.0 / 3, inf, 2.0 / 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CrowdingDistance(tt.points, tt.front)
+			if len(got) != len(tt.want) {
+				t.Fatalf("CrowdingDistance() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] && math.Abs(got[i]-tt.want[i]) > 1e-9 {
+					t.Errorf("CrowdingDistance() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
@@ -2,8 +2,11 @@ package selection
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/Perttulands/chiron/internal/tournament"
 )
@@ -13,89 +16,210 @@ const (
 	StrategyTruncation = "truncation"
 	StrategyTournament = "tournament"
 	StrategyElitist    = "elitist"
+	StrategyRoulette   = "roulette"
+	StrategyRank       = "rank"
+	StrategyBoltzmann  = "boltzmann"
+	StrategyDiversity  = "diversity"
+	StrategyPareto     = "pareto"
 )
 
+// ValidStrategies lists all built-in selection strategies.
+var ValidStrategies = []string{StrategyTruncation, StrategyTournament, StrategyElitist, StrategyRoulette, StrategyRank, StrategyBoltzmann, StrategyDiversity, StrategyPareto}
+
 // Selector picks winners from tournament standings.
 type Selector interface {
 	Select(standings []tournament.Standing, n int) []tournament.Standing
 }
 
-// TruncationSelector keeps the top N by rank.
+// Config tunes the selection strategies. The zero value uses the defaults.
+type Config struct {
+	Elite           int      `json:"elite,omitempty"`            // top contestants always kept; default 1 for elitist, else 0
+	TournamentSize  int      `json:"tournament_size,omitempty"`  // contestants per tournament draw; default 2
+	Pressure        float64  `json:"pressure,omitempty"`         // rank selection pressure in [1, 2]; default 1.5
+	Temperature     float64  `json:"temperature,omitempty"`      // Boltzmann starting temperature; default 1
+	Cooling         float64  `json:"cooling,omitempty"`          // Boltzmann temperature factor per generation; default 0.9
+	MinTemperature  float64  `json:"min_temperature,omitempty"`  // Boltzmann temperature floor; default 0.05
+	DiversityWeight float64  `json:"diversity_weight,omitempty"` // diversity vs fitness in [0, 1]; default 0.5
+	Objectives      []string `json:"objectives,omitempty"`       // pareto objectives; default DefaultObjectives
+
+	Generation      int                  `json:"-"` // 1-based generation being selected, for temperature schedules
+	Distance        DistanceFunc         `json:"-"` // contestant distance for the diversity selector
+	ObjectiveValues map[string][]float64 `json:"-"` // objective values by contestant id for the pareto selector
+}
+
+// TemperatureAt returns the Boltzmann temperature for a 1-based generation:
+// Temperature * Cooling^(generation-1), never below MinTemperature.
+func (c Config) TemperatureAt(generation int) float64 {
+	t0 := c.Temperature
+	if t0 <= 0 {
+		t0 = 1
+	}
+	cooling := c.Cooling
+	if cooling <= 0 || cooling > 1 {
+		cooling = 0.9
+	}
+	floor := c.MinTemperature
+	if floor <= 0 {
+		floor = 0.05
+	}
+	if generation < 1 {
+		generation = 1
+	}
+	return math.Max(t0*math.Pow(cooling, float64(generation-1)), floor)
+}
+
+// New creates a selector by strategy name. rng drives the stochastic
+// strategies; pass a seeded source to make selection reproducible. With
+// Config.Elite > 0 any strategy keeps the top contestants first.
+func New(strategy string, cfg Config, rng *rand.Rand) (Selector, error) {
+	if err := ValidateObjectives(cfg.Objectives); err != nil {
+		return nil, err
+	}
+	var base Selector
+	switch strategy {
+	case StrategyTruncation:
+		base = TruncationSelector{}
+	case StrategyTournament:
+		base = TournamentSelector{Rng: rng, Size: cfg.TournamentSize}
+	case StrategyElitist:
+		elite := cfg.Elite
+		if elite <= 0 {
+			elite = 1
+		}
+		return ElitistSelector{Elite: elite, Rest: TournamentSelector{Rng: rng, Size: cfg.TournamentSize}}, nil
+	case StrategyRoulette:
+		base = RouletteSelector{Rng: rng}
+	case StrategyRank:
+		base = RankSelector{Rng: rng, Pressure: cfg.Pressure}
+	case StrategyBoltzmann:
+		base = BoltzmannSelector{Rng: rng, Temperature: cfg.TemperatureAt(cfg.Generation)}
+	case StrategyDiversity:
+		base = DiversitySelector{Distance: cfg.Distance, Weight: cfg.DiversityWeight}
+	case StrategyPareto:
+		base = ParetoSelector{Objectives: cfg.ObjectiveNames(), Values: cfg.ObjectiveValues}
+	default:
+		return nil, fmt.Errorf("unknown selection strategy %q; choose from: %s", strategy, strings.Join(ValidStrategies, ", "))
+	}
+	if cfg.Elite > 0 {
+		return ElitistSelector{Elite: cfg.Elite, Rest: base}, nil
+	}
+	return base, nil
+}
+
+// NewSelector creates a selector by strategy name with default settings and
+// an unseeded RNG.
+func NewSelector(strategy string) (Selector, error) {
+	return New(strategy, Config{}, nil)
+}
+
+// TruncationSelector keeps the top N by rank. Contestants tied with the Nth
+// (see tournament.Ranking.TieInsignificant) are kept too, so a variant is
+// never eliminated by a difference that is not significant.
 type TruncationSelector struct{}
 
 func (TruncationSelector) Select(standings []tournament.Standing, n int) []tournament.Standing {
 	if n <= 0 || len(standings) == 0 {
 		return nil
 	}
-	sorted := make([]tournament.Standing, len(standings))
-	copy(sorted, standings)
-	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Rank < sorted[j].Rank })
+	sorted := byRank(standings)
 	if n > len(sorted) {
 		n = len(sorted)
 	}
+	for n < len(sorted) && sorted[n].Rank == sorted[n-1].Rank {
+		n++
+	}
 	return sorted[:n]
 }
 
-// TournamentSelector runs pairwise comparisons to select winners.
+// TournamentSelector fills each slot with the best of Size contestants drawn
+// at random from those not yet selected.
 type TournamentSelector struct {
-	Rng *rand.Rand
+	Rng  *rand.Rand
+	Size int // default 2
 }
 
 func (ts TournamentSelector) Select(standings []tournament.Standing, n int) []tournament.Standing {
 	if n <= 0 || len(standings) == 0 {
 		return nil
 	}
-
-	rng := ts.Rng
-	if rng == nil {
-		rng = rand.New(rand.NewSource(42))
+	rng := orNewRand(ts.Rng)
+	size := ts.Size
+	if size < 2 {
+		size = 2
 	}
 
+	pool := byRank(standings)
 	selected := make([]tournament.Standing, 0, n)
-	used := map[string]bool{}
-
-	for len(selected) < n && len(used) < len(standings) {
-		// Pick two random contestants, keep the better one
-		a := standings[rng.Intn(len(standings))]
-		b := standings[rng.Intn(len(standings))]
-
-		winner := a
-		if b.AvgScore > a.AvgScore {
-			winner = b
+	for len(selected) < n && len(pool) > 0 {
+		draw := rng.Perm(len(pool))
+		if len(draw) > size {
+			draw = draw[:size]
 		}
-
-		if !used[winner.ContestantID] {
-			used[winner.ContestantID] = true
-			selected = append(selected, winner)
+		best := draw[0]
+		for _, i := range draw[1:] {
+			if better(pool[i], pool[best]) {
+				best = i
+			}
 		}
+		selected = append(selected, pool[best])
+		pool = append(pool[:best], pool[best+1:]...)
 	}
-
 	return selected
 }
 
-// ElitistSelector always keeps the single best, fills remaining by truncation.
-type ElitistSelector struct{}
+// ElitistSelector always keeps the Elite best by rank, then fills the
+// remaining slots from the other contestants with Rest.
+type ElitistSelector struct {
+	Elite int      // default 1
+	Rest  Selector // default TournamentSelector
+}
 
-func (ElitistSelector) Select(standings []tournament.Standing, n int) []tournament.Standing {
+func (es ElitistSelector) Select(standings []tournament.Standing, n int) []tournament.Standing {
 	if n <= 0 || len(standings) == 0 {
 		return nil
 	}
-	return TruncationSelector{}.Select(standings, n)
+	elite := es.Elite
+	if elite <= 0 {
+		elite = 1
+	}
+	if elite > n {
+		elite = n
+	}
+	rest := es.Rest
+	if rest == nil {
+		rest = TournamentSelector{}
+	}
+
+	sorted := byRank(standings)
+	if elite >= len(sorted) {
+		return sorted
+	}
+	selected := append([]tournament.Standing{}, sorted[:elite]...)
+	return append(selected, rest.Select(sorted[elite:], n-elite)...)
 }
 
-// NewSelector creates a selector by strategy name.
-func NewSelector(strategy string) (Selector, error) {
-	switch strategy {
-	case StrategyTruncation:
-		return TruncationSelector{}, nil
-	case StrategyTournament:
-		return TournamentSelector{}, nil
-	case StrategyElitist:
-		return ElitistSelector{}, nil
-	default:
-		return nil, fmt.Errorf("unknown selection strategy %q; choose from: %s, %s, %s",
-			strategy, StrategyTruncation, StrategyTournament, StrategyElitist)
+// byRank returns a copy of standings sorted best first.
+func byRank(standings []tournament.Standing) []tournament.Standing {
+	sorted := make([]tournament.Standing, len(standings))
+	copy(sorted, standings)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Rank < sorted[j].Rank })
+	return sorted
+}
+
+// better reports whether a beats b: higher average score, then better rank.
+func better(a, b tournament.Standing) bool {
+	if a.AvgScore != b.AvgScore {
+		return a.AvgScore > b.AvgScore
+	}
+	return a.Rank < b.Rank
+}
+
+// orNewRand returns rng, or a time-seeded generator when it is nil.
+func orNewRand(rng *rand.Rand) *rand.Rand {
+	if rng != nil {
+		return rng
 	}
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
 }
 
 // Partition splits standings into winners and losers based on selection.
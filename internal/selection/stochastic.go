@@ -0,0 +1,120 @@
+package selection
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/Perttulands/chiron/internal/tournament"
+)
+
+// RouletteSelector picks contestants with probability proportional to their
+// average score, without replacement.
+type RouletteSelector struct {
+	Rng *rand.Rand
+}
+
+func (rs RouletteSelector) Select(standings []tournament.Standing, n int) []tournament.Standing {
+	sorted := byRank(standings)
+	weights := make([]float64, len(sorted))
+	for i, s := range sorted {
+		weights[i] = math.Max(s.AvgScore, 0)
+	}
+	return sampleWeighted(sorted, weights, n, orNewRand(rs.Rng))
+}
+
+// RankSelector picks contestants with linear rank-based probabilities, so
+// selection pressure does not depend on how far apart the scores are. With
+// Pressure p the best contestant is p times as likely as average and the
+// worst 2-p times.
+type RankSelector struct {
+	Rng      *rand.Rand
+	Pressure float64 // in [1, 2]; default 1.5
+}
+
+func (rs RankSelector) Select(standings []tournament.Standing, n int) []tournament.Standing {
+	pressure := rs.Pressure
+	if pressure < 1 || pressure > 2 {
+		pressure = 1.5
+	}
+	sorted := byRank(standings)
+	weights := make([]float64, len(sorted))
+	last := float64(len(sorted) - 1)
+	for i, s := range sorted {
+		if last == 0 {
+			weights[i] = 1
+			continue
+		}
+		// Tied contestants share the weight of their common rank.
+		position := float64(s.Rank - 1)
+		if position > last {
+			position = last
+		}
+		weights[i] = (2 - pressure) + 2*(pressure-1)*(last-position)/last
+	}
+	return sampleWeighted(sorted, weights, n, orNewRand(rs.Rng))
+}
+
+// BoltzmannSelector picks contestants with probability proportional to
+// exp(score / Temperature), without replacement. High temperatures explore,
+// low temperatures approach truncation; Config.TemperatureAt cools it over
+// generations. Scores are divided by 10 so temperatures are scale-free.
+type BoltzmannSelector struct {
+	Rng         *rand.Rand
+	Temperature float64 // default 1
+}
+
+func (bs BoltzmannSelector) Select(standings []tournament.Standing, n int) []tournament.Standing {
+	temperature := bs.Temperature
+	if temperature <= 0 {
+		temperature = 1
+	}
+	sorted := byRank(standings)
+	if len(sorted) == 0 {
+		return nil
+	}
+	best := sorted[0].AvgScore
+	for _, s := range sorted {
+		best = math.Max(best, s.AvgScore)
+	}
+	weights := make([]float64, len(sorted))
+	for i, s := range sorted {
+		// Shift by the best score so exp never overflows.
+		weights[i] = math.Exp((s.AvgScore - best) / 10 / temperature)
+	}
+	return sampleWeighted(sorted, weights, n, orNewRand(bs.Rng))
+}
+
+// sampleWeighted draws n standings without replacement, each draw with
+// probability proportional to its remaining weight. When every remaining
+// weight is zero the draw is uniform.
+func sampleWeighted(standings []tournament.Standing, weights []float64, n int, rng *rand.Rand) []tournament.Standing {
+	if n <= 0 || len(standings) == 0 {
+		return nil
+	}
+	pool := append([]tournament.Standing{}, standings...)
+	w := append([]float64{}, weights...)
+	selected := make([]tournament.Standing, 0, n)
+	for len(selected) < n && len(pool) > 0 {
+		total := 0.0
+		for _, x := range w {
+			total += x
+		}
+		pick := len(pool) - 1
+		if total <= 0 {
+			pick = rng.Intn(len(pool))
+		} else {
+			r := rng.Float64() * total
+			for i, x := range w {
+				if r < x {
+					pick = i
+					break
+				}
+				r -= x
+			}
+		}
+		selected = append(selected, pool[pick])
+		pool = append(pool[:pick], pool[pick+1:]...)
+		w = append(w[:pick], w[pick+1:]...)
+	}
+	return selected
+}
@@ -15,6 +15,20 @@ func AddArtifact(sessionID, lineageID string, artifact Artifact) (string, error)
 		return "", fmt.Errorf("load state: %w", err)
 	}
 
+	artifactID, err := st.AppendArtifact(sessionID, lineageID, artifact)
+	if err != nil {
+		return "", err
+	}
+
+	if err := Save("", st); err != nil {
+		return "", fmt.Errorf("save state: %w", err)
+	}
+	return artifactID, nil
+}
+
+// AppendArtifact appends one artifact to a lineage in st, assigning an id
+// and creation time when missing. The caller saves st.
+func (st *State) AppendArtifact(sessionID, lineageID string, artifact Artifact) (string, error) {
 	session, ok := st.Sessions[sessionID]
 	if !ok {
 		return "", fmt.Errorf("session %q not found", sessionID)
@@ -33,12 +47,13 @@ func AddArtifact(sessionID, lineageID string, artifact Artifact) (string, error)
 		return "", fmt.Errorf("lineage %q not found in session %q", lineageID, sessionID)
 	}
 
+	var err error
 	if strings.TrimSpace(artifact.ID) == "" {
-		artifact.ID, err = newUniqueArtifactID(st)
+		artifact.ID, err = newUniqueArtifactID(*st)
 		if err != nil {
 			return "", fmt.Errorf("find artifact id: %w", err)
 		}
-	} else if artifactIDExists(st, artifact.ID) {
+	} else if artifactIDExists(*st, artifact.ID) {
 		return "", fmt.Errorf("artifact id %q already exists", artifact.ID)
 	}
 	if strings.TrimSpace(artifact.CreatedAt) == "" {
@@ -48,10 +63,6 @@ func AddArtifact(sessionID, lineageID string, artifact Artifact) (string, error)
 	lineage.Artifacts = append(lineage.Artifacts, artifact)
 	session.Lineages[lineageKey] = lineage
 	st.Sessions[sessionID] = session
-
-	if err := Save("", st); err != nil {
-		return "", fmt.Errorf("save state: %w", err)
-	}
 	return artifact.ID, nil
 }
 
@@ -6,7 +6,8 @@ import (
 	"time"
 )
 
-// EvaluateArtifact stores immutable single-score feedback for one artifact.
+// EvaluateArtifact stores immutable single-score feedback for one artifact and
+// folds it into the artifact's composite score when it was harness-scored.
 func EvaluateArtifact(artifactID string, score int, comment string) error {
 	if score < 1 || score > 10 {
 		return fmt.Errorf("score must be between 1-10")
@@ -33,6 +34,7 @@ func EvaluateArtifact(artifactID string, score int, comment string) error {
 		Comment:     strings.TrimSpace(comment),
 		EvaluatedAt: time.Now().UTC().Format(time.RFC3339),
 	}
+	lineage.Artifacts[location.index].RefreshCompositeScore()
 
 	session.Lineages[location.lineageKey] = lineage
 	st.Sessions[location.sessionID] = session
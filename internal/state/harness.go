@@ -0,0 +1,68 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Perttulands/chiron/internal/harness"
+	"github.com/Perttulands/chiron/internal/scoring"
+)
+
+// SuiteFor picks the test suite that grades a run of the session. An explicit
+// rowID must name a dataset row; otherwise a row whose input matches exactly
+// wins over the session-wide suite. It returns nil when nothing applies.
+func (s Session) SuiteFor(input, rowID string) (*harness.TestSuite, *DatasetRow, error) {
+	if s.Harness == nil {
+		if strings.TrimSpace(rowID) != "" {
+			return nil, nil, fmt.Errorf("session %q has no dataset rows", s.ID)
+		}
+		return nil, nil, nil
+	}
+
+	if id := strings.TrimSpace(rowID); id != "" {
+		for i := range s.Harness.Rows {
+			if s.Harness.Rows[i].ID == id {
+				row := s.Harness.Rows[i]
+				return &row.Suite, &row, nil
+			}
+		}
+		return nil, nil, fmt.Errorf("dataset row %q not found in session %q", id, s.ID)
+	}
+
+	for i := range s.Harness.Rows {
+		if s.Harness.Rows[i].Input == input {
+			row := s.Harness.Rows[i]
+			return &row.Suite, &row, nil
+		}
+	}
+	return s.Harness.Suite, nil, nil
+}
+
+// RefreshCompositeScore recomputes CompositeScore from the artifact's harness
+// result, manual evaluation and, when MaxDurationMS is set, efficiency,
+// with the artifact's Weights, so a tournament bout is rescored as its
+// tournament would.
+// Artifacts without a harness result keep a nil composite; their manual
+// score is already the whole story.
+func (a *Artifact) RefreshCompositeScore() {
+	if a.HarnessResult == nil {
+		a.CompositeScore = nil
+		return
+	}
+
+	input := scoring.Input{
+		HarnessResult: a.HarnessResult,
+		DurationMS:    a.ExecutionMetadata.DurationMS,
+		MaxDurationMS: a.MaxDurationMS,
+	}
+	if a.Evaluation != nil {
+		score := a.Evaluation.Score
+		input.ManualScore = &score
+	}
+	weights := scoring.DefaultWeights()
+	if a.Weights != nil {
+		weights = *a.Weights
+	}
+	result := scoring.Score(input, weights)
+	a.CompositeScore = &result
+}
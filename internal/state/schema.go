@@ -1,19 +1,80 @@
 package state
 
+import (
+	"encoding/json"
+
+	"github.com/Perttulands/chiron/internal/harness"
+	"github.com/Perttulands/chiron/internal/scoring"
+)
+
 // State is the root JSON document stored at .chiron/state.json.
 type State struct {
 	Version  string             `json:"version"`
 	Sessions map[string]Session `json:"sessions"`
+	Ratings  map[string]Rating  `json:"ratings,omitempty"` // keyed by agent id
+}
+
+// Rating is an agent's persistent skill estimate, updated from the pairwise
+// outcomes of every tournament round it plays. Elo and TrueSkill are tracked
+// side by side.
+type Rating struct {
+	AgentID   string  `json:"agent_id"`
+	LineageID string  `json:"lineage_id"`
+	Version   int     `json:"version"`
+	Elo       float64 `json:"elo"`
+	Mu        float64 `json:"mu"`    // TrueSkill mean
+	Sigma     float64 `json:"sigma"` // TrueSkill standard deviation
+	Matches   int     `json:"matches"`
+	Wins      int     `json:"wins"`
+	Losses    int     `json:"losses"`
+	Draws     int     `json:"draws"`
+	UpdatedAt string  `json:"updated_at"`
 }
 
 // Session captures one quickstart or training run.
 type Session struct {
-	ID        string             `json:"id"`
-	Mode      string             `json:"mode"`
-	Need      string             `json:"need"`
-	CreatedAt string             `json:"created_at"`
-	Status    string             `json:"status"`
-	Lineages  map[string]Lineage `json:"lineages"`
+	ID          string             `json:"id"`
+	Mode        string             `json:"mode"`
+	Need        string             `json:"need"`
+	CreatedAt   string             `json:"created_at"`
+	Status      string             `json:"status"`
+	Lineages    map[string]Lineage `json:"lineages"`
+	Harness     *SessionHarness    `json:"harness,omitempty"`
+	Tournaments []TournamentRecord `json:"tournaments,omitempty"`
+}
+
+// TournamentRecord stores one tournament played by a session's agents. The
+// full tournament document is kept verbatim in Tournament; bout outputs live
+// in the linked artifacts instead.
+type TournamentRecord struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Status        string          `json:"status"`
+	Format        string          `json:"format,omitempty"`
+	LoopID        string          `json:"loop_id,omitempty"`    // training loop that played it
+	Generation    int             `json:"generation,omitempty"` // training generation number
+	RerunOf       string          `json:"rerun_of,omitempty"`   // tournament this one replayed
+	LineageIDs    []string        `json:"lineage_ids"`
+	ArtifactIDs   []string        `json:"artifact_ids"`
+	WinnerAgentID string          `json:"winner_agent_id,omitempty"`
+	Selected      []string        `json:"selected,omitempty"` // contestant ids kept by selection
+	CreatedAt     string          `json:"created_at"`
+	CompletedAt   string          `json:"completed_at,omitempty"`
+	Tournament    json.RawMessage `json:"tournament"`
+}
+
+// SessionHarness attaches objective checks to a session. Suite applies to
+// every input; Rows pin a suite to one specific input and take precedence.
+type SessionHarness struct {
+	Suite *harness.TestSuite `json:"suite,omitempty"`
+	Rows  []DatasetRow       `json:"rows,omitempty"`
+}
+
+// DatasetRow pairs one input with the suite that grades its output.
+type DatasetRow struct {
+	ID    string            `json:"id"`
+	Input string            `json:"input"`
+	Suite harness.TestSuite `json:"suite"`
 }
 
 // Lineage stores generated agents and their artifacts.
@@ -44,26 +105,80 @@ type AgentDefinition struct {
 	Temperature  float64 `json:"temperature"`
 	MaxTokens    int     `json:"max_tokens"`
 	Tools        []any   `json:"tools"`
+
+	// Sections optionally structures SystemPrompt as named parts; when set,
+	// SystemPrompt is their rendering. See mutation.ParseSections.
+	Sections []PromptSection `json:"sections,omitempty"`
+
+	// Examples are few-shot input/output pairs, rendered into the system
+	// prompt or, with ExampleMode "messages", sent as prior chat turns.
+	Examples    []FewShotExample `json:"examples,omitempty"`
+	ExampleMode string           `json:"example_mode,omitempty"` // prompt (default) or messages
+}
+
+// FewShotExample is one demonstration shown to the agent. Examples drawn
+// from artifacts keep the source artifact and its score.
+type FewShotExample struct {
+	Input      string  `json:"input"`
+	Output     string  `json:"output"`
+	ArtifactID string  `json:"artifact_id,omitempty"`
+	Score      float64 `json:"score,omitempty"`
+}
+
+// PromptSection is one named part of a structured system prompt. Locked
+// sections are never changed by mutation or crossover.
+type PromptSection struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	Locked  bool   `json:"locked,omitempty"`
 }
 
 // GenerationMetadata tracks generation-level observability.
 type GenerationMetadata struct {
-	Provider   string  `json:"provider"`
-	Model      string  `json:"model"`
-	TokensUsed int     `json:"tokens_used"`
-	DurationMS int     `json:"duration_ms"`
-	CostUSD    float64 `json:"cost_usd"`
+	Provider        string           `json:"provider"`
+	Model           string           `json:"model"`
+	TokensUsed      int              `json:"tokens_used"`
+	DurationMS      int              `json:"duration_ms"`
+	CostUSD         float64          `json:"cost_usd"`
+	MutationTargets []MutationTarget `json:"mutation_targets,omitempty"` // failures a failure-driven mutation set out to fix
+
+	// Provenance: how this version was derived. Root agents leave these empty.
+	ParentIDs      []string `json:"parent_ids,omitempty"`      // parent agent first, then any crossover partner
+	Operator       string   `json:"operator,omitempty"`        // mutation operator, or "evolve" for feedback-driven iteration
+	MutationPrompt string   `json:"mutation_prompt,omitempty"` // prompt sent to the generating model
+	Reasoning      string   `json:"reasoning,omitempty"`       // the model's explanation of its changes, when given
+}
+
+// MutationTarget is one observed failure of a parent agent that a mutation
+// was asked to fix: a failing test case, or a low-scoring output when
+// TestCaseID is empty.
+type MutationTarget struct {
+	ChallengeID string  `json:"challenge_id"`
+	TestCaseID  string  `json:"test_case_id,omitempty"`
+	TestName    string  `json:"test_name,omitempty"`
+	Detail      string  `json:"detail,omitempty"` // test failure detail or bout error
+	Output      string  `json:"output,omitempty"` // excerpt of the output that failed
+	Score       float64 `json:"score"`            // composite score of the failing bout
+	Occurrences int     `json:"occurrences"`      // bouts (repetitions) that showed it
 }
 
 // Artifact stores one execution result for an agent.
 type Artifact struct {
-	ID                string            `json:"id"`
-	AgentID           string            `json:"agent_id"`
-	Input             string            `json:"input"`
-	Output            string            `json:"output"`
-	CreatedAt         string            `json:"created_at"`
-	ExecutionMetadata ExecutionMetadata `json:"execution_metadata"`
-	Evaluation        *Evaluation       `json:"evaluation,omitempty"`
+	ID                string               `json:"id"`
+	AgentID           string               `json:"agent_id"`
+	Input             string               `json:"input"`
+	Output            string               `json:"output"`
+	CreatedAt         string               `json:"created_at"`
+	ExecutionMetadata ExecutionMetadata    `json:"execution_metadata"`
+	Evaluation        *Evaluation          `json:"evaluation,omitempty"`
+	DatasetRowID      string               `json:"dataset_row_id,omitempty"`
+	HarnessResult     *harness.SuiteResult `json:"harness_result,omitempty"`
+	CompositeScore    *scoring.Result      `json:"composite_score,omitempty"`
+	TournamentID      string               `json:"tournament_id,omitempty"`
+	ChallengeID       string               `json:"challenge_id,omitempty"`
+	MaxDurationMS     int                  `json:"max_duration_ms,omitempty"` // challenge time budget the composite's efficiency is scored against
+	Weights           *scoring.Weights     `json:"weights,omitempty"`         // composite weights of the owning tournament; nil means scoring.DefaultWeights
+	Error             string               `json:"error,omitempty"`
 }
 
 // ExecutionMetadata tracks runtime signals and tool calls.
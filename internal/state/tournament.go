@@ -0,0 +1,38 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AddTournamentRecord appends a tournament record to a session in st. The
+// caller saves st.
+func (st *State) AddTournamentRecord(sessionID string, record TournamentRecord) error {
+	session, ok := st.Sessions[sessionID]
+	if !ok {
+		return fmt.Errorf("session %q not found", sessionID)
+	}
+	if _, _, err := FindTournamentRecord(*st, record.ID); err == nil {
+		return fmt.Errorf("tournament id %q already exists", record.ID)
+	}
+	session.Tournaments = append(session.Tournaments, record)
+	st.Sessions[sessionID] = session
+	return nil
+}
+
+// FindTournamentRecord returns the tournament record with id and the id of
+// the session that holds it.
+func FindTournamentRecord(st State, tournamentID string) (TournamentRecord, string, error) {
+	targetID := strings.TrimSpace(tournamentID)
+	if targetID == "" {
+		return TournamentRecord{}, "", fmt.Errorf("tournament id is required")
+	}
+	for sessionID, session := range st.Sessions {
+		for _, record := range session.Tournaments {
+			if record.ID == targetID {
+				return record, sessionID, nil
+			}
+		}
+	}
+	return TournamentRecord{}, "", fmt.Errorf("tournament %q not found", targetID)
+}
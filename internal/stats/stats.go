@@ -0,0 +1,210 @@
+// Package stats provides the small set of descriptive statistics and paired
+// significance tests used to compare tournament contestants.
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Mean returns the arithmetic mean of values, or 0 for an empty slice.
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// StdDev returns the sample standard deviation (n-1 denominator), or 0 when
+// fewer than two values are given.
+func StdDev(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	m := Mean(values)
+	var ss float64
+	for _, v := range values {
+		ss += (v - m) * (v - m)
+	}
+	return math.Sqrt(ss / float64(len(values)-1))
+}
+
+// MeanCI returns the two-sided Student-t confidence interval for the mean at
+// the given level (e.g. 0.95). With fewer than two values the interval
+// collapses to the mean.
+func MeanCI(values []float64, level float64) (low, high float64) {
+	m := Mean(values)
+	n := len(values)
+	if n < 2 {
+		return m, m
+	}
+	if level <= 0 || level >= 1 {
+		level = 0.95
+	}
+	half := tQuantile(1-(1-level)/2, n-1) * StdDev(values) / math.Sqrt(float64(n))
+	return m - half, m + half
+}
+
+// tQuantile approximates the p-quantile of Student's t with df degrees of
+// freedom: exact for df 1 and 2, Cornish-Fisher expansion beyond that.
+func tQuantile(p float64, df int) float64 {
+	switch df {
+	case 1:
+		return math.Tan(math.Pi * (p - 0.5))
+	case 2:
+		return (2*p - 1) / math.Sqrt(2*p*(1-p))
+	}
+	z := math.Sqrt2 * math.Erfinv(2*p-1)
+	v := float64(df)
+	z3, z5, z7 := z*z*z, math.Pow(z, 5), math.Pow(z, 7)
+	return z +
+		(z3+z)/(4*v) +
+		(5*z5+16*z3+3*z)/(96*v*v) +
+		(3*z7+19*z5+17*z3-15*z)/(384*v*v*v)
+}
+
+// Wilcoxon runs a two-sided Wilcoxon signed-rank test on paired samples a
+// and b. Zero differences are dropped; tied magnitudes get average ranks. The
+// p-value is exact for up to 50 non-zero pairs and uses the tie-corrected
+// normal approximation beyond that. It returns 1 when no pair differs.
+func Wilcoxon(a, b []float64) float64 {
+	diffs := pairedDiffs(a, b)
+	nonZero := diffs[:0:0]
+	for _, d := range diffs {
+		if d != 0 {
+			nonZero = append(nonZero, d)
+		}
+	}
+	n := len(nonZero)
+	if n == 0 {
+		return 1
+	}
+
+	sort.Slice(nonZero, func(i, j int) bool { return math.Abs(nonZero[i]) < math.Abs(nonZero[j]) })
+	ranks := make([]float64, n)
+	var tieTerm float64
+	for i := 0; i < n; {
+		j := i
+		for j+1 < n && math.Abs(nonZero[j+1]) == math.Abs(nonZero[i]) {
+			j++
+		}
+		avg := float64(i+j+2) / 2
+		for k := i; k <= j; k++ {
+			ranks[k] = avg
+		}
+		t := float64(j - i + 1)
+		tieTerm += t*t*t - t
+		i = j + 1
+	}
+
+	var wPlus float64
+	for i, d := range nonZero {
+		if d > 0 {
+			wPlus += ranks[i]
+		}
+	}
+
+	if n <= 50 {
+		return wilcoxonExact(ranks, wPlus)
+	}
+
+	nf := float64(n)
+	mean := nf * (nf + 1) / 4
+	variance := nf*(nf+1)*(2*nf+1)/24 - tieTerm/48
+	if variance <= 0 {
+		return 1
+	}
+	z := (math.Abs(wPlus-mean) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		z = 0
+	}
+	return math.Min(1, math.Erfc(z/math.Sqrt2))
+}
+
+// wilcoxonExact enumerates the null distribution of W+ over all sign
+// assignments. Ranks are doubled so average ranks stay integral.
+func wilcoxonExact(ranks []float64, wPlus float64) float64 {
+	total := 0
+	doubled := make([]int, len(ranks))
+	for i, r := range ranks {
+		doubled[i] = int(math.Round(2 * r))
+		total += doubled[i]
+	}
+
+	counts := make([]float64, total+1)
+	counts[0] = 1
+	for _, r := range doubled {
+		for s := total; s >= r; s-- {
+			counts[s] += counts[s-r]
+		}
+	}
+
+	observed := int(math.Round(2 * wPlus))
+	mirrored := total - observed
+	lo, hi := observed, mirrored
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	var tail float64
+	for s := 0; s <= lo; s++ {
+		tail += counts[s]
+	}
+	for s := hi; s <= total; s++ {
+		tail += counts[s]
+	}
+	if lo == hi {
+		tail -= counts[lo]
+	}
+	return math.Min(1, tail/math.Pow(2, float64(len(ranks))))
+}
+
+// PairedBootstrap estimates a two-sided p-value for the mean of the paired
+// differences a-b being zero by resampling the differences with replacement.
+// rng makes the estimate reproducible; samples defaults to 2000.
+func PairedBootstrap(a, b []float64, samples int, rng *rand.Rand) float64 {
+	diffs := pairedDiffs(a, b)
+	n := len(diffs)
+	if n == 0 {
+		return 1
+	}
+	observed := Mean(diffs)
+	if observed == 0 {
+		return 1
+	}
+	if samples <= 0 {
+		samples = 2000
+	}
+	if rng == nil {
+		rng = rand.New(rand.NewSource(1))
+	}
+
+	// Count resampled means on the other side of zero from the observed one.
+	opposite := 0
+	for i := 0; i < samples; i++ {
+		var sum float64
+		for j := 0; j < n; j++ {
+			sum += diffs[rng.Intn(n)]
+		}
+		if (observed > 0 && sum <= 0) || (observed < 0 && sum >= 0) {
+			opposite++
+		}
+	}
+	return math.Min(1, 2*float64(opposite+1)/float64(samples+1))
+}
+
+func pairedDiffs(a, b []float64) []float64 {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	diffs := make([]float64, n)
+	for i := 0; i < n; i++ {
+		diffs[i] = a[i] - b[i]
+	}
+	return diffs
+}
@@ -0,0 +1,86 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestMeanCI(t *testing.T) {
+	low, high := MeanCI([]float64{1, 2, 3}, 0.95)
+	// t(0.975, 2) = 4.303; half width 4.303 * 1 / sqrt(3).
+	if math.Abs(low-(2-2.484)) > 0.001 || math.Abs(high-(2+2.484)) > 0.001 {
+		t.Errorf("MeanCI() = [%.3f, %.3f], want [-0.484, 4.484]", low, high)
+	}
+	if low, high := MeanCI([]float64{7}, 0.95); low != 7 || high != 7 {
+		t.Errorf("MeanCI() of one value = [%v, %v], want [7, 7]", low, high)
+	}
+}
+
+func TestWilcoxon(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float64
+		want float64
+	}{
+		{name: "no differences", a: []float64{1, 2, 3}, b: []float64{1, 2, 3}, want: 1},
+		{name: "five all better", a: []float64{2, 4, 6, 8, 10}, b: []float64{1, 2, 3, 4, 5}, want: 2.0 / 32},
+		{name: "six all better", a: []float64{2, 4, 6, 8, 10, 12}, b: []float64{1, 2, 3, 4, 5, 6}, want: 2.0 / 64},
+		{name: "mixed signs", a: []float64{1, 0, 3, 4, 5}, b: []float64{0, 2, 0, 0, 0}, want: 6.0 / 32},
+		{name: "zero differences dropped", a: []float64{2, 4, 6, 8, 10, 7}, b: []float64{1, 2, 3, 4, 5, 7}, want: 2.0 / 32},
+		{name: "symmetric", a: []float64{1, 0}, b: []float64{0, 1}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Wilcoxon(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Wilcoxon() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWilcoxonNormalApproximation(t *testing.T) {
+	a := make([]float64, 60)
+	b := make([]float64, 60)
+	for i := range a {
+		a[i] = float64(i + 1)
+	}
+	if p := Wilcoxon(a, b); p >= 1e-6 {
+		t.Errorf("Wilcoxon() of 60 positive differences = %v, want < 1e-6", p)
+	}
+	for i := range a {
+		if i%2 == 0 {
+			a[i] = -a[i]
+		}
+	}
+	if p := Wilcoxon(a, b); p < 0.5 {
+		t.Errorf("Wilcoxon() of alternating differences = %v, want >= 0.5", p)
+	}
+}
+
+func TestPairedBootstrap(t *testing.T) {
+	better := []float64{3, 4, 5, 6}
+	worse := []float64{1, 2, 3, 4}
+
+	// Every resampled mean stays positive, so only the +1 correction remains.
+	if got, want := PairedBootstrap(better, worse, 999, rand.New(rand.NewSource(7))), 2.0/1000; got != want {
+		t.Errorf("PairedBootstrap() = %v, want %v", got, want)
+	}
+	if got := PairedBootstrap(better, better, 999, nil); got != 1 {
+		t.Errorf("PairedBootstrap() of identical samples = %v, want 1", got)
+	}
+	if got := PairedBootstrap(nil, nil, 999, nil); got != 1 {
+		t.Errorf("PairedBootstrap() of no samples = %v, want 1", got)
+	}
+
+	mixed := []float64{1, -1, 2, -2, 1}
+	zero := make([]float64, len(mixed))
+	first := PairedBootstrap(mixed, zero, 500, rand.New(rand.NewSource(3)))
+	second := PairedBootstrap(mixed, zero, 500, rand.New(rand.NewSource(3)))
+	if first != second {
+		t.Errorf("PairedBootstrap() with one seed = %v then %v, want equal", first, second)
+	}
+	if first < 0.5 {
+		t.Errorf("PairedBootstrap() of near-zero mean difference = %v, want >= 0.5", first)
+	}
+}
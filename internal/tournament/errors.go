@@ -0,0 +1,61 @@
+package tournament
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"os/exec"
+
+	"github.com/Perttulands/chiron/internal/provider"
+)
+
+// Error kinds recorded on failed bouts.
+const (
+	ErrorTimeout  = "timeout"  // the bout ran past its challenge's time limit
+	ErrorProvider = "provider" // the model API, network or host failed
+	ErrorAgent    = "agent"    // the agent's own run failed
+)
+
+// ErrProvider marks an executor error as an infrastructure failure that is
+// not the agent's fault. Executors wrap it: fmt.Errorf("...: %w", ErrProvider).
+var ErrProvider = errors.New("provider failure")
+
+// ClassifyError returns the kind of a bout error. timedOut reports whether
+// the bout's own deadline expired, which makes any error a timeout.
+// Provider errors are API status errors, transport and network failures,
+// missing executables, cancellation of the whole run, and anything wrapping
+// ErrProvider. Everything else is blamed on the agent.
+func ClassifyError(err error, timedOut bool) string {
+	if timedOut {
+		return ErrorTimeout
+	}
+	var statusErr *provider.StatusError
+	var urlErr *url.Error
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrProvider),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, exec.ErrNotFound),
+		errors.As(err, &statusErr),
+		errors.As(err, &urlErr),
+		errors.As(err, &netErr):
+		return ErrorProvider
+	default:
+		return ErrorAgent
+	}
+}
+
+// retryable reports whether a bout that failed with err may succeed if run
+// again: provider failures other than permanent API rejections such as bad
+// credentials or an unknown model.
+func retryable(err error, kind string) bool {
+	if kind != ErrorProvider || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var statusErr *provider.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Retryable()
+	}
+	return true
+}
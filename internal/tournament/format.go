@@ -0,0 +1,492 @@
+package tournament
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Perttulands/chiron/internal/challenge"
+	"github.com/Perttulands/chiron/internal/harness"
+	"github.com/Perttulands/chiron/internal/scoring"
+)
+
+// Format names for Config.Format.
+const (
+	FormatRoundRobin        = "round_robin"
+	FormatSwiss             = "swiss"
+	FormatSingleElimination = "single_elimination"
+	FormatDoubleElimination = "double_elimination"
+	FormatSuccessiveHalving = "successive_halving"
+)
+
+// ValidFormats lists all built-in tournament formats.
+var ValidFormats = []string{FormatRoundRobin, FormatSwiss, FormatSingleElimination, FormatDoubleElimination, FormatSuccessiveHalving}
+
+// Format decides which contestants meet on which challenges. Run plays the
+// tournament through arena and returns its rounds. Formats that rank by
+// progression rather than raw score (Swiss points, bracket depth, halving
+// stage) also return points per contestant id; standings then sort by points
+// first and average score second.
+type Format interface {
+	Run(ctx context.Context, arena *Arena) ([]Round, map[string]float64, error)
+}
+
+// NewFormat returns the built-in format for cfg.Format; "" is round robin.
+func NewFormat(cfg Config) (Format, error) {
+	switch cfg.Format {
+	case "", FormatRoundRobin:
+		return roundRobin{}, nil
+	case FormatSwiss:
+		return swiss{rounds: cfg.SwissRounds}, nil
+	case FormatSingleElimination:
+		return elimination{maxLosses: 1}, nil
+	case FormatDoubleElimination:
+		return elimination{maxLosses: 2}, nil
+	case FormatSuccessiveHalving:
+		return successiveHalving{}, nil
+	default:
+		return nil, fmt.Errorf("unknown tournament format %q; choose from: %v", cfg.Format, ValidFormats)
+	}
+}
+
+// Arena plays bouts on behalf of a Format under the tournament's schedule,
+// grader and weights. Progress is reported cumulatively across Play calls;
+// in multi-stage formats Total grows as later stages are scheduled.
+type Arena struct {
+	Contestants []Contestant          // in seed order
+	Challenges  []challenge.Challenge // in play order
+
+	exec      Executor
+	weights   scoring.Weights
+	grader    harness.Grader
+	sched     Schedule
+	replay    []Round // finished rounds still to be replayed
+	completed int
+	scheduled int
+}
+
+// Play runs contestants on ch as one round. With pairs, each pair's winner is
+// the contestant with the higher mean composite score over its repetitions;
+// equal means are a draw.
+func (a *Arena) Play(ctx context.Context, contestants []Contestant, ch challenge.Challenge, stage int, pairs []Pair) (Round, error) {
+	jobs := appendJobs(nil, contestants, ch, a.sched.repetitions())
+	if round, ok := a.replayRound(contestants, ch, stage); ok {
+		a.scheduled += len(jobs)
+		a.completed += len(round.Bouts)
+		if a.sched.RoundReplayed != nil {
+			a.sched.RoundReplayed(round)
+		}
+		return round, nil
+	}
+
+	sched := a.sched
+	offset := a.completed
+	a.scheduled += len(jobs)
+	if a.sched.Progress != nil {
+		total := a.scheduled
+		report := a.sched.Progress
+		sched.Progress = func(p Progress) {
+			p.Completed += offset
+			p.Total = total
+			report(p)
+		}
+	}
+
+	bouts, done, err := runBouts(ctx, jobs, a.exec, a.weights, a.grader, sched)
+	round := Round{ChallengeID: ch.ID, Stage: stage, Bouts: finishedBouts(bouts, done)}
+	a.completed += len(round.Bouts)
+	if len(pairs) > 0 {
+		round.Pairs = decidePairs(round, pairs)
+	}
+	if err != nil {
+		return round, fmt.Errorf("round %q cancelled: %w", ch.ID, err)
+	}
+	if a.sched.RoundDone != nil {
+		a.sched.RoundDone(round)
+	}
+	return round, nil
+}
+
+// replayRound returns the next resumed round when it is the round about to
+// be played: the same challenge, stage and contestants. On a mismatch the
+// remaining rounds are dropped and play starts afresh from here.
+func (a *Arena) replayRound(contestants []Contestant, ch challenge.Challenge, stage int) (Round, bool) {
+	if len(a.replay) == 0 {
+		return Round{}, false
+	}
+	next := a.replay[0]
+	played := map[string]bool{}
+	for _, b := range next.Bouts {
+		played[b.ContestantID] = true
+	}
+	match := next.ChallengeID == ch.ID && next.Stage == stage && len(played) == len(contestants)
+	for _, c := range contestants {
+		match = match && played[c.ID]
+	}
+	if !match {
+		a.replay = nil
+		return Round{}, false
+	}
+	a.replay = a.replay[1:]
+	return next, true
+}
+
+// meanScores returns each contestant's mean composite score in a round,
+// ignoring infrastructure failures.
+func meanScores(round Round) map[string]float64 {
+	totals := map[string]float64{}
+	counts := map[string]int{}
+	for _, bout := range round.Bouts {
+		if bout.InfraFailure() {
+			continue
+		}
+		totals[bout.ContestantID] += bout.CompositeScore.FinalScore
+		counts[bout.ContestantID]++
+	}
+	means := make(map[string]float64, len(totals))
+	for id, total := range totals {
+		means[id] = total / float64(counts[id])
+	}
+	return means
+}
+
+// decidePairs fills in pair winners from the round's bouts. A pair where
+// either contestant has no scored bout (infrastructure failure or
+// cancellation) is marked NoResult rather than forfeited, so the failure
+// costs neither side.
+func decidePairs(round Round, pairs []Pair) []Pair {
+	means := meanScores(round)
+	decided := make([]Pair, 0, len(pairs))
+	for _, p := range pairs {
+		a, okA := means[p.A]
+		b, okB := means[p.B]
+		switch {
+		case !okA || !okB:
+			p.NoResult = true
+		case a > b:
+			p.Winner = p.A
+		case b > a:
+			p.Winner = p.B
+		}
+		decided = append(decided, p)
+	}
+	return decided
+}
+
+// ceilLog2 returns the number of halvings needed to get n down to one.
+func ceilLog2(n int) int {
+	k := 0
+	for size := 1; size < n; size *= 2 {
+		k++
+	}
+	return k
+}
+
+// roundRobin plays every contestant on every challenge.
+type roundRobin struct{}
+
+func (roundRobin) Run(ctx context.Context, arena *Arena) ([]Round, map[string]float64, error) {
+	if len(arena.replay) == 0 && arena.sched.RoundDone == nil {
+		rounds, err := RunAll(ctx, arena.Contestants, arena.Challenges, arena.exec, arena.weights, arena.grader, arena.sched)
+		return rounds, nil, err
+	}
+
+	// Rounds are reported or replayed one at a time, so play challenge by
+	// challenge rather than pooling every bout.
+	rounds := make([]Round, 0, len(arena.Challenges))
+	for _, ch := range arena.Challenges {
+		round, err := arena.Play(ctx, arena.Contestants, ch, 0, nil)
+		rounds = append(rounds, round)
+		if err != nil {
+			return rounds, nil, err
+		}
+	}
+	return rounds, nil, nil
+}
+
+// swiss plays a fixed number of rounds, each pairing contestants with similar
+// match points who have not met yet. A win is worth one point, a draw half,
+// and a bye one. Challenges are used in order, cycling if there are fewer
+// challenges than rounds.
+type swiss struct {
+	rounds int // default: ceil(log2(contestants))
+}
+
+func (f swiss) Run(ctx context.Context, arena *Arena) ([]Round, map[string]float64, error) {
+	numRounds := f.rounds
+	if numRounds <= 0 {
+		numRounds = ceilLog2(len(arena.Contestants))
+	}
+
+	seed := map[string]int{}
+	byID := map[string]Contestant{}
+	for i, c := range arena.Contestants {
+		seed[c.ID] = i
+		byID[c.ID] = c
+	}
+	points := map[string]float64{}
+	met := map[[2]string]bool{}
+	hadBye := map[string]bool{}
+	rounds := []Round{}
+
+	for r := 0; r < numRounds; r++ {
+		ranked := make([]Contestant, len(arena.Contestants))
+		copy(ranked, arena.Contestants)
+		sort.SliceStable(ranked, func(i, j int) bool {
+			return points[ranked[i].ID] > points[ranked[j].ID]
+		})
+
+		// The lowest-ranked contestant without a bye sits out an odd round.
+		if len(ranked)%2 == 1 {
+			byeIndex := len(ranked) - 1
+			for i := len(ranked) - 1; i >= 0; i-- {
+				if !hadBye[ranked[i].ID] {
+					byeIndex = i
+					break
+				}
+			}
+			bye := ranked[byeIndex]
+			hadBye[bye.ID] = true
+			points[bye.ID]++
+			ranked = append(ranked[:byeIndex], ranked[byeIndex+1:]...)
+		}
+
+		pairs := swissPairs(ranked, met, seed)
+		players := make([]Contestant, 0, len(ranked))
+		for _, p := range pairs {
+			players = append(players, byID[p.A], byID[p.B])
+		}
+
+		ch := arena.Challenges[r%len(arena.Challenges)]
+		round, err := arena.Play(ctx, players, ch, r+1, pairs)
+		rounds = append(rounds, round)
+		for _, p := range round.Pairs {
+			met[pairKey(p.A, p.B)] = true
+			switch {
+			case p.NoResult:
+			case p.Winner == "":
+				points[p.A] += 0.5
+				points[p.B] += 0.5
+			default:
+				points[p.Winner]++
+			}
+		}
+		if err != nil {
+			return rounds, points, err
+		}
+	}
+	return rounds, points, nil
+}
+
+// swissPairs pairs ranked contestants top-down, each with the next
+// contestant it has not met; if it has met everyone left, the rematch is
+// allowed. The better-seeded contestant of each pair is A.
+func swissPairs(ranked []Contestant, met map[[2]string]bool, seed map[string]int) []Pair {
+	pairs := []Pair{}
+	used := make([]bool, len(ranked))
+	for i := range ranked {
+		if used[i] {
+			continue
+		}
+		opponent := -1
+		for j := i + 1; j < len(ranked); j++ {
+			if used[j] {
+				continue
+			}
+			if opponent < 0 {
+				opponent = j
+			}
+			if !met[pairKey(ranked[i].ID, ranked[j].ID)] {
+				opponent = j
+				break
+			}
+		}
+		if opponent < 0 {
+			break
+		}
+		used[i], used[opponent] = true, true
+		a, b := ranked[i].ID, ranked[opponent].ID
+		if seed[b] < seed[a] {
+			a, b = b, a
+		}
+		pairs = append(pairs, Pair{A: a, B: b})
+	}
+	return pairs
+}
+
+func pairKey(a, b string) [2]string {
+	if b < a {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
+
+// elimination runs a single (maxLosses 1) or double (maxLosses 2)
+// elimination bracket. Each stage pairs contestants with the same number of
+// losses, best seed against worst, on the next challenge; the best seed of an
+// odd group gets a bye, and a drawn match goes to the better seed. Points
+// record the stage in which a contestant was knocked out, so deeper runs rank
+// higher and the champion ranks first.
+type elimination struct {
+	maxLosses int
+}
+
+func (f elimination) Run(ctx context.Context, arena *Arena) ([]Round, map[string]float64, error) {
+	seed := map[string]int{}
+	for i, c := range arena.Contestants {
+		seed[c.ID] = i
+	}
+	alive := make([]Contestant, len(arena.Contestants))
+	copy(alive, arena.Contestants)
+	losses := map[string]int{}
+	points := map[string]float64{}
+	rounds := []Round{}
+
+	for stage := 1; len(alive) > 1; stage++ {
+		pairs := []Pair{}
+		leftovers := []Contestant{}
+		for l := 0; l < f.maxLosses; l++ {
+			group := []Contestant{}
+			for _, c := range alive {
+				if losses[c.ID] == l {
+					group = append(group, c)
+				}
+			}
+			groupPairs, bye := foldPairs(group)
+			pairs = append(pairs, groupPairs...)
+			if bye != nil {
+				leftovers = append(leftovers, *bye)
+			}
+		}
+		// Only lone contestants remain in each loss group (the grand final
+		// of a double elimination): let them meet across groups.
+		if len(pairs) == 0 {
+			pairs, _ = foldPairs(leftovers)
+		}
+
+		players := []Contestant{}
+		byID := map[string]Contestant{}
+		for _, c := range alive {
+			byID[c.ID] = c
+		}
+		for _, p := range pairs {
+			players = append(players, byID[p.A], byID[p.B])
+		}
+
+		ch := arena.Challenges[(stage-1)%len(arena.Challenges)]
+		round, err := arena.Play(ctx, players, ch, stage, pairs)
+		rounds = append(rounds, round)
+		if err != nil {
+			return rounds, points, err
+		}
+		// Pairs without a result are replayed in the next stage; a stage
+		// that decides nothing would repeat forever.
+		decided := 0
+		for _, p := range round.Pairs {
+			if p.NoResult {
+				continue
+			}
+			decided++
+			loser := p.B
+			if p.Winner == p.B {
+				loser = p.A
+			}
+			losses[loser]++
+			if losses[loser] >= f.maxLosses {
+				points[loser] = float64(stage)
+			}
+		}
+		if decided == 0 {
+			return rounds, points, fmt.Errorf("stage %d: no match could be decided", stage)
+		}
+
+		remaining := alive[:0]
+		for _, c := range alive {
+			if losses[c.ID] < f.maxLosses {
+				remaining = append(remaining, c)
+			}
+		}
+		alive = remaining
+		if len(alive) == 1 {
+			points[alive[0].ID] = float64(stage + 1)
+		}
+	}
+	return rounds, points, nil
+}
+
+// foldPairs pairs a seed-ordered group best against worst. With an odd
+// count the best seed is returned as the bye.
+func foldPairs(group []Contestant) ([]Pair, *Contestant) {
+	var bye *Contestant
+	if len(group)%2 == 1 {
+		first := group[0]
+		bye = &first
+		group = group[1:]
+	}
+	pairs := make([]Pair, 0, len(group)/2)
+	for i, j := 0, len(group)-1; i < j; i, j = i+1, j-1 {
+		pairs = append(pairs, Pair{A: group[i].ID, B: group[j].ID})
+	}
+	return pairs, bye
+}
+
+// successiveHalving splits the challenges into ceil(log2(contestants))
+// stages. After every stage but the last, only the better half of the
+// surviving contestants (by mean score so far) plays on. Points record the
+// last stage a contestant played.
+type successiveHalving struct{}
+
+func (successiveHalving) Run(ctx context.Context, arena *Arena) ([]Round, map[string]float64, error) {
+	stages := ceilLog2(len(arena.Contestants))
+	if stages < 1 {
+		stages = 1
+	}
+	if stages > len(arena.Challenges) {
+		stages = len(arena.Challenges)
+	}
+
+	alive := make([]Contestant, len(arena.Contestants))
+	copy(alive, arena.Contestants)
+	totals := map[string]float64{}
+	counts := map[string]int{}
+	points := map[string]float64{}
+	rounds := []Round{}
+
+	next := 0
+	for stage := 1; stage <= stages; stage++ {
+		// Spread the challenges as evenly as possible over the stages.
+		end := next + (len(arena.Challenges)-next)/(stages-stage+1)
+		for _, ch := range arena.Challenges[next:end] {
+			round, err := arena.Play(ctx, alive, ch, stage, nil)
+			rounds = append(rounds, round)
+			for _, bout := range round.Bouts {
+				if bout.InfraFailure() {
+					continue
+				}
+				totals[bout.ContestantID] += bout.CompositeScore.FinalScore
+				counts[bout.ContestantID]++
+			}
+			if err != nil {
+				return rounds, points, err
+			}
+		}
+		next = end
+
+		for _, c := range alive {
+			points[c.ID] = float64(stage)
+		}
+		if stage == stages {
+			break
+		}
+
+		mean := func(id string) float64 {
+			if counts[id] == 0 {
+				return 0
+			}
+			return totals[id] / float64(counts[id])
+		}
+		sort.SliceStable(alive, func(i, j int) bool { return mean(alive[i].ID) > mean(alive[j].ID) })
+		alive = alive[:(len(alive)+1)/2]
+	}
+	return rounds, points, nil
+}
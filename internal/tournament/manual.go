@@ -0,0 +1,60 @@
+package tournament
+
+import (
+	"fmt"
+
+	"github.com/Perttulands/chiron/internal/scoring"
+)
+
+// ApplyManualScores folds reviewer scores (1-10, keyed by artifact id) into
+// the composite score of every recorded bout they name, then recomputes the
+// standings. Points and pair winners of paired formats are kept as played.
+// It returns how many bouts were rescored.
+func ApplyManualScores(t *Tournament, scores map[string]int) (int, error) {
+	return ApplyBoutScores(t, func(bout Bout) (int, bool) {
+		score, ok := scores[bout.ArtifactID]
+		return score, ok && bout.ArtifactID != ""
+	})
+}
+
+// ApplyBoutScores is ApplyManualScores with the reviewer score of each bout
+// looked up by score, e.g. from a run manifest whose bouts were never
+// stored as artifacts.
+func ApplyBoutScores(t *Tournament, score func(Bout) (int, bool)) (int, error) {
+	if t.Status != StatusComplete {
+		return 0, fmt.Errorf("tournament %q is %s, not complete", t.ID, t.Status)
+	}
+	maxDuration := map[string]int{}
+	for _, ch := range t.Challenges {
+		maxDuration[ch.ID] = ch.MaxDurationMS
+	}
+
+	applied := 0
+	for i := range t.Rounds {
+		for j := range t.Rounds[i].Bouts {
+			bout := &t.Rounds[i].Bouts[j]
+			manual, ok := score(*bout)
+			if !ok || bout.InfraFailure() {
+				continue
+			}
+			harnessResult := bout.HarnessResult
+			bout.CompositeScore = scoring.Score(scoring.Input{
+				HarnessResult: &harnessResult,
+				ManualScore:   &manual,
+				DurationMS:    bout.DurationMS,
+				MaxDurationMS: maxDuration[bout.ChallengeID],
+			}, t.Weights)
+			applied++
+		}
+	}
+	if applied == 0 {
+		return 0, nil
+	}
+
+	points := map[string]float64{}
+	for _, s := range t.Standings {
+		points[s.ContestantID] = s.Points
+	}
+	t.Standings = computeStandings(t.Contestants, t.Rounds, t.Ranking, points)
+	return applied, nil
+}
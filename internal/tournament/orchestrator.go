@@ -7,49 +7,77 @@ import (
 	"time"
 
 	"github.com/Perttulands/chiron/internal/challenge"
+	"github.com/Perttulands/chiron/internal/harness"
 	"github.com/Perttulands/chiron/internal/scoring"
 )
 
 // Status tracks the lifecycle of a tournament.
 const (
-	StatusPending   = "pending"
-	StatusRunning   = "running"
-	StatusScoring   = "scoring"
-	StatusComplete  = "complete"
-	StatusFailed    = "failed"
+	StatusPending  = "pending"
+	StatusRunning  = "running"
+	StatusScoring  = "scoring"
+	StatusComplete = "complete"
+	StatusFailed   = "failed"
 )
 
 // Tournament represents a full competition between prompt variants.
 type Tournament struct {
-	ID           string              `json:"id"`
-	Name         string              `json:"name"`
-	Status       string              `json:"status"`
-	Contestants  []Contestant        `json:"contestants"`
-	Challenges   []challenge.Challenge `json:"challenges"`
-	Rounds       []Round             `json:"rounds"`
-	Standings    []Standing          `json:"standings"`
-	Weights      scoring.Weights     `json:"weights"`
-	CreatedAt    string              `json:"created_at"`
-	CompletedAt  string              `json:"completed_at,omitempty"`
-	DurationMS   int                 `json:"duration_ms"`
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	Status      string                `json:"status"`
+	Contestants []Contestant          `json:"contestants"`
+	Challenges  []challenge.Challenge `json:"challenges"`
+	Rounds      []Round               `json:"rounds"`
+	Standings   []Standing            `json:"standings"`
+	Weights     scoring.Weights       `json:"weights"`
+	Format      string                `json:"format,omitempty"`
+	SwissRounds int                   `json:"swiss_rounds,omitempty"`
+	Schedule    Schedule              `json:"schedule"`
+	Ranking     Ranking               `json:"ranking"`
+	CreatedAt   string                `json:"created_at"`
+	CompletedAt string                `json:"completed_at,omitempty"`
+	DurationMS  int                   `json:"duration_ms"`
+
+	grader harness.Grader
+	format Format
+	resume []Round
 }
 
 // Standing captures a contestant's aggregate tournament performance.
 type Standing struct {
-	ContestantID string  `json:"contestant_id"`
-	LineageID    string  `json:"lineage_id"`
-	TotalScore   float64 `json:"total_score"`
-	AvgScore     float64 `json:"avg_score"`
-	BoutsPlayed  int     `json:"bouts_played"`
-	BoutsWon     int     `json:"bouts_won"`
-	Rank         int     `json:"rank"`
+	ContestantID string   `json:"contestant_id"`
+	LineageID    string   `json:"lineage_id"`
+	TotalScore   float64  `json:"total_score"`
+	AvgScore     float64  `json:"avg_score"`
+	BoutsPlayed  int      `json:"bouts_played"`
+	BoutsWon     int      `json:"bouts_won"`
+	BoutsFailed  int      `json:"bouts_failed,omitempty"` // infrastructure failures, excluded from the scores
+	Rank         int      `json:"rank"`
+	Points       float64  `json:"points,omitempty"` // format points (Swiss, bracket depth, halving stage)
+	StdDev       float64  `json:"std_dev"`
+	CILow        float64  `json:"ci_low"` // confidence interval on AvgScore
+	CIHigh       float64  `json:"ci_high"`
+	PValueNext   *float64 `json:"p_value_next,omitempty"` // paired test against the next-ranked contestant
+	Tied         bool     `json:"tied,omitempty"`         // shares Rank with the contestant above
 }
 
 // Config controls tournament creation.
 type Config struct {
-	Name    string
-	Weights scoring.Weights
-	IDFunc  func(string) string
+	Name     string
+	Weights  scoring.Weights
+	IDFunc   func(string) string
+	Grader   harness.Grader // judges llm_rubric test cases; optional
+	Schedule Schedule       // bout concurrency, repetitions and progress reporting
+	Ranking  Ranking        // confidence intervals and significance testing
+
+	Format      string // round_robin (default), swiss, single_elimination, double_elimination, successive_halving
+	SwissRounds int    // rounds for the swiss format; default ceil(log2(contestants))
+
+	// Resume holds the rounds an interrupted run of the same tournament
+	// finished, in play order. They are replayed instead of played again;
+	// formats are deterministic given their results, so play continues
+	// with the first unfinished round.
+	Resume []Round
 }
 
 // New creates a tournament in pending state.
@@ -60,6 +88,13 @@ func New(cfg Config, contestants []Contestant, challenges []challenge.Challenge)
 	if len(challenges) == 0 {
 		return nil, fmt.Errorf("tournament requires at least 1 challenge")
 	}
+	if err := cfg.Ranking.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid ranking: %w", err)
+	}
+	format, err := NewFormat(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	id := cfg.IDFunc("trn")
 	name := cfg.Name
@@ -76,11 +111,18 @@ func New(cfg Config, contestants []Contestant, challenges []challenge.Challenge)
 		Rounds:      []Round{},
 		Standings:   []Standing{},
 		Weights:     cfg.Weights,
+		Format:      cfg.Format,
+		SwissRounds: cfg.SwissRounds,
+		Schedule:    cfg.Schedule,
+		Ranking:     cfg.Ranking,
 		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+		grader:      cfg.Grader,
+		format:      format,
+		resume:      cfg.Resume,
 	}, nil
 }
 
-// Run executes the full tournament: all rounds, then computes standings.
+// Run executes the full tournament in its format, then computes standings.
 func (t *Tournament) Run(ctx context.Context, exec Executor) error {
 	if t.Status != StatusPending {
 		return fmt.Errorf("tournament %q is %s, not pending", t.ID, t.Status)
@@ -89,15 +131,34 @@ func (t *Tournament) Run(ctx context.Context, exec Executor) error {
 	t.Status = StatusRunning
 	start := time.Now()
 
-	rounds, err := RunAll(ctx, t.Contestants, t.Challenges, exec, t.Weights)
+	format := t.format
+	if format == nil {
+		// Tournaments decoded from JSON carry only the format name.
+		var err error
+		if format, err = NewFormat(Config{Format: t.Format, SwissRounds: t.SwissRounds}); err != nil {
+			t.Status = StatusFailed
+			return err
+		}
+	}
+	arena := &Arena{
+		Contestants: t.Contestants,
+		Challenges:  t.Challenges,
+		exec:        exec,
+		weights:     t.Weights,
+		grader:      t.grader,
+		sched:       t.Schedule,
+		replay:      t.resume,
+	}
+	rounds, points, err := format.Run(ctx, arena)
 	if err != nil {
+		t.Rounds = rounds
 		t.Status = StatusFailed
 		return fmt.Errorf("run tournament: %w", err)
 	}
 
 	t.Rounds = rounds
 	t.Status = StatusScoring
-	t.Standings = computeStandings(t.Contestants, t.Rounds)
+	t.Standings = computeStandings(t.Contestants, t.Rounds, t.Ranking, points)
 	t.Status = StatusComplete
 	t.DurationMS = int(time.Since(start).Milliseconds())
 	t.CompletedAt = time.Now().UTC().Format(time.RFC3339)
@@ -127,30 +188,61 @@ func (t *Tournament) TopN(n int) []Standing {
 	return t.Standings[:n]
 }
 
-func computeStandings(contestants []Contestant, rounds []Round) []Standing {
+// computeStandings aggregates bouts per contestant, leaving out bouts that
+// failed for infrastructure reasons. With points (paired and staged formats)
+// contestants rank by points first and average score second.
+func computeStandings(contestants []Contestant, rounds []Round, ranking Ranking, points map[string]float64) []Standing {
 	scores := map[string]*Standing{}
+	samples := map[string]map[string]float64{}
 
 	for _, c := range contestants {
 		scores[c.ID] = &Standing{
 			ContestantID: c.ID,
 			LineageID:    c.LineageID,
+			Points:       points[c.ID],
 		}
+		samples[c.ID] = map[string]float64{}
 	}
 
-	// For each round, find the round winner
+	// For each round, find the round winner by mean score over repetitions
 	for _, round := range rounds {
-		var bestScore float64
-		var bestID string
+		roundTotals := map[string]float64{}
+		roundCounts := map[string]int{}
+		order := []string{}
 		for _, bout := range round.Bouts {
 			s := scores[bout.ContestantID]
 			if s == nil {
 				continue
 			}
+			if bout.InfraFailure() {
+				s.BoutsFailed++
+				continue
+			}
 			s.TotalScore += bout.CompositeScore.FinalScore
 			s.BoutsPlayed++
-			if bout.CompositeScore.FinalScore > bestScore {
-				bestScore = bout.CompositeScore.FinalScore
-				bestID = bout.ContestantID
+			samples[bout.ContestantID][sampleKey(round.Stage, round.ChallengeID, bout.Repetition)] = bout.CompositeScore.FinalScore
+			if roundCounts[bout.ContestantID] == 0 {
+				order = append(order, bout.ContestantID)
+			}
+			roundTotals[bout.ContestantID] += bout.CompositeScore.FinalScore
+			roundCounts[bout.ContestantID]++
+		}
+
+		if len(round.Pairs) > 0 {
+			for _, p := range round.Pairs {
+				if s := scores[p.Winner]; s != nil {
+					s.BoutsWon++
+				}
+			}
+			continue
+		}
+
+		var bestScore float64
+		var bestID string
+		for _, id := range order {
+			if mean := roundTotals[id] / float64(roundCounts[id]); mean > bestScore {
+				bestScore = mean
+				bestID = id
 			}
 		}
 		if bestID != "" {
@@ -167,15 +259,27 @@ func computeStandings(contestants []Contestant, rounds []Round) []Standing {
 	}
 
 	sort.Slice(standings, func(i, j int) bool {
+		if standings[i].Points != standings[j].Points {
+			return standings[i].Points > standings[j].Points
+		}
+		// Contestants whose every bout failed for infrastructure reasons were
+		// never measured; they go last rather than tying on a zero average.
+		if (standings[i].BoutsPlayed == 0) != (standings[j].BoutsPlayed == 0) {
+			return standings[j].BoutsPlayed == 0
+		}
 		if standings[i].AvgScore != standings[j].AvgScore {
 			return standings[i].AvgScore > standings[j].AvgScore
 		}
-		return standings[i].BoutsWon > standings[j].BoutsWon
+		if standings[i].BoutsWon != standings[j].BoutsWon {
+			return standings[i].BoutsWon > standings[j].BoutsWon
+		}
+		return standings[i].ContestantID < standings[j].ContestantID
 	})
 
 	for i := range standings {
 		standings[i].Rank = i + 1
 	}
+	applySignificance(standings, samples, ranking)
 
 	return standings
 }
@@ -0,0 +1,128 @@
+package tournament
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/Perttulands/chiron/internal/stats"
+)
+
+// Significance test names for Ranking.Test.
+const (
+	TestWilcoxon  = "wilcoxon"
+	TestBootstrap = "bootstrap"
+)
+
+// Ranking controls the statistics attached to standings. Adjacent
+// contestants are compared with a paired test over the bouts they both
+// played, matched by stage, challenge and repetition.
+type Ranking struct {
+	Test             string  `json:"test,omitempty"`              // "wilcoxon" (default) or "bootstrap"
+	Alpha            float64 `json:"alpha,omitempty"`             // significance level; default 0.05
+	Confidence       float64 `json:"confidence,omitempty"`        // confidence interval level; default 0.95
+	TieInsignificant bool    `json:"tie_insignificant,omitempty"` // share ranks when the difference is not significant
+	BootstrapSamples int     `json:"bootstrap_samples,omitempty"` // default 2000
+	Seed             int64   `json:"seed,omitempty"`              // bootstrap RNG seed; default 1
+}
+
+// Validate checks that the ranking options are usable.
+func (r Ranking) Validate() error {
+	switch r.Test {
+	case "", TestWilcoxon, TestBootstrap:
+	default:
+		return fmt.Errorf("unknown significance test %q; choose from: %s, %s", r.Test, TestWilcoxon, TestBootstrap)
+	}
+	if r.Alpha < 0 || r.Alpha >= 1 {
+		return fmt.Errorf("alpha must be in [0, 1)")
+	}
+	if r.Confidence < 0 || r.Confidence >= 1 {
+		return fmt.Errorf("confidence must be in [0, 1)")
+	}
+	return nil
+}
+
+func (r Ranking) alpha() float64 {
+	if r.Alpha <= 0 {
+		return 0.05
+	}
+	return r.Alpha
+}
+
+// sampleKey identifies one bout for pairing samples across contestants.
+// Multi-stage formats replay challenges, so the stage is part of the key.
+func sampleKey(stage int, challengeID string, repetition int) string {
+	return fmt.Sprintf("%d/%s#%d", stage, challengeID, repetition)
+}
+
+// applySignificance fills in confidence intervals and adjacent-rank p-values
+// on standings, which must already be sorted best first. With
+// TieInsignificant, a contestant not significantly worse than the one above
+// it takes the same rank, unless format points already separate them.
+func applySignificance(standings []Standing, samples map[string]map[string]float64, ranking Ranking) {
+	for i := range standings {
+		values := sampleValues(samples[standings[i].ContestantID])
+		standings[i].StdDev = stats.StdDev(values)
+		standings[i].CILow, standings[i].CIHigh = stats.MeanCI(values, ranking.Confidence)
+	}
+
+	var rng *rand.Rand
+	if ranking.Test == TestBootstrap {
+		seed := ranking.Seed
+		if seed == 0 {
+			seed = 1
+		}
+		rng = rand.New(rand.NewSource(seed))
+	}
+
+	for i := 0; i+1 < len(standings); i++ {
+		a, b := pairedSamples(samples[standings[i].ContestantID], samples[standings[i+1].ContestantID])
+		if len(a) == 0 {
+			continue
+		}
+		var p float64
+		if ranking.Test == TestBootstrap {
+			p = stats.PairedBootstrap(a, b, ranking.BootstrapSamples, rng)
+		} else {
+			p = stats.Wilcoxon(a, b)
+		}
+		standings[i].PValueNext = &p
+
+		if ranking.TieInsignificant && p >= ranking.alpha() && standings[i].Points == standings[i+1].Points {
+			standings[i+1].Rank = standings[i].Rank
+			standings[i+1].Tied = true
+		}
+	}
+}
+
+func sampleValues(byKey map[string]float64) []float64 {
+	keys := make([]string, 0, len(byKey))
+	for k := range byKey {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([]float64, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, byKey[k])
+	}
+	return values
+}
+
+// pairedSamples returns the scores of two contestants on the bouts both
+// played, in a stable key order.
+func pairedSamples(x, y map[string]float64) ([]float64, []float64) {
+	keys := make([]string, 0, len(x))
+	for k := range x {
+		if _, ok := y[k]; ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	a := make([]float64, 0, len(keys))
+	b := make([]float64, 0, len(keys))
+	for _, k := range keys {
+		a = append(a, x[k])
+		b = append(b, y[k])
+	}
+	return a, b
+}
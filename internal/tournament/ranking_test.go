@@ -0,0 +1,99 @@
+package tournament
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSampleKey(t *testing.T) {
+	keys := map[string]bool{}
+	for _, k := range []string{
+		sampleKey(0, "c1", 0),
+		sampleKey(1, "c1", 0),
+		sampleKey(0, "c1", 1),
+		sampleKey(0, "c2", 0),
+	} {
+		if keys[k] {
+			t.Fatalf("sampleKey() repeated %q", k)
+		}
+		keys[k] = true
+	}
+	if sampleKey(2, "c1", 3) != sampleKey(2, "c1", 3) {
+		t.Error("sampleKey() is not stable")
+	}
+}
+
+func TestPairedSamples(t *testing.T) {
+	x := map[string]float64{"b": 2, "a": 1, "only-x": 9}
+	y := map[string]float64{"a": 5, "only-y": 9, "b": 6}
+
+	a, b := pairedSamples(x, y)
+	if want := []float64{1, 2}; !reflect.DeepEqual(a, want) {
+		t.Errorf("pairedSamples() a = %v, want %v", a, want)
+	}
+	if want := []float64{5, 6}; !reflect.DeepEqual(b, want) {
+		t.Errorf("pairedSamples() b = %v, want %v", b, want)
+	}
+	if a, _ := pairedSamples(x, map[string]float64{"z": 1}); len(a) != 0 {
+		t.Errorf("pairedSamples() without common bouts = %v, want none", a)
+	}
+}
+
+func TestApplySignificance(t *testing.T) {
+	// best clearly beats mid on six bouts (exact Wilcoxon p = 1/32); mid
+	// and low differ by one point either way (p = 1).
+	samples := map[string]map[string]float64{"best": {}, "mid": {}, "low": {}}
+	for i, challenge := range []string{"c1", "c2", "c3", "c4", "c5", "c6"} {
+		key := sampleKey(0, challenge, 0)
+		samples["best"][key] = 9
+		samples["mid"][key] = 5
+		samples["low"][key] = 5 + float64(i%2*2-1)
+	}
+	standings := func() []Standing {
+		return []Standing{
+			{ContestantID: "best", Rank: 1},
+			{ContestantID: "mid", Rank: 2},
+			{ContestantID: "low", Rank: 3},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		ranking  Ranking
+		points   []float64
+		wantRank []int
+		wantTied []bool
+	}{
+		{name: "ties off", ranking: Ranking{}, wantRank: []int{1, 2, 3}, wantTied: []bool{false, false, false}},
+		{name: "insignificant tied", ranking: Ranking{TieInsignificant: true}, wantRank: []int{1, 2, 2}, wantTied: []bool{false, false, true}},
+		{name: "points separate", ranking: Ranking{TieInsignificant: true}, points: []float64{2, 1, 0}, wantRank: []int{1, 2, 3}, wantTied: []bool{false, false, false}},
+		{name: "bootstrap", ranking: Ranking{Test: TestBootstrap, TieInsignificant: true}, wantRank: []int{1, 2, 2}, wantTied: []bool{false, false, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := standings()
+			for i, p := range tt.points {
+				got[i].Points = p
+			}
+			applySignificance(got, samples, tt.ranking)
+
+			for i, s := range got {
+				if s.Rank != tt.wantRank[i] || s.Tied != tt.wantTied[i] {
+					t.Errorf("%s: rank %d tied %v, want rank %d tied %v", s.ContestantID, s.Rank, s.Tied, tt.wantRank[i], tt.wantTied[i])
+				}
+			}
+			if p := got[0].PValueNext; p == nil || *p >= 0.05 {
+				t.Errorf("best vs mid p = %v, want < 0.05", p)
+			}
+			if p := got[1].PValueNext; p == nil || *p < 0.05 {
+				t.Errorf("mid vs low p = %v, want >= 0.05", p)
+			}
+			if got[2].PValueNext != nil {
+				t.Errorf("last contestant has p = %v, want none", *got[2].PValueNext)
+			}
+			if got[0].CILow != 9 || got[0].CIHigh != 9 || got[2].StdDev == 0 {
+				t.Errorf("best CI [%v, %v], low std dev %v; want [9, 9] and a spread", got[0].CILow, got[0].CIHigh, got[2].StdDev)
+			}
+		})
+	}
+}
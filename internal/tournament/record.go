@@ -0,0 +1,220 @@
+package tournament
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Perttulands/chiron/internal/state"
+)
+
+// ExecutionModeTournament marks artifacts created from tournament bouts.
+const ExecutionModeTournament = "tournament"
+
+// RecordOptions links a stored tournament to where it came from.
+type RecordOptions struct {
+	SessionID  string
+	LoopID     string   // training loop, if any
+	Generation int      // training generation number, if any
+	RerunOf    string   // tournament id this one replayed, if any
+	Selected   []string // contestant ids kept by selection, if any
+}
+
+// Record stores t in st under opts.SessionID. Every scored bout of a
+// contestant whose lineage belongs to the session becomes an artifact on
+// that lineage, carrying the bout's harness result and composite score; the
+// stored tournament's bouts then point at their artifacts instead of
+// repeating the output. Bouts already stored by RecordBouts keep their
+// artifact. Bouts of other contestants, and infrastructure failures, keep
+// their output inline. The caller saves st.
+func Record(st *state.State, t *Tournament, opts RecordOptions) (state.TournamentRecord, error) {
+	b, err := newBoutRecorder(st, t, opts.SessionID)
+	if err != nil {
+		return state.TournamentRecord{}, err
+	}
+	sessionLineages := b.lineages
+	contestants := b.contestants
+
+	stored := *t
+	stored.Rounds = make([]Round, len(t.Rounds))
+	artifactIDs := []string{}
+
+	for i, round := range t.Rounds {
+		round.Bouts = append([]Bout(nil), round.Bouts...)
+		for j, bout := range round.Bouts {
+			if bout.ArtifactID != "" {
+				round.Bouts[j].Output = ""
+				artifactIDs = append(artifactIDs, bout.ArtifactID)
+				continue
+			}
+			artifactID, err := b.store(bout)
+			if err != nil {
+				return state.TournamentRecord{}, err
+			}
+			if artifactID == "" {
+				continue
+			}
+			round.Bouts[j].ArtifactID = artifactID
+			round.Bouts[j].Output = ""
+			artifactIDs = append(artifactIDs, artifactID)
+		}
+		stored.Rounds[i] = round
+	}
+
+	document, err := json.Marshal(stored)
+	if err != nil {
+		return state.TournamentRecord{}, fmt.Errorf("encode tournament %q: %w", t.ID, err)
+	}
+
+	record := state.TournamentRecord{
+		ID:          t.ID,
+		Name:        t.Name,
+		Status:      t.Status,
+		Format:      t.Format,
+		LoopID:      opts.LoopID,
+		Generation:  opts.Generation,
+		RerunOf:     opts.RerunOf,
+		LineageIDs:  []string{},
+		ArtifactIDs: artifactIDs,
+		Selected:    opts.Selected,
+		CreatedAt:   t.CreatedAt,
+		CompletedAt: t.CompletedAt,
+		Tournament:  document,
+	}
+	seen := map[string]bool{}
+	for _, c := range t.Contestants {
+		if sessionLineages[c.Agent.LineageID] && !seen[c.Agent.LineageID] {
+			seen[c.Agent.LineageID] = true
+			record.LineageIDs = append(record.LineageIDs, c.Agent.LineageID)
+		}
+	}
+	if winner, err := t.Winner(); err == nil {
+		record.WinnerAgentID = contestants[winner.ContestantID].Agent.ID
+	}
+
+	if err := st.AddTournamentRecord(opts.SessionID, record); err != nil {
+		return state.TournamentRecord{}, err
+	}
+	return record, nil
+}
+
+// RecordBouts stores the scored bouts of the given contestants as artifacts
+// in st, as Record would, before the tournament itself is recorded, e.g. so
+// they can be evaluated by hand. The bouts keep their output and point at
+// their new artifacts; a later Record reuses them. The caller saves st.
+func RecordBouts(st *state.State, t *Tournament, sessionID string, contestantIDs []string) ([]string, error) {
+	b, err := newBoutRecorder(st, t, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	wanted := map[string]bool{}
+	for _, id := range contestantIDs {
+		wanted[id] = true
+	}
+
+	artifactIDs := []string{}
+	for i := range t.Rounds {
+		for j := range t.Rounds[i].Bouts {
+			bout := &t.Rounds[i].Bouts[j]
+			if !wanted[bout.ContestantID] {
+				continue
+			}
+			if bout.ArtifactID == "" {
+				if bout.ArtifactID, err = b.store(*bout); err != nil {
+					return nil, err
+				}
+			}
+			if bout.ArtifactID != "" {
+				artifactIDs = append(artifactIDs, bout.ArtifactID)
+			}
+		}
+	}
+	return artifactIDs, nil
+}
+
+// boutRecorder turns the bouts of one tournament into artifacts.
+type boutRecorder struct {
+	st          *state.State
+	t           *Tournament
+	sessionID   string
+	lineages    map[string]bool
+	contestants map[string]Contestant
+	inputs      map[string]string
+	maxDuration map[string]int
+	createdAt   string
+}
+
+func newBoutRecorder(st *state.State, t *Tournament, sessionID string) (*boutRecorder, error) {
+	session, ok := st.Sessions[sessionID]
+	if !ok {
+		return nil, fmt.Errorf("session %q not found", sessionID)
+	}
+	b := &boutRecorder{
+		st:          st,
+		t:           t,
+		sessionID:   sessionID,
+		lineages:    map[string]bool{},
+		contestants: map[string]Contestant{},
+		inputs:      map[string]string{},
+		maxDuration: map[string]int{},
+		createdAt:   t.CompletedAt,
+	}
+	for _, lineage := range session.Lineages {
+		b.lineages[lineage.ID] = true
+	}
+	for _, c := range t.Contestants {
+		b.contestants[c.ID] = c
+	}
+	for _, ch := range t.Challenges {
+		b.inputs[ch.ID] = ch.Input
+		b.maxDuration[ch.ID] = ch.MaxDurationMS
+	}
+	if b.createdAt == "" {
+		b.createdAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	return b, nil
+}
+
+// store appends bout as an artifact and returns its id, or "" when the bout
+// is not stored: another session's contestant or an infrastructure failure.
+func (b *boutRecorder) store(bout Bout) (string, error) {
+	c, ok := b.contestants[bout.ContestantID]
+	if !ok || !b.lineages[c.Agent.LineageID] || bout.InfraFailure() {
+		return "", nil
+	}
+	harnessResult := bout.HarnessResult
+	compositeScore := bout.CompositeScore
+	weights := b.t.Weights
+	artifactID, err := b.st.AppendArtifact(b.sessionID, c.Agent.LineageID, state.Artifact{
+		AgentID:   c.Agent.ID,
+		Input:     b.inputs[bout.ChallengeID],
+		Output:    bout.Output,
+		CreatedAt: b.createdAt,
+		ExecutionMetadata: state.ExecutionMetadata{
+			Mode:       ExecutionModeTournament,
+			DurationMS: bout.DurationMS,
+			ToolCalls:  []state.ToolCall{},
+		},
+		HarnessResult:  &harnessResult,
+		CompositeScore: &compositeScore,
+		TournamentID:   b.t.ID,
+		ChallengeID:    bout.ChallengeID,
+		MaxDurationMS:  b.maxDuration[bout.ChallengeID],
+		Weights:        &weights,
+		Error:          bout.Error,
+	})
+	if err != nil {
+		return "", fmt.Errorf("store bout %s/%s: %w", bout.ContestantID, bout.ChallengeID, err)
+	}
+	return artifactID, nil
+}
+
+// FromRecord decodes the tournament stored in record. Bout outputs stay in
+// their artifacts; Bout.ArtifactID points at them.
+func FromRecord(record state.TournamentRecord) (*Tournament, error) {
+	var t Tournament
+	if err := json.Unmarshal(record.Tournament, &t); err != nil {
+		return nil, fmt.Errorf("decode tournament %q: %w", record.ID, err)
+	}
+	return &t, nil
+}
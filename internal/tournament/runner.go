@@ -2,7 +2,9 @@ package tournament
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/Perttulands/chiron/internal/challenge"
@@ -13,55 +15,146 @@ import (
 
 // Contestant represents one prompt variant competing in a tournament.
 type Contestant struct {
-	ID         string                `json:"id"`
-	LineageID  string                `json:"lineage_id"`
-	Agent      state.Agent           `json:"agent"`
+	ID        string      `json:"id"`
+	LineageID string      `json:"lineage_id"`
+	Agent     state.Agent `json:"agent"`
 }
 
 // Bout is the result of one contestant against one challenge.
 type Bout struct {
-	ContestantID string              `json:"contestant_id"`
-	ChallengeID  string              `json:"challenge_id"`
-	Output       string              `json:"output"`
-	HarnessResult harness.SuiteResult `json:"harness_result"`
-	CompositeScore scoring.Result     `json:"composite_score"`
-	DurationMS   int                 `json:"duration_ms"`
-	Error        string              `json:"error,omitempty"`
+	ContestantID   string              `json:"contestant_id"`
+	ChallengeID    string              `json:"challenge_id"`
+	Repetition     int                 `json:"repetition,omitempty"` // 1-based when Schedule.Repetitions > 1
+	Output         string              `json:"output"`
+	HarnessResult  harness.SuiteResult `json:"harness_result"`
+	CompositeScore scoring.Result      `json:"composite_score"`
+	DurationMS     int                 `json:"duration_ms"`
+	Error          string              `json:"error,omitempty"`
+	ErrorKind      string              `json:"error_kind,omitempty"`  // timeout, provider or agent; see ClassifyError
+	Attempts       int                 `json:"attempts,omitempty"`    // executions including retries, when more than one
+	ArtifactID     string              `json:"artifact_id,omitempty"` // stored artifact holding Output, once recorded
+	TokensIn       int                 `json:"tokens_in,omitempty"`   // summed over attempts, as reported with ReportUsage
+	TokensOut      int                 `json:"tokens_out,omitempty"`
+	CostUSD        float64             `json:"cost_usd,omitempty"`
 }
 
-// Round groups all bouts for one challenge.
+// InfraFailure reports whether the bout failed for infrastructure reasons.
+// Such bouts say nothing about the agent, so standings and ratings skip them.
+func (b Bout) InfraFailure() bool {
+	return b.ErrorKind == ErrorProvider
+}
+
+// Round groups all bouts for one challenge. Paired formats (Swiss and
+// elimination brackets) record head-to-head matches in Pairs; otherwise every
+// contestant in the round competes against every other.
 type Round struct {
 	ChallengeID string `json:"challenge_id"`
+	Stage       int    `json:"stage,omitempty"` // 1-based round/stage number in multi-stage formats
 	Bouts       []Bout `json:"bouts"`
+	Pairs       []Pair `json:"pairs,omitempty"`
+}
+
+// Pair is one head-to-head match inside a round. Winner is empty for a draw
+// or, with NoResult, when a side has no scored bout.
+type Pair struct {
+	A        string `json:"a"`
+	B        string `json:"b"`
+	Winner   string `json:"winner,omitempty"`
+	NoResult bool   `json:"no_result,omitempty"` // a side failed for infrastructure reasons or was cancelled; nobody wins or loses
 }
 
 // Executor is the function signature for running an agent on an input.
+// Executors that know what a call consumed report it with ReportUsage.
 type Executor func(ctx context.Context, agent state.AgentDefinition, input string) (output string, durationMS int, err error)
 
+// Usage is the tokens and cost of one execution.
+type Usage struct {
+	TokensIn  int
+	TokensOut int
+	CostUSD   float64
+}
+
+type usageKey struct{}
+
+// usageRecorder collects the usage reported during one bout attempt.
+type usageRecorder struct {
+	mu    sync.Mutex
+	usage Usage
+}
+
+// ReportUsage adds u to the bout running under ctx. Outside a bout it does
+// nothing, so executors can call it unconditionally.
+func ReportUsage(ctx context.Context, u Usage) {
+	rec, ok := ctx.Value(usageKey{}).(*usageRecorder)
+	if !ok {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.usage.TokensIn += u.TokensIn
+	rec.usage.TokensOut += u.TokensOut
+	rec.usage.CostUSD += u.CostUSD
+}
+
 // RunBout executes one contestant against one challenge and scores the result.
-func RunBout(ctx context.Context, contestant Contestant, ch challenge.Challenge, exec Executor, weights scoring.Weights) Bout {
+// grader judges llm_rubric test cases and may be nil. When the challenge sets
+// MaxDurationMS the executor is cancelled after twice that budget.
+func RunBout(ctx context.Context, contestant Contestant, ch challenge.Challenge, exec Executor, weights scoring.Weights, grader harness.Grader) Bout {
+	bout, _ := runBout(ctx, contestant, ch, exec, weights, grader, DefaultTimeoutFactor)
+	return bout
+}
+
+// runBout is RunBout with an explicit timeout factor. It also returns the
+// executor error so the scheduler can decide whether to retry.
+func runBout(ctx context.Context, contestant Contestant, ch challenge.Challenge, exec Executor, weights scoring.Weights, grader harness.Grader, timeoutFactor float64) (Bout, error) {
+	boutCtx := ctx
+	if ch.MaxDurationMS > 0 && timeoutFactor > 0 {
+		var cancel context.CancelFunc
+		timeout := time.Duration(float64(ch.MaxDurationMS) * timeoutFactor * float64(time.Millisecond))
+		boutCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	rec := &usageRecorder{}
+	boutCtx = context.WithValue(boutCtx, usageKey{}, rec)
+
 	start := time.Now()
-	output, durationMS, err := exec(ctx, contestant.Agent.Definition, ch.Input)
+	output, durationMS, err := exec(boutCtx, contestant.Agent.Definition, ch.Input)
 	if durationMS == 0 {
 		durationMS = int(time.Since(start).Milliseconds())
 	}
 
+	rec.mu.Lock()
+	usage := rec.usage
+	rec.mu.Unlock()
 	bout := Bout{
 		ContestantID: contestant.ID,
 		ChallengeID:  ch.ID,
 		DurationMS:   durationMS,
+		TokensIn:     usage.TokensIn,
+		TokensOut:    usage.TokensOut,
+		CostUSD:      usage.CostUSD,
 	}
 
 	if err != nil {
+		timedOut := ctx.Err() == nil && errors.Is(boutCtx.Err(), context.DeadlineExceeded)
 		bout.Error = err.Error()
+		bout.ErrorKind = ClassifyError(err, timedOut)
 		bout.CompositeScore = scoring.Score(scoring.Input{}, weights)
 		bout.HarnessResult = harness.SuiteResult{SuiteID: ch.TestSuite.ID}
-		return bout
+		return bout, err
 	}
 
 	bout.Output = output
-	harnessResult := harness.RunSuite(ch.TestSuite, output)
+	harnessResult, err := harness.RunSuite(ctx, ch.TestSuite, output, grader)
 	bout.HarnessResult = harnessResult
+	if err != nil {
+		// The judge failed, not the agent: the bout goes unscored.
+		err = fmt.Errorf("grade output: %w: %w", ErrProvider, err)
+		bout.Error = err.Error()
+		bout.ErrorKind = ErrorProvider
+		bout.CompositeScore = scoring.Score(scoring.Input{}, weights)
+		return bout, err
+	}
 
 	bout.CompositeScore = scoring.Score(scoring.Input{
 		HarnessResult: &harnessResult,
@@ -69,24 +162,30 @@ func RunBout(ctx context.Context, contestant Contestant, ch challenge.Challenge,
 		MaxDurationMS: ch.MaxDurationMS,
 	}, weights)
 
-	return bout
+	return bout, nil
 }
 
-// RunRound executes all contestants against one challenge.
-func RunRound(ctx context.Context, contestants []Contestant, ch challenge.Challenge, exec Executor, weights scoring.Weights) Round {
-	bouts := make([]Bout, 0, len(contestants))
-	for _, c := range contestants {
-		bout := RunBout(ctx, c, ch, exec, weights)
-		bouts = append(bouts, bout)
-	}
-	return Round{
-		ChallengeID: ch.ID,
-		Bouts:       bouts,
+// RunRound executes all contestants against one challenge under sched.
+// Bouts are returned in contestant order, repetitions adjacent. If ctx is
+// cancelled the round holds only the bouts that finished and the context
+// error is returned.
+func RunRound(ctx context.Context, contestants []Contestant, ch challenge.Challenge, exec Executor, weights scoring.Weights, grader harness.Grader, sched Schedule) (Round, error) {
+	jobs := appendJobs(make([]boutJob, 0, len(contestants)*sched.repetitions()), contestants, ch, sched.repetitions())
+
+	bouts, done, err := runBouts(ctx, jobs, exec, weights, grader, sched)
+	round := Round{ChallengeID: ch.ID, Bouts: finishedBouts(bouts, done)}
+	if err != nil {
+		return round, fmt.Errorf("round %q cancelled: %w", ch.ID, err)
 	}
+	return round, nil
 }
 
-// RunAll executes all contestants against all challenges.
-func RunAll(ctx context.Context, contestants []Contestant, challenges []challenge.Challenge, exec Executor, weights scoring.Weights) ([]Round, error) {
+// RunAll executes all contestants against all challenges. Bouts from every
+// round share one worker pool bounded by sched; rounds keep challenge order
+// and bouts keep contestant and repetition order regardless of completion
+// order. If ctx is cancelled, the finished bouts are returned with the
+// context error.
+func RunAll(ctx context.Context, contestants []Contestant, challenges []challenge.Challenge, exec Executor, weights scoring.Weights, grader harness.Grader, sched Schedule) ([]Round, error) {
 	if len(contestants) == 0 {
 		return nil, fmt.Errorf("no contestants")
 	}
@@ -94,10 +193,31 @@ func RunAll(ctx context.Context, contestants []Contestant, challenges []challeng
 		return nil, fmt.Errorf("no challenges")
 	}
 
-	rounds := make([]Round, 0, len(challenges))
+	perRound := len(contestants) * sched.repetitions()
+	jobs := make([]boutJob, 0, perRound*len(challenges))
 	for _, ch := range challenges {
-		round := RunRound(ctx, contestants, ch, exec, weights)
-		rounds = append(rounds, round)
+		jobs = appendJobs(jobs, contestants, ch, sched.repetitions())
+	}
+
+	bouts, done, err := runBouts(ctx, jobs, exec, weights, grader, sched)
+
+	rounds := make([]Round, 0, len(challenges))
+	for i, ch := range challenges {
+		lo, hi := i*perRound, (i+1)*perRound
+		rounds = append(rounds, Round{ChallengeID: ch.ID, Bouts: finishedBouts(bouts[lo:hi], done[lo:hi])})
+	}
+	if err != nil {
+		return rounds, fmt.Errorf("tournament cancelled: %w", err)
 	}
 	return rounds, nil
 }
+
+func finishedBouts(bouts []Bout, done []bool) []Bout {
+	out := make([]Bout, 0, len(bouts))
+	for i, bout := range bouts {
+		if done[i] {
+			out = append(out, bout)
+		}
+	}
+	return out
+}
@@ -0,0 +1,217 @@
+package tournament
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Perttulands/chiron/internal/challenge"
+	"github.com/Perttulands/chiron/internal/harness"
+	"github.com/Perttulands/chiron/internal/scoring"
+)
+
+// Schedule controls how bouts are dispatched. The zero value runs bouts one
+// at a time, which matches the historical sequential behavior.
+type Schedule struct {
+	Concurrency  int            `json:"concurrency,omitempty"`   // max bouts in flight; <= 1 is sequential
+	Repetitions  int            `json:"repetitions,omitempty"`   // bouts per contestant x challenge; <= 1 is one
+	ProviderCaps map[string]int `json:"provider_caps,omitempty"` // max in-flight bouts per provider name
+	Progress     ProgressFunc   `json:"-"`                       // called after every finished bout
+
+	// Provider names the provider the executor runs every bout on, which
+	// ProviderCaps then limit. When empty, each contestant's bouts count
+	// against the provider that generated its agent.
+	Provider string `json:"provider,omitempty"`
+
+	// RoundDone, if set, is called after every round is played, in play
+	// order. Rounds replayed from Config.Resume are not reported again.
+	// Round robin then plays one challenge at a time instead of pooling
+	// the bouts of every challenge.
+	RoundDone func(Round) `json:"-"`
+	// RoundReplayed, if set, is called for every round taken from
+	// Config.Resume instead of being played. Replay stops at the first
+	// resumed round that no longer matches, so these rounds are the prefix
+	// of Config.Resume still in effect.
+	RoundReplayed func(Round) `json:"-"`
+
+	// BoutContext, if set, derives the context each bout of a contestant
+	// runs and is graded under, e.g. to attribute its provider calls.
+	BoutContext func(context.Context, Contestant) context.Context `json:"-"`
+
+	// TimeoutFactor scales Challenge.MaxDurationMS into the hard per-bout
+	// timeout; default 2, where the efficiency score bottoms out. Negative
+	// disables timeouts.
+	TimeoutFactor float64 `json:"timeout_factor,omitempty"`
+	// Retries is how many extra attempts a bout gets after a retryable
+	// provider failure. Timeouts and agent errors are never retried.
+	Retries        int `json:"retries,omitempty"`
+	RetryBackoffMS int `json:"retry_backoff_ms,omitempty"` // delay before the first retry, doubled after each; default 1000
+}
+
+// DefaultTimeoutFactor is the per-bout timeout as a multiple of the
+// challenge's MaxDurationMS.
+const DefaultTimeoutFactor = 2.0
+
+func (s Schedule) timeoutFactor() float64 {
+	if s.TimeoutFactor == 0 {
+		return DefaultTimeoutFactor
+	}
+	return s.TimeoutFactor
+}
+
+func (s Schedule) retryBackoff() time.Duration {
+	if s.RetryBackoffMS <= 0 {
+		return time.Second
+	}
+	return time.Duration(s.RetryBackoffMS) * time.Millisecond
+}
+
+// Progress reports one finished bout. Callbacks run on the scheduling
+// goroutine, one at a time, so they may render to a terminal without locking.
+type Progress struct {
+	Completed int  `json:"completed"`
+	Total     int  `json:"total"`
+	Bout      Bout `json:"bout"`
+}
+
+// ProgressFunc receives progress updates from RunRound and RunAll.
+type ProgressFunc func(Progress)
+
+// boutJob is one contestant x challenge pairing awaiting execution.
+type boutJob struct {
+	contestant Contestant
+	challenge  challenge.Challenge
+	repetition int
+}
+
+// repetitions returns the number of bouts per pairing, at least one.
+func (s Schedule) repetitions() int {
+	if s.Repetitions < 1 {
+		return 1
+	}
+	return s.Repetitions
+}
+
+// appendJobs adds every repetition of every contestant against ch, in
+// contestant order. Repetitions are numbered from 1 only when there are
+// several, so single-shot bouts serialize as before.
+func appendJobs(jobs []boutJob, contestants []Contestant, ch challenge.Challenge, reps int) []boutJob {
+	for _, c := range contestants {
+		for r := 1; r <= reps; r++ {
+			job := boutJob{contestant: c, challenge: ch}
+			if reps > 1 {
+				job.repetition = r
+			}
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+type boutDone struct {
+	index int
+	bout  Bout
+}
+
+// contestantProvider names the provider a contestant's bouts count against
+// for ProviderCaps: the executing provider when known, otherwise the
+// provider that generated its agent.
+func (s Schedule) contestantProvider(c Contestant) string {
+	if name := strings.TrimSpace(s.Provider); name != "" {
+		return name
+	}
+	return strings.TrimSpace(c.Agent.GenerationMetadata.Provider)
+}
+
+// runJob runs one bout, retrying retryable provider failures up to
+// sched.Retries times with exponential backoff.
+func runJob(ctx context.Context, job boutJob, exec Executor, weights scoring.Weights, grader harness.Grader, sched Schedule) Bout {
+	backoff := sched.retryBackoff()
+	if sched.BoutContext != nil {
+		ctx = sched.BoutContext(ctx, job.contestant)
+	}
+	var spent Usage
+	for attempt := 1; ; attempt++ {
+		bout, err := runBout(ctx, job.contestant, job.challenge, exec, weights, grader, sched.timeoutFactor())
+		bout.Repetition = job.repetition
+		if attempt > 1 {
+			bout.Attempts = attempt
+		}
+		// Failed attempts were paid for too.
+		spent.TokensIn += bout.TokensIn
+		spent.TokensOut += bout.TokensOut
+		spent.CostUSD += bout.CostUSD
+		bout.TokensIn, bout.TokensOut, bout.CostUSD = spent.TokensIn, spent.TokensOut, spent.CostUSD
+		if err == nil || attempt > sched.Retries || !retryable(err, bout.ErrorKind) {
+			return bout
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return bout
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// runBouts executes jobs on a worker pool bounded by sched and returns the
+// bouts in job order. done[i] reports whether job i ran; jobs are only
+// skipped when ctx is cancelled, in which case ctx.Err() is returned after
+// in-flight bouts have finished.
+func runBouts(ctx context.Context, jobs []boutJob, exec Executor, weights scoring.Weights, grader harness.Grader, sched Schedule) ([]Bout, []bool, error) {
+	bouts := make([]Bout, len(jobs))
+	done := make([]bool, len(jobs))
+
+	limit := sched.Concurrency
+	if limit < 1 {
+		limit = 1
+	}
+
+	pending := make([]int, len(jobs))
+	for i := range pending {
+		pending[i] = i
+	}
+	results := make(chan boutDone)
+	inFlight := 0
+	perProvider := map[string]int{}
+	completed := 0
+
+	for len(pending) > 0 || inFlight > 0 {
+		// Dispatch the earliest pending jobs that fit under both limits, so
+		// a saturated provider never blocks bouts bound for another one.
+		if ctx.Err() == nil {
+			for i := 0; i < len(pending) && inFlight < limit; {
+				index := pending[i]
+				name := sched.contestantProvider(jobs[index].contestant)
+				if capacity, ok := sched.ProviderCaps[name]; ok && capacity > 0 && perProvider[name] >= capacity {
+					i++
+					continue
+				}
+				pending = append(pending[:i], pending[i+1:]...)
+				inFlight++
+				perProvider[name]++
+				go func(index int, job boutJob) {
+					results <- boutDone{index: index, bout: runJob(ctx, job, exec, weights, grader, sched)}
+				}(index, jobs[index])
+			}
+		}
+		if inFlight == 0 {
+			break
+		}
+
+		result := <-results
+		inFlight--
+		perProvider[sched.contestantProvider(jobs[result.index].contestant)]--
+		bouts[result.index] = result.bout
+		done[result.index] = true
+		completed++
+		if sched.Progress != nil {
+			sched.Progress(Progress{Completed: completed, Total: len(jobs), Bout: result.bout})
+		}
+	}
+
+	return bouts, done, ctx.Err()
+}
@@ -0,0 +1,120 @@
+package training
+
+import (
+	"strings"
+
+	"github.com/Perttulands/chiron/internal/challenge"
+	"github.com/Perttulands/chiron/internal/cost"
+	"github.com/Perttulands/chiron/internal/harness"
+	"github.com/Perttulands/chiron/internal/tournament"
+)
+
+// Reasons a loop stopped, recorded in Loop.StopReason.
+const (
+	StopMaxGenerations = "max_generations"
+	StopTargetScore    = "target_score"
+	StopBudget         = "budget"      // paused; resume with a larger budget
+	StopInterrupted    = "interrupted" // paused mid-generation; resume replays its finished rounds
+)
+
+// judgeTokens is the assumed size of a grading call beyond the output it
+// grades: the rubric and instructions in, a short verdict out.
+const judgeTokens = 200
+
+// RecordSpend adds what the latest generation, including the mutation that
+// followed it, cost to that generation and to the loop's totals.
+func (l *Loop) RecordSpend(costUSD float64, tokens int) {
+	l.SpentUSD += costUSD
+	l.SpentTokens += tokens
+	if len(l.Generations) > 0 {
+		gen := &l.Generations[len(l.Generations)-1]
+		gen.CostUSD += costUSD
+		gen.Tokens += tokens
+	}
+}
+
+// StopForBudget pauses the loop because its budget is spent. A paused loop
+// can be resumed from its checkpoint with a larger budget.
+func (l *Loop) StopForBudget() {
+	l.Status = StatusPaused
+	l.StopReason = StopBudget
+}
+
+// ProjectGeneration estimates the spend of the next generation on
+// challenges, or on the curriculum's share of them: the tournament as
+// ProjectTournament projects it, and a mutation call per contestant
+// selection will replace. Providers and models come from Config.Providers,
+// falling back to each agent's model.
+// When the previous generation recorded a higher spend, its spend is
+// projected instead.
+func (l *Loop) ProjectGeneration(challenges []challenge.Challenge) cost.Projection {
+	roles := map[string]ProviderIdentity{}
+	for _, p := range l.Config.Providers {
+		roles[p.Role] = p
+	}
+	mutator := roles[RoleMutator]
+	p := ProjectTournament(l.Contestants, l.curriculumChallenges(challenges), l.Config.Schedule.Repetitions, roles[RoleExecutor], roles[RoleGrader])
+
+	if children := len(l.Contestants) - l.Config.SelectionCount; children > 0 && len(l.Contestants) > 0 {
+		def := l.Contestants[0].Agent.Definition
+		promptTokens := cost.EstimateTokens(def.SystemPrompt)
+		p.AddCalls(children, mutator.Provider, orDefault(mutator.Model, def.Model), 2*promptTokens+judgeTokens, promptTokens+judgeTokens)
+	}
+
+	if n := len(l.Generations); n > 0 {
+		last := l.Generations[n-1]
+		if last.CostUSD > p.CostUSD {
+			p.CostUSD = last.CostUSD
+		}
+		if last.Tokens > p.Tokens() {
+			p.TokensOut += last.Tokens - p.Tokens()
+		}
+	}
+	return p
+}
+
+// ProjectTournament estimates the spend of a tournament: every contestant
+// on every challenge and repetition (the round-robin upper bound), plus a
+// grading call per llm_rubric test case. Empty provider models fall back to
+// each agent's model.
+func ProjectTournament(contestants []tournament.Contestant, challenges []challenge.Challenge, repetitions int, executor, grader ProviderIdentity) cost.Projection {
+	repetitions = max(repetitions, 1)
+	var p cost.Projection
+	for _, c := range contestants {
+		def := c.Agent.Definition
+		var prompt strings.Builder
+		prompt.WriteString(def.SystemPrompt)
+		for _, ex := range def.Examples {
+			prompt.WriteString(ex.Input)
+			prompt.WriteString(ex.Output)
+		}
+		promptTokens := cost.EstimateTokens(prompt.String())
+		outTokens := def.MaxTokens
+		if outTokens <= 0 {
+			outTokens = cost.DefaultOutputTokens
+		}
+		model := orDefault(executor.Model, def.Model)
+		for _, ch := range challenges {
+			p.AddCalls(repetitions, executor.Provider, model, promptTokens+cost.EstimateTokens(ch.Input), outTokens)
+			p.AddCalls(repetitions*rubricCases(ch.TestSuite), grader.Provider, orDefault(grader.Model, model), outTokens+judgeTokens, judgeTokens)
+		}
+	}
+	return p
+}
+
+func rubricCases(suite harness.TestSuite) int {
+	n := 0
+	for _, tc := range suite.TestCases {
+		if strings.EqualFold(strings.TrimSpace(tc.Type), harness.TypeLLMRubric) {
+			n++
+		}
+	}
+	return n
+}
+
+func orDefault(value, fallback string) string {
+	if strings.TrimSpace(value) != "" {
+		return strings.TrimSpace(value)
+	}
+	return strings.TrimSpace(fallback)
+}
@@ -0,0 +1,209 @@
+package training
+
+import (
+	"sort"
+
+	"github.com/Perttulands/chiron/internal/challenge"
+	"github.com/Perttulands/chiron/internal/tournament"
+)
+
+// Curriculum defaults.
+const (
+	DefaultPromoteScore = 7.0 // population average on a tier that unlocks the next
+	DefaultFailScore    = 5.0 // best contestant mean below which a challenge counts as failed
+)
+
+// CurriculumConfig schedules challenges by difficulty. The zero value
+// plays every challenge every generation.
+type CurriculumConfig struct {
+	Enabled         bool    `json:"enabled,omitempty"`
+	PromoteScore    float64 `json:"promote_score,omitempty"`    // population average on the current tier that promotes to the next; default DefaultPromoteScore
+	FailScore       float64 `json:"fail_score,omitempty"`       // a challenge fails when no contestant averages this on it; default DefaultFailScore
+	ResurfaceFailed bool    `json:"resurface_failed,omitempty"` // replay failed challenges of easier tiers until they pass
+}
+
+func (c CurriculumConfig) promoteScore() float64 {
+	if c.PromoteScore <= 0 {
+		return DefaultPromoteScore
+	}
+	return c.PromoteScore
+}
+
+func (c CurriculumConfig) failScore() float64 {
+	if c.FailScore <= 0 {
+		return DefaultFailScore
+	}
+	return c.FailScore
+}
+
+// CurriculumState is where a loop stands in its curriculum.
+type CurriculumState struct {
+	Tier   string   `json:"tier"`             // difficulty being trained on
+	Failed []string `json:"failed,omitempty"` // challenges that failed the last time they were played, sorted
+}
+
+// challengeDifficulty returns ch's difficulty; challenges without one are
+// medium, as the generator defaults them.
+func challengeDifficulty(ch challenge.Challenge) string {
+	if ch.Difficulty == "" {
+		return challenge.DifficultyMedium
+	}
+	return ch.Difficulty
+}
+
+func difficultyRank(difficulty string) int {
+	for i, d := range challenge.ValidDifficulties {
+		if d == difficulty {
+			return i
+		}
+	}
+	return len(challenge.ValidDifficulties)
+}
+
+// tiers returns the difficulties present in challenges, easiest first.
+func tiers(challenges []challenge.Challenge) []string {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, ch := range challenges {
+		if d := challengeDifficulty(ch); !seen[d] {
+			seen[d] = true
+			out = append(out, d)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return difficultyRank(out[i]) < difficultyRank(out[j]) })
+	return out
+}
+
+// curriculumChallenges returns the challenges the next generation plays:
+// all of them without a curriculum, otherwise the current tier's, preceded
+// by the failed challenges of easier tiers when ResurfaceFailed is set.
+// The curriculum starts on the easiest tier present.
+func (l *Loop) curriculumChallenges(challenges []challenge.Challenge) []challenge.Challenge {
+	if !l.Config.Curriculum.Enabled || len(challenges) == 0 {
+		return challenges
+	}
+	if l.Curriculum == nil || l.Curriculum.Tier == "" {
+		l.Curriculum = &CurriculumState{Tier: tiers(challenges)[0]}
+	}
+
+	failed := map[string]bool{}
+	for _, id := range l.Curriculum.Failed {
+		failed[id] = true
+	}
+	tier := difficultyRank(l.Curriculum.Tier)
+	resurfaced := []challenge.Challenge{}
+	current := []challenge.Challenge{}
+	for _, ch := range challenges {
+		switch rank := difficultyRank(challengeDifficulty(ch)); {
+		case rank == tier:
+			current = append(current, ch)
+		case rank < tier && l.Config.Curriculum.ResurfaceFailed && failed[ch.ID]:
+			resurfaced = append(resurfaced, ch)
+		}
+	}
+	if len(current) == 0 {
+		// The set changed under a resumed loop; train on everything rather
+		// than on nothing.
+		return challenges
+	}
+	return append(resurfaced, current...)
+}
+
+// difficultyScores returns the mean composite score of trn's bouts per
+// challenge difficulty, ignoring infrastructure failures.
+func difficultyScores(trn *tournament.Tournament) map[string]float64 {
+	difficulty := map[string]string{}
+	for _, ch := range trn.Challenges {
+		difficulty[ch.ID] = challengeDifficulty(ch)
+	}
+	totals := map[string]float64{}
+	counts := map[string]int{}
+	for _, round := range trn.Rounds {
+		for _, bout := range round.Bouts {
+			if bout.InfraFailure() {
+				continue
+			}
+			d := difficulty[bout.ChallengeID]
+			totals[d] += bout.CompositeScore.FinalScore
+			counts[d]++
+		}
+	}
+	out := make(map[string]float64, len(totals))
+	for d, total := range totals {
+		out[d] = total / float64(counts[d])
+	}
+	return out
+}
+
+// advanceCurriculum records which of trn's challenges failed and promotes
+// the loop to the next tier present in challenges once the population
+// averages PromoteScore on the current one. It returns the new tier, or ""
+// when the loop stays.
+func (l *Loop) advanceCurriculum(trn *tournament.Tournament, challenges []challenge.Challenge, scores map[string]float64) string {
+	if !l.Config.Curriculum.Enabled || l.Curriculum == nil {
+		return ""
+	}
+	cfg := l.Config.Curriculum
+
+	// A challenge fails when no contestant's mean on it reaches FailScore.
+	totals := map[[2]string]float64{}
+	counts := map[[2]string]int{}
+	for _, round := range trn.Rounds {
+		for _, bout := range round.Bouts {
+			if bout.InfraFailure() {
+				continue
+			}
+			key := [2]string{bout.ChallengeID, bout.ContestantID}
+			totals[key] += bout.CompositeScore.FinalScore
+			counts[key]++
+		}
+	}
+	// best holds a challenge once any contestant was scored on it, so one
+	// every contestant scored 0 on counts as played and fails.
+	best := map[string]float64{}
+	for key, total := range totals {
+		mean := total / float64(counts[key])
+		if prev, ok := best[key[0]]; !ok || mean > prev {
+			best[key[0]] = mean
+		}
+	}
+	failed := map[string]bool{}
+	for _, id := range l.Curriculum.Failed {
+		failed[id] = true
+	}
+	for _, ch := range trn.Challenges {
+		if _, played := best[ch.ID]; !played {
+			continue
+		}
+		failed[ch.ID] = best[ch.ID] < cfg.failScore()
+	}
+	l.Curriculum.Failed = l.Curriculum.Failed[:0]
+	for id, f := range failed {
+		if f {
+			l.Curriculum.Failed = append(l.Curriculum.Failed, id)
+		}
+	}
+	sort.Strings(l.Curriculum.Failed)
+
+	score, played := scores[l.Curriculum.Tier]
+	if !played || score < cfg.promoteScore() {
+		return ""
+	}
+	for _, tier := range tiers(challenges) {
+		if difficultyRank(tier) > difficultyRank(l.Curriculum.Tier) {
+			l.Curriculum.Tier = tier
+			return tier
+		}
+	}
+	return ""
+}
+
+// finalTier reports whether the loop trains on the hardest tier present in
+// challenges, or has no curriculum.
+func (l *Loop) finalTier(challenges []challenge.Challenge) bool {
+	if !l.Config.Curriculum.Enabled || l.Curriculum == nil {
+		return true
+	}
+	ts := tiers(challenges)
+	return len(ts) == 0 || difficultyRank(l.Curriculum.Tier) >= difficultyRank(ts[len(ts)-1])
+}
@@ -3,11 +3,17 @@ package training
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"time"
 
 	"github.com/Perttulands/chiron/internal/challenge"
+	"github.com/Perttulands/chiron/internal/diversity"
+	"github.com/Perttulands/chiron/internal/harness"
+	"github.com/Perttulands/chiron/internal/mutation"
+	"github.com/Perttulands/chiron/internal/rating"
 	"github.com/Perttulands/chiron/internal/scoring"
 	"github.com/Perttulands/chiron/internal/selection"
+	"github.com/Perttulands/chiron/internal/state"
 	"github.com/Perttulands/chiron/internal/tournament"
 )
 
@@ -22,12 +28,30 @@ const (
 
 // Config controls the training loop behavior.
 type Config struct {
-	MaxGenerations   int                `json:"max_generations"`
-	SelectionCount   int                `json:"selection_count"` // how many winners to keep
-	SelectionStrategy string            `json:"selection_strategy"`
-	Weights          scoring.Weights    `json:"weights"`
-	TargetScore      float64            `json:"target_score"` // stop if avg score >= this
-	IDFunc           func(string) string `json:"-"`
+	MaxGenerations    int                 `json:"max_generations"`
+	SelectionCount    int                 `json:"selection_count"` // how many winners to keep
+	SelectionStrategy string              `json:"selection_strategy"`
+	Selection         selection.Config    `json:"selection"` // elitism, tournament size, rank pressure, temperature schedule
+	Seed              int64               `json:"seed"`      // drives every RNG in the run and seeds providers that accept one; 0 picks one in NewLoop
+	Diversity         diversity.Config    `json:"diversity"` // prompt distance metric and optional sharing/novelty pressure
+	Weights           scoring.Weights     `json:"weights"`
+	TargetScore       float64             `json:"target_score"` // stop if avg score >= this
+	IDFunc            func(string) string `json:"-"`
+	Grader            harness.Grader      `json:"-"`                           // judges llm_rubric test cases
+	Format            string              `json:"format,omitempty"`            // tournament format; default round_robin
+	SwissRounds       int                 `json:"swiss_rounds,omitempty"`      // rounds for the swiss format
+	Schedule          tournament.Schedule `json:"schedule"`                    // bout concurrency, repetitions, provider caps, progress
+	Ranking           tournament.Ranking  `json:"ranking"`                     // confidence intervals, significance, ties
+	Rating            rating.Config       `json:"rating"`                      // Elo/TrueSkill update parameters
+	OperatorSchedule  string              `json:"operator_schedule,omitempty"` // random (default), ucb1 or thompson; see mutation.Bandit
+	Operators         []string            `json:"operators,omitempty"`         // operators the schedule chooses from; default mutation.AllOperators
+	Providers         []ProviderIdentity  `json:"providers,omitempty"`         // providers the caller runs the loop with, for the manifest and cost projections
+	BudgetUSD         float64             `json:"budget_usd,omitempty"`        // total spend after which the loop stops; 0 is unlimited
+	BudgetTokens      int                 `json:"budget_tokens,omitempty"`     // total tokens after which the loop stops; 0 is unlimited
+	ManifestDir       string              `json:"-"`                           // where RunGeneration writes the run manifest; empty disables it
+	OnRound           func(*Loop)         `json:"-"`                           // called after every round with Partial updated, e.g. to checkpoint mid-generation
+	Review            ReviewConfig        `json:"review"`                      // human review gates between tournament and selection
+	Curriculum        CurriculumConfig    `json:"curriculum"`                  // challenge difficulty schedule; disabled plays every challenge
 }
 
 // DefaultConfig returns sensible training defaults.
@@ -39,6 +63,7 @@ func DefaultConfig(idFunc func(string) string) Config {
 		Weights:           scoring.DefaultWeights(),
 		TargetScore:       9.0,
 		IDFunc:            idFunc,
+		ManifestDir:       DefaultManifestDir,
 	}
 }
 
@@ -50,20 +75,47 @@ type Generation struct {
 	Eliminated  []tournament.Standing   `json:"eliminated"`
 	BestScore   float64                 `json:"best_score"`
 	AvgScore    float64                 `json:"avg_score"`
+	Diversity   diversity.Report        `json:"diversity"` // prompt diversity of the generation's contestants
+	CostUSD     float64                 `json:"cost_usd"`  // spend of the generation and the mutation after it; see RecordSpend
+	Tokens      int                     `json:"tokens"`
+	Objectives  []string                `json:"objectives"`                  // objectives the Pareto front is measured on
+	ParetoFront []selection.ParetoPoint `json:"pareto_front"`                // non-dominated contestants under Objectives
+	Difficulty  map[string]float64      `json:"difficulty_scores,omitempty"` // mean composite score per challenge difficulty
+	Tier        string                  `json:"tier,omitempty"`              // curriculum tier the generation trained on
+	PromotedTo  string                  `json:"promoted_to,omitempty"`       // curriculum tier unlocked by this generation
 	DurationMS  int                     `json:"duration_ms"`
 	CompletedAt string                  `json:"completed_at"`
 }
 
+// PartialGeneration holds the rounds a generation in progress has finished,
+// so a run interrupted mid-tournament resumes from its last round.
+type PartialGeneration struct {
+	Number  int                `json:"number"`
+	Rounds  []tournament.Round `json:"rounds"`
+	CostUSD float64            `json:"cost_usd,omitempty"` // spend of the finished rounds, for the caller to carry into RecordSpend
+	Tokens  int                `json:"tokens,omitempty"`
+}
+
 // Loop represents a complete training run.
 type Loop struct {
-	ID           string                   `json:"id"`
-	Status       string                   `json:"status"`
-	Config       Config                   `json:"config"`
-	Generations  []Generation             `json:"generations"`
-	Contestants  []tournament.Contestant  `json:"contestants"`
-	BestScore    float64                  `json:"best_score"`
-	CreatedAt    string                   `json:"created_at"`
-	CompletedAt  string                   `json:"completed_at,omitempty"`
+	ID          string                  `json:"id"`
+	SessionID   string                  `json:"session_id,omitempty"` // session whose lineages the loop evolves, if any
+	Status      string                  `json:"status"`
+	StopReason  string                  `json:"stop_reason,omitempty"` // why the loop last stopped; see StopBudget
+	Config      Config                  `json:"config"`
+	Generations []Generation            `json:"generations"`
+	Contestants []tournament.Contestant `json:"contestants"`
+	BestScore   float64                 `json:"best_score"`
+	SpentUSD    float64                 `json:"spent_usd"`
+	SpentTokens int                     `json:"spent_tokens"`
+	Ratings     rating.Table            `json:"ratings,omitempty"`   // seed from state.State.Ratings to carry ratings across runs
+	Archive     []string                `json:"archive,omitempty"`   // prompts of eliminated contestants, for novelty search
+	Operators   *mutation.Bandit        `json:"operators,omitempty"` // per-operator score gains, persisted with the loop
+	Partial     *PartialGeneration      `json:"partial,omitempty"`   // the generation in progress, if interrupted or mid-tournament
+	Review      *PendingReview          `json:"review,omitempty"`    // the generation waiting for manual scores, if paused for review
+	Curriculum  *CurriculumState        `json:"curriculum,omitempty"`
+	CreatedAt   string                  `json:"created_at"`
+	CompletedAt string                  `json:"completed_at,omitempty"`
 }
 
 // Mutator generates new contestant variants from winners.
@@ -80,6 +132,22 @@ func NewLoop(cfg Config, contestants []tournament.Contestant) (*Loop, error) {
 	if cfg.SelectionCount <= 0 || cfg.SelectionCount >= len(contestants) {
 		return nil, fmt.Errorf("selection_count must be between 1 and %d", len(contestants)-1)
 	}
+	if _, err := selection.New(cfg.SelectionStrategy, cfg.Selection, nil); err != nil {
+		return nil, err
+	}
+	if err := cfg.Diversity.Validate(); err != nil {
+		return nil, err
+	}
+	operators, err := mutation.NewBandit(cfg.OperatorSchedule, cfg.Operators)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	if cfg.Ranking.Seed == 0 {
+		cfg.Ranking.Seed = cfg.Seed
+	}
 
 	return &Loop{
 		ID:          cfg.IDFunc("loop"),
@@ -87,6 +155,7 @@ func NewLoop(cfg Config, contestants []tournament.Contestant) (*Loop, error) {
 		Config:      cfg,
 		Contestants: contestants,
 		Generations: []Generation{},
+		Operators:   operators,
 		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
 	}, nil
 }
@@ -98,34 +167,59 @@ func (l *Loop) RunGeneration(ctx context.Context, challenges []challenge.Challen
 	}
 
 	l.Status = StatusRunning
+	l.StopReason = ""
 	genNum := len(l.Generations) + 1
 	start := time.Now()
 
-	// Create and run tournament
-	trn, err := tournament.New(tournament.Config{
-		Name:    fmt.Sprintf("Generation %d", genNum),
-		Weights: l.Config.Weights,
-		IDFunc:  l.Config.IDFunc,
-	}, l.Contestants, challenges)
-	if err != nil {
-		l.Status = StatusFailed
-		return nil, fmt.Errorf("create tournament: %w", err)
+	var trn *tournament.Tournament
+	if r := l.Review; r != nil && r.Generation == genNum {
+		// Selection continues on the reviewed tournament.
+		reviewed := r.Tournament
+		trn = &reviewed
+		l.Review = nil
+	} else {
+		played, err := l.playTournament(ctx, genNum, l.curriculumChallenges(challenges), exec)
+		if err != nil {
+			return nil, err
+		}
+		trn = played
+		if trigger := l.reviewTrigger(genNum, trn); trigger != "" {
+			l.pauseForReview(genNum, trigger, trn)
+			return nil, ErrReviewPending
+		}
 	}
 
-	if err := trn.Run(ctx, exec); err != nil {
-		l.Status = StatusFailed
-		return nil, fmt.Errorf("run tournament: %w", err)
+	l.creditOperators(trn)
+
+	// Update persistent ratings
+	if l.Ratings == nil {
+		l.Ratings = rating.Table{}
+	}
+	l.Ratings.UpdateTournament(trn, l.Config.Rating)
+
+	// Measure prompt diversity
+	ids := make([]string, len(l.Contestants))
+	for i, c := range l.Contestants {
+		ids[i] = c.ID
 	}
+	prompts := contestantPrompts(l.Contestants)
+	space := diversity.NewSpace(ids, prompts, l.Archive, l.Config.Diversity)
 
-	// Select winners
-	sel, err := selection.NewSelector(l.Config.SelectionStrategy)
+	// Select winners, under diversity pressure if configured
+	selCfg := l.Config.Selection
+	selCfg.Generation = genNum
+	selCfg.Distance = space.Distance
+	objectives := l.Config.Selection.ObjectiveNames()
+	selCfg.ObjectiveValues = selection.Objectives(trn, objectives)
+	sel, err := selection.New(l.Config.SelectionStrategy, selCfg, l.generationRand(genNum))
 	if err != nil {
 		l.Status = StatusFailed
 		return nil, fmt.Errorf("create selector: %w", err)
 	}
 
-	winners := sel.Select(trn.Standings, l.Config.SelectionCount)
-	_, eliminated := selection.Partition(trn.Standings, winners)
+	adjusted := diversity.Adjust(trn.Standings, space, l.Config.Diversity)
+	winners, eliminated := selection.Partition(trn.Standings, sel.Select(adjusted, l.Config.SelectionCount))
+	l.archive(eliminated, prompts)
 
 	// Compute stats
 	var bestScore, totalScore float64
@@ -144,6 +238,13 @@ func (l *Loop) RunGeneration(ctx context.Context, challenges []challenge.Challen
 		l.BestScore = bestScore
 	}
 
+	tier := ""
+	if l.Curriculum != nil {
+		tier = l.Curriculum.Tier
+	}
+	scores := difficultyScores(trn)
+	promoted := l.advanceCurriculum(trn, challenges, scores)
+
 	gen := Generation{
 		Number:      genNum,
 		Tournament:  *trn,
@@ -151,6 +252,12 @@ func (l *Loop) RunGeneration(ctx context.Context, challenges []challenge.Challen
 		Eliminated:  eliminated,
 		BestScore:   bestScore,
 		AvgScore:    avgScore,
+		Diversity:   space.Report(l.Config.Diversity),
+		Objectives:  objectives,
+		ParetoFront: selection.ParetoFront(trn, objectives),
+		Difficulty:  scores,
+		Tier:        tier,
+		PromotedTo:  promoted,
 		DurationMS:  int(time.Since(start).Milliseconds()),
 		CompletedAt: time.Now().UTC().Format(time.RFC3339),
 	}
@@ -160,17 +267,191 @@ func (l *Loop) RunGeneration(ctx context.Context, challenges []challenge.Challen
 	// Check termination
 	if genNum >= l.Config.MaxGenerations {
 		l.Status = StatusComplete
+		l.StopReason = StopMaxGenerations
 		l.CompletedAt = time.Now().UTC().Format(time.RFC3339)
-	} else if bestScore >= l.Config.TargetScore {
+	} else if bestScore >= l.Config.TargetScore && promoted == "" && l.finalTier(challenges) {
+		// A curriculum reaches its target only on its hardest tier.
 		l.Status = StatusComplete
+		l.StopReason = StopTargetScore
 		l.CompletedAt = time.Now().UTC().Format(time.RFC3339)
 	} else {
 		l.Status = StatusPaused
 	}
 
+	if err := l.WriteManifest(); err != nil {
+		return &gen, fmt.Errorf("generation %d: %w", genNum, err)
+	}
+
 	return &gen, nil
 }
 
+// playTournament plays generation genNum's tournament. Rounds an
+// interrupted run of the generation finished are replayed, and every new
+// round is recorded in Partial as it completes. Partial is rebuilt from the
+// rounds actually replayed, so resumed rounds that no longer match the
+// tournament are not kept.
+func (l *Loop) playTournament(ctx context.Context, genNum int, challenges []challenge.Challenge, exec tournament.Executor) (*tournament.Tournament, error) {
+	var resume []tournament.Round
+	if l.Partial != nil && l.Partial.Number == genNum {
+		resume = l.Partial.Rounds
+	} else {
+		l.Partial = &PartialGeneration{Number: genNum, Rounds: []tournament.Round{}}
+	}
+	sched := l.Config.Schedule
+	sched.RoundReplayed = func(round tournament.Round) {
+		l.Partial.Rounds = append(l.Partial.Rounds, round)
+	}
+	sched.RoundDone = func(round tournament.Round) {
+		l.Partial.Rounds = append(l.Partial.Rounds, round)
+		if l.Config.OnRound != nil {
+			l.Config.OnRound(l)
+		}
+	}
+
+	// Create and run tournament
+	trn, err := tournament.New(tournament.Config{
+		Name:        fmt.Sprintf("Generation %d", genNum),
+		Weights:     l.Config.Weights,
+		IDFunc:      l.Config.IDFunc,
+		Grader:      l.Config.Grader,
+		Schedule:    sched,
+		Ranking:     l.Config.Ranking,
+		Format:      l.Config.Format,
+		SwissRounds: l.Config.SwissRounds,
+		Resume:      resume,
+	}, l.Contestants, challenges)
+	if err != nil {
+		l.Status = StatusFailed
+		return nil, fmt.Errorf("create tournament: %w", err)
+	}
+
+	l.Partial.Rounds = []tournament.Round{}
+	if err := trn.Run(ctx, exec); err != nil {
+		l.Status = StatusFailed
+		if ctx.Err() != nil {
+			// Cancelled: the finished rounds stay in Partial to resume from.
+			l.Status = StatusPaused
+			l.StopReason = StopInterrupted
+		}
+		return nil, fmt.Errorf("run tournament: %w", err)
+	}
+	l.Partial = nil
+
+	return trn, nil
+}
+
+// RecordGeneration stores gen's tournament in st under sessionID, linked to
+// the loop and listing the contestants selection kept. The caller saves st.
+func (l *Loop) RecordGeneration(st *state.State, sessionID string, gen Generation) (state.TournamentRecord, error) {
+	selected := make([]string, 0, len(gen.Winners))
+	for _, w := range gen.Winners {
+		selected = append(selected, w.ContestantID)
+	}
+	return tournament.Record(st, &gen.Tournament, tournament.RecordOptions{
+		SessionID:  sessionID,
+		LoopID:     l.ID,
+		Generation: gen.Number,
+		Selected:   selected,
+	})
+}
+
+// generationRand returns the RNG for one generation, derived from the loop
+// seed and the generation number so a resumed loop makes the same choices.
+func (l *Loop) generationRand(generation int) *rand.Rand {
+	return rand.New(rand.NewSource(l.Config.Seed + int64(generation)*1_000_003))
+}
+
+// MutationRand returns the RNG a mutator should use to produce the
+// contestants of the generation after generation, e.g. for
+// mutation.RandomOperator. It is derived from the loop seed like the
+// selection RNG but is an independent stream.
+func (l *Loop) MutationRand(generation int) *rand.Rand {
+	return rand.New(rand.NewSource(l.Config.Seed + int64(generation)*1_000_003 + 500_009))
+}
+
+// ChooseOperator picks the mutation operator for the next child under the
+// configured schedule. Pass the generation's MutationRand.
+func (l *Loop) ChooseOperator(rng *rand.Rand) string {
+	if l.Operators == nil {
+		// Loops restored from checkpoints written before operator scheduling.
+		l.Operators, _ = mutation.NewBandit(l.Config.OperatorSchedule, l.Config.Operators)
+		if l.Operators == nil {
+			l.Operators, _ = mutation.NewBandit(mutation.ScheduleRandom, nil)
+		}
+	}
+	return l.Operators.Choose(rng)
+}
+
+// creditOperators rewards the operator behind every contestant playing its
+// first generation with its score gain over its primary parent. A parent
+// that survived into trn is compared on trn's challenges; only a parent
+// eliminated earlier falls back to its most recent score, from a tournament
+// that may have played other challenges. Contestants without provenance, or
+// whose parent never played, are skipped.
+func (l *Loop) creditOperators(trn *tournament.Tournament) {
+	if l.Operators == nil {
+		return
+	}
+	played := map[string]bool{}
+	lastScore := map[string]float64{}
+	for _, gen := range l.Generations {
+		agents := map[string]string{}
+		for _, c := range gen.Tournament.Contestants {
+			agents[c.ID] = c.Agent.ID
+			played[c.Agent.ID] = true
+		}
+		for _, s := range gen.Tournament.Standings {
+			if s.BoutsPlayed > 0 {
+				lastScore[agents[s.ContestantID]] = s.AvgScore
+			}
+		}
+	}
+
+	standings := map[string]tournament.Standing{}
+	for _, s := range trn.Standings {
+		standings[s.ContestantID] = s
+	}
+	for _, c := range trn.Contestants {
+		if s := standings[c.ID]; s.BoutsPlayed > 0 {
+			lastScore[c.Agent.ID] = s.AvgScore
+		}
+	}
+	for _, c := range trn.Contestants {
+		meta := c.Agent.GenerationMetadata
+		if played[c.Agent.ID] || meta.Operator == "" || len(meta.ParentIDs) == 0 {
+			continue
+		}
+		parentScore, ok := lastScore[meta.ParentIDs[0]]
+		s := standings[c.ID]
+		if !ok || s.BoutsPlayed == 0 {
+			continue
+		}
+		l.Operators.Record(meta.Operator, s.AvgScore-parentScore)
+	}
+}
+
+// archive remembers eliminated prompts for novelty search, keeping the most
+// recent Diversity.ArchiveLimit().
+func (l *Loop) archive(eliminated []tournament.Standing, prompts map[string]string) {
+	if l.Config.Diversity.Pressure != diversity.PressureNovelty {
+		return
+	}
+	for _, s := range eliminated {
+		l.Archive = append(l.Archive, prompts[s.ContestantID])
+	}
+	if limit := l.Config.Diversity.ArchiveLimit(); len(l.Archive) > limit {
+		l.Archive = append([]string(nil), l.Archive[len(l.Archive)-limit:]...)
+	}
+}
+
+func contestantPrompts(contestants []tournament.Contestant) map[string]string {
+	prompts := make(map[string]string, len(contestants))
+	for _, c := range contestants {
+		prompts[c.ID] = c.Agent.Definition.SystemPrompt
+	}
+	return prompts
+}
+
 // SetContestants replaces the contestant pool (used after mutation).
 func (l *Loop) SetContestants(contestants []tournament.Contestant) {
 	l.Contestants = contestants
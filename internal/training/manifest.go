@@ -0,0 +1,365 @@
+package training
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/Perttulands/chiron/internal/challenge"
+	"github.com/Perttulands/chiron/internal/state"
+	"github.com/Perttulands/chiron/internal/tournament"
+)
+
+// ManifestVersion is the run manifest schema version written by this binary.
+const ManifestVersion = 1
+
+// DefaultManifestDir is where loops write their run manifests by default.
+const DefaultManifestDir = ".chiron/manifests"
+
+// Provider roles in a run manifest.
+const (
+	RoleExecutor = "executor" // plays the bouts
+	RoleGrader   = "grader"   // judges llm_rubric test cases
+	RoleMutator  = "mutator"  // produces the next generation's variants
+)
+
+// ProviderIdentity names one provider a run used. Seeded reports whether the
+// provider accepted the loop seed; unseeded sampling cannot be expected to
+// reproduce.
+type ProviderIdentity struct {
+	Role     string `json:"role"`
+	Provider string `json:"provider"`
+	Model    string `json:"model,omitempty"`
+	BaseURL  string `json:"base_url,omitempty"`
+	Seeded   bool   `json:"seeded"`
+}
+
+// BinaryInfo identifies the chiron build that ran a loop.
+type BinaryInfo struct {
+	Path      string `json:"path"`
+	Version   string `json:"version"`            // module version; "(devel)" for local builds
+	Revision  string `json:"revision,omitempty"` // VCS commit the binary was built from
+	Modified  bool   `json:"modified,omitempty"` // built from a tree with uncommitted changes
+	GoVersion string `json:"go_version"`
+}
+
+func (b BinaryInfo) String() string {
+	s := b.Version
+	if b.Revision != "" {
+		s += " rev " + b.Revision
+	}
+	if b.Modified {
+		s += " (modified)"
+	}
+	return s
+}
+
+// Manifest captures everything needed to re-run a training loop and check
+// whether it reproduces: the binary, the configuration and seed, the
+// challenge set, the providers, and per generation the contestants that
+// played and digests of what they produced.
+type Manifest struct {
+	Version          int                   `json:"version"`
+	LoopID           string                `json:"loop_id"`
+	SessionID        string                `json:"session_id,omitempty"` // session the loop bred its children into
+	Binary           BinaryInfo            `json:"binary"`
+	Seed             int64                 `json:"seed"`
+	Config           Config                `json:"config"`
+	ChallengeSetHash string                `json:"challenge_set_hash"`
+	Challenges       []challenge.Challenge `json:"challenges"`
+	Providers        []ProviderIdentity    `json:"providers"`
+	Generations      []ManifestGeneration  `json:"generations"`
+	CreatedAt        string                `json:"created_at"`
+	UpdatedAt        string                `json:"updated_at"`
+}
+
+// ManifestGeneration records one generation's inputs and a digest of its
+// results.
+type ManifestGeneration struct {
+	Number       int                     `json:"number"`
+	TournamentID string                  `json:"tournament_id"`
+	ChallengeIDs []string                `json:"challenge_ids"`
+	Contestants  []tournament.Contestant `json:"contestants"`
+	Winners      []string                `json:"winners"` // contestant ids kept by selection
+	BestScore    float64                 `json:"best_score"`
+	AvgScore     float64                 `json:"avg_score"`
+	Bouts        []BoutDigest            `json:"bouts"`
+}
+
+// BoutDigest identifies one bout's output by hash.
+type BoutDigest struct {
+	ContestantID string  `json:"contestant_id"`
+	ChallengeID  string  `json:"challenge_id"`
+	Repetition   int     `json:"repetition,omitempty"`
+	OutputHash   string  `json:"output_hash"`
+	Score        float64 `json:"score"`
+	ManualScore  *int    `json:"manual_score,omitempty"` // reviewer score folded into Score by a review gate
+	ErrorKind    string  `json:"error_kind,omitempty"`
+}
+
+// ManualScore returns the reviewer score recorded for bout, if any. It
+// satisfies the lookup of tournament.ApplyBoutScores.
+func (g ManifestGeneration) ManualScore(bout tournament.Bout) (int, bool) {
+	for _, d := range g.Bouts {
+		if d.ContestantID == bout.ContestantID && d.ChallengeID == bout.ChallengeID && d.Repetition == bout.Repetition && d.ManualScore != nil {
+			return *d.ManualScore, true
+		}
+	}
+	return 0, false
+}
+
+// Manifest builds the loop's run manifest from its generations so far.
+func (l *Loop) Manifest() Manifest {
+	m := Manifest{
+		Version:     ManifestVersion,
+		LoopID:      l.ID,
+		SessionID:   l.SessionID,
+		Binary:      CurrentBinary(),
+		Seed:        l.Config.Seed,
+		Config:      l.Config,
+		Challenges:  []challenge.Challenge{},
+		Providers:   l.Config.Providers,
+		Generations: make([]ManifestGeneration, 0, len(l.Generations)),
+		CreatedAt:   l.CreatedAt,
+		UpdatedAt:   time.Now().UTC().Format(time.RFC3339),
+	}
+	if m.Providers == nil {
+		m.Providers = []ProviderIdentity{}
+	}
+
+	seen := map[string]bool{}
+	for _, gen := range l.Generations {
+		mg := ManifestGeneration{
+			Number:       gen.Number,
+			TournamentID: gen.Tournament.ID,
+			ChallengeIDs: make([]string, 0, len(gen.Tournament.Challenges)),
+			Contestants:  gen.Tournament.Contestants,
+			Winners:      make([]string, 0, len(gen.Winners)),
+			BestScore:    gen.BestScore,
+			AvgScore:     gen.AvgScore,
+			Bouts:        boutDigests(gen.Tournament.Rounds),
+		}
+		for _, ch := range gen.Tournament.Challenges {
+			mg.ChallengeIDs = append(mg.ChallengeIDs, ch.ID)
+			if !seen[ch.ID] {
+				seen[ch.ID] = true
+				m.Challenges = append(m.Challenges, ch)
+			}
+		}
+		for _, w := range gen.Winners {
+			mg.Winners = append(mg.Winners, w.ContestantID)
+		}
+		m.Generations = append(m.Generations, mg)
+	}
+	m.ChallengeSetHash = ChallengeSetHash(m.Challenges)
+	return m
+}
+
+// ManifestPath returns where the loop writes its manifest, or "" when
+// manifests are disabled.
+func (l *Loop) ManifestPath() string {
+	if l.Config.ManifestDir == "" {
+		return ""
+	}
+	return filepath.Join(l.Config.ManifestDir, l.ID+".json")
+}
+
+// WriteManifest writes the loop's manifest to ManifestPath.
+func (l *Loop) WriteManifest() error {
+	path := l.ManifestPath()
+	if path == "" {
+		return nil
+	}
+	return SaveManifest(path, l.Manifest())
+}
+
+// SaveManifest writes m as indented JSON to path.
+func SaveManifest(path string, m Manifest) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create manifest directory: %w", err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	return nil
+}
+
+// LoadManifest reads a run manifest and verifies its challenge set hash.
+func LoadManifest(path string) (Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("read manifest %q: %w", path, err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Manifest{}, fmt.Errorf("decode manifest %q: %w", path, err)
+	}
+	if m.Version > ManifestVersion {
+		return Manifest{}, fmt.Errorf("manifest %q has version %d; this binary reads up to %d", path, m.Version, ManifestVersion)
+	}
+	if hash := ChallengeSetHash(m.Challenges); hash != m.ChallengeSetHash {
+		return Manifest{}, fmt.Errorf("manifest %q: challenge set hash %s does not match its challenges (%s)", path, m.ChallengeSetHash, hash)
+	}
+	return m, nil
+}
+
+// ChallengeSetHash returns a SHA-256 over the challenges' JSON encoding.
+func ChallengeSetHash(challenges []challenge.Challenge) string {
+	data, err := json.Marshal(challenges)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// CurrentBinary describes the running binary from its build information.
+func CurrentBinary() BinaryInfo {
+	info := BinaryInfo{Version: "unknown", GoVersion: runtime.Version()}
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.Path = build.Main.Path
+	info.Version = build.Main.Version
+	for _, s := range build.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Revision = s.Value
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+	return info
+}
+
+func boutDigests(rounds []tournament.Round) []BoutDigest {
+	digests := []BoutDigest{}
+	for _, round := range rounds {
+		for _, b := range round.Bouts {
+			sum := sha256.Sum256([]byte(b.Output))
+			d := BoutDigest{
+				ContestantID: b.ContestantID,
+				ChallengeID:  b.ChallengeID,
+				Repetition:   b.Repetition,
+				OutputHash:   hex.EncodeToString(sum[:]),
+				Score:        b.CompositeScore.FinalScore,
+				ErrorKind:    b.ErrorKind,
+			}
+			for _, c := range b.CompositeScore.Components {
+				if c.Name == "manual" && c.Available {
+					manual := c.RawScore
+					d.ManualScore = &manual
+				}
+			}
+			digests = append(digests, d)
+		}
+	}
+	return digests
+}
+
+// ReplayResult compares a replayed generation with its manifest record.
+type ReplayResult struct {
+	Number           int      `json:"number"`
+	Bouts            int      `json:"bouts"`            // bouts in the recorded generation
+	MatchingOutputs  int      `json:"matching_outputs"` // replayed bouts whose output hash matches
+	MissingBouts     int      `json:"missing_bouts"`    // recorded bouts the replay did not play
+	MaxScoreDelta    float64  `json:"max_score_delta"`  // largest composite score difference over matched bouts
+	Winners          []string `json:"winners"`
+	ReplayedWinners  []string `json:"replayed_winners"`
+	WinnersMatch     bool     `json:"winners_match"`
+	Children         int      `json:"children"`          // contestants the recorded loop bred from this generation
+	MatchingChildren int      `json:"matching_children"` // replayed children whose agent definition hash matches one recorded
+	Reproduced       bool     `json:"reproduced"`        // every output, the selection and the bred children matched
+}
+
+// CompareGeneration checks a replayed generation against its record.
+// Composite scores include latency, so they are reported as a delta rather
+// than required to match.
+func CompareGeneration(recorded ManifestGeneration, replayed Generation) ReplayResult {
+	result := ReplayResult{
+		Number:          recorded.Number,
+		Bouts:           len(recorded.Bouts),
+		Winners:         recorded.Winners,
+		ReplayedWinners: make([]string, 0, len(replayed.Winners)),
+	}
+	for _, w := range replayed.Winners {
+		result.ReplayedWinners = append(result.ReplayedWinners, w.ContestantID)
+	}
+
+	type boutKey struct {
+		contestant, challenge string
+		repetition            int
+	}
+	played := map[boutKey]BoutDigest{}
+	for _, d := range boutDigests(replayed.Tournament.Rounds) {
+		played[boutKey{d.ContestantID, d.ChallengeID, d.Repetition}] = d
+	}
+	for _, want := range recorded.Bouts {
+		got, ok := played[boutKey{want.ContestantID, want.ChallengeID, want.Repetition}]
+		if !ok {
+			result.MissingBouts++
+			continue
+		}
+		if got.OutputHash == want.OutputHash {
+			result.MatchingOutputs++
+		}
+		if delta := math.Abs(got.Score - want.Score); delta > result.MaxScoreDelta {
+			result.MaxScoreDelta = delta
+		}
+	}
+
+	result.WinnersMatch = len(result.Winners) == len(result.ReplayedWinners)
+	for i := 0; result.WinnersMatch && i < len(result.Winners); i++ {
+		result.WinnersMatch = result.Winners[i] == result.ReplayedWinners[i]
+	}
+	result.Reproduced = result.WinnersMatch && result.MissingBouts == 0 && result.MatchingOutputs == result.Bouts
+	return result
+}
+
+// CompareChildren checks the children a replay bred from a generation
+// played by parents against the ones the loop recorded for the next
+// generation. Children are the contestants not carried over from parents;
+// child ids are minted afresh, so children match by the hash of their agent
+// definition, each recorded child matching at most one replayed child.
+func (r *ReplayResult) CompareChildren(parents, recorded, replayed []tournament.Contestant) {
+	carried := map[string]bool{}
+	for _, c := range parents {
+		carried[c.ID] = true
+	}
+	want := map[string]int{}
+	for _, c := range recorded {
+		if !carried[c.ID] {
+			want[definitionHash(c.Agent.Definition)]++
+			r.Children++
+		}
+	}
+	for _, c := range replayed {
+		if h := definitionHash(c.Agent.Definition); !carried[c.ID] && want[h] > 0 {
+			want[h]--
+			r.MatchingChildren++
+		}
+	}
+	r.Reproduced = r.Reproduced && r.MatchingChildren == r.Children
+}
+
+// definitionHash returns a SHA-256 over an agent definition's JSON encoding.
+func definitionHash(def state.AgentDefinition) string {
+	data, err := json.Marshal(def)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
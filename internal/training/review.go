@@ -0,0 +1,110 @@
+package training
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Perttulands/chiron/internal/tournament"
+)
+
+// StopReview pauses a loop for human review of a generation's tournament.
+const StopReview = "review"
+
+// Review triggers, recorded in PendingReview.Trigger.
+const (
+	ReviewScheduled = "scheduled" // every ReviewConfig.Every generations
+	ReviewNewBest   = "new_best"  // a contestant beat the loop's best score
+)
+
+// DefaultReviewTop is how many top contestants a review presents.
+const DefaultReviewTop = 3
+
+// ErrReviewPending is returned by RunGeneration when a review gate paused
+// the loop after its tournament and before selection.
+var ErrReviewPending = errors.New("generation awaits review")
+
+// ReviewConfig sets when a loop pauses for human review. The zero value
+// never pauses.
+type ReviewConfig struct {
+	Every     int  `json:"every,omitempty"`       // pause after every Every generations; 0 disables
+	OnNewBest bool `json:"on_new_best,omitempty"` // pause when a contestant beats the best score of earlier generations
+	Top       int  `json:"top,omitempty"`         // contestants presented for review; default DefaultReviewTop
+}
+
+// TopN returns how many contestants a review presents.
+func (r ReviewConfig) TopN() int {
+	if r.Top <= 0 {
+		return DefaultReviewTop
+	}
+	return r.Top
+}
+
+// PendingReview is a generation whose tournament has been played and is
+// waiting for manual scores before selection.
+type PendingReview struct {
+	Generation    int                   `json:"generation"`
+	Trigger       string                `json:"trigger"`
+	ContestantIDs []string              `json:"contestant_ids"` // top contestants presented for review, best first
+	Tournament    tournament.Tournament `json:"tournament"`
+	Applied       int                   `json:"applied,omitempty"`  // bouts rescored by ApplyReview
+	CostUSD       float64               `json:"cost_usd,omitempty"` // spend of the tournament, for the caller to carry into RecordSpend
+	Tokens        int                   `json:"tokens,omitempty"`
+}
+
+// reviewTrigger reports which gate, if any, pauses generation genNum after
+// trn. A generation resumed from review is never paused again.
+func (l *Loop) reviewTrigger(genNum int, trn *tournament.Tournament) string {
+	cfg := l.Config.Review
+	if cfg.Every > 0 && genNum%cfg.Every == 0 {
+		return ReviewScheduled
+	}
+	if cfg.OnNewBest && len(l.Generations) > 0 {
+		for _, s := range trn.Standings {
+			if s.BoutsPlayed > 0 && s.AvgScore > l.BestScore {
+				return ReviewNewBest
+			}
+		}
+	}
+	return ""
+}
+
+// pauseForReview holds trn for review and pauses the loop.
+func (l *Loop) pauseForReview(genNum int, trigger string, trn *tournament.Tournament) {
+	ids := []string{}
+	for _, s := range trn.TopN(l.Config.Review.TopN()) {
+		ids = append(ids, s.ContestantID)
+	}
+	l.Review = &PendingReview{Generation: genNum, Trigger: trigger, ContestantIDs: ids, Tournament: *trn}
+	l.Status = StatusPaused
+	l.StopReason = StopReview
+}
+
+// ApplyReview folds manual scores, 1-10 keyed by artifact id, into the
+// pending review's tournament; the next RunGeneration selects on the
+// rescored standings. Bouts must have been stored as artifacts, e.g. with
+// tournament.RecordBouts. It returns how many bouts were rescored.
+func (l *Loop) ApplyReview(scores map[string]int) (int, error) {
+	if l.Review == nil {
+		return 0, fmt.Errorf("loop %q has no pending review", l.ID)
+	}
+	return l.applyReview(func() (int, error) { return tournament.ApplyManualScores(&l.Review.Tournament, scores) })
+}
+
+// ApplyRecordedReview folds the manual scores a run manifest recorded for
+// the pending review's generation into its tournament, matching bouts by
+// contestant, challenge and repetition. Replays use it in place of a human.
+func (l *Loop) ApplyRecordedReview(recorded ManifestGeneration) (int, error) {
+	if l.Review == nil {
+		return 0, fmt.Errorf("loop %q has no pending review", l.ID)
+	}
+	return l.applyReview(func() (int, error) { return tournament.ApplyBoutScores(&l.Review.Tournament, recorded.ManualScore) })
+}
+
+func (l *Loop) applyReview(apply func() (int, error)) (int, error) {
+	applied, err := apply()
+	if err != nil {
+		return 0, fmt.Errorf("apply review of generation %d: %w", l.Review.Generation, err)
+	}
+	l.Review.Applied = applied
+	return applied, nil
+}